@@ -0,0 +1,178 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Canonicalize serializa un árbol de valores según JSON Canonicalization
+// Scheme (RFC 8785): sin espacios, claves ordenadas por unidades UTF-16,
+// números con el formato de ECMAScript y escape mínimo de strings. Dos
+// documentos con el mismo contenido producen exactamente los mismos bytes.
+func Canonicalize(value interface{}) ([]byte, error) {
+	state := &encodeState{}
+	if err := state.appendCanonical(value); err != nil {
+		return nil, err
+	}
+	return state.buf, nil
+}
+
+// CanonicalDigest devuelve la forma canónica y su SHA-256 en hexadecimal
+func CanonicalDigest(value interface{}) ([]byte, string, error) {
+	canonical, err := Canonicalize(value)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return canonical, hex.EncodeToString(sum[:]), nil
+}
+
+// appendCanonical serializa un valor en forma canónica
+func (e *encodeState) appendCanonical(value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}, *OrderedObject:
+		values := objectValues(v)
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+
+		e.buf = append(e.buf, '{')
+		for i, key := range keys {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			e.appendString(key)
+			e.buf = append(e.buf, ':')
+			if err := e.appendCanonical(values[key]); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, '}')
+	case []interface{}:
+		e.buf = append(e.buf, '[')
+		for i, item := range v {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			if err := e.appendCanonical(item); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, ']')
+	case float64, int64, int, Number, json.Number:
+		f, err := canonicalFloat(v)
+		if err != nil {
+			return err
+		}
+		formatted, err := formatECMAScriptNumber(f)
+		if err != nil {
+			return err
+		}
+		e.buf = append(e.buf, formatted...)
+	default:
+		return e.appendValue(value, 0)
+	}
+	return nil
+}
+
+// canonicalFloat lleva cualquier representación numérica a float64: JCS
+// trabaja sobre números IEEE 754 de doble precisión (I-JSON)
+func canonicalFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case Number:
+		return parseCanonicalLiteral(string(v))
+	case json.Number:
+		return parseCanonicalLiteral(string(v))
+	}
+	return 0, fmt.Errorf("tipo no numérico: %T", value)
+}
+
+// parseCanonicalLiteral convierte un literal rechazando los que exceden el rango de float64
+func parseCanonicalLiteral(literal string) (float64, error) {
+	f, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return 0, fmt.Errorf("número fuera del rango de IEEE 754 (requerido por JCS): %s", literal)
+	}
+	return f, nil
+}
+
+// formatECMAScriptNumber reproduce Number.prototype.toString de ECMAScript:
+// el decimal más corto que identifica al double, en notación fija para
+// exponentes entre -7 y 20 y científica fuera de ese rango
+func formatECMAScriptNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("número no representable en JSON: %v", f)
+	}
+	if f == 0 {
+		return "0", nil // también -0
+	}
+
+	var sb strings.Builder
+	if f < 0 {
+		sb.WriteByte('-')
+		f = -f
+	}
+
+	// Dígitos significativos más cortos y exponente decimal: d.ddd e±x
+	scientific := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exponent, _ := strings.Cut(scientific, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	exp, _ := strconv.Atoi(exponent)
+
+	k := len(digits)
+	n := exp + 1 // posición del punto decimal respecto de los dígitos
+	switch {
+	case k <= n && n <= 21:
+		sb.WriteString(digits)
+		sb.WriteString(strings.Repeat("0", n-k))
+	case 0 < n && n <= 21:
+		sb.WriteString(digits[:n])
+		sb.WriteByte('.')
+		sb.WriteString(digits[n:])
+	case -6 < n && n <= 0:
+		sb.WriteString("0.")
+		sb.WriteString(strings.Repeat("0", -n))
+		sb.WriteString(digits)
+	default:
+		sb.WriteByte(digits[0])
+		if k > 1 {
+			sb.WriteByte('.')
+			sb.WriteString(digits[1:])
+		}
+		sb.WriteByte('e')
+		if n-1 >= 0 {
+			sb.WriteByte('+')
+		}
+		sb.WriteString(strconv.Itoa(n - 1))
+	}
+	return sb.String(), nil
+}
+
+// lessUTF16 compara strings por sus unidades de código UTF-16, el orden
+// que exige JCS (difiere del orden por bytes fuera del BMP)
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
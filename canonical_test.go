@@ -0,0 +1,124 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// Test con los valores de referencia del apéndice B de RFC 8785
+func TestFormatECMAScriptNumber(t *testing.T) {
+	tests := []struct {
+		bits uint64
+		want string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got, err := formatECMAScriptNumber(math.Float64frombits(tt.bits))
+			if err != nil {
+				t.Fatalf("formatECMAScriptNumber() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("formatECMAScriptNumber(%016x) = %s, want %s", tt.bits, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := formatECMAScriptNumber(math.NaN()); err == nil {
+		t.Error("formatECMAScriptNumber(NaN) debería fallar")
+	}
+}
+
+// Test con los ejemplos de RFC 8785 (sección 3.2.2 y orden de claves 3.2.3)
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"ejemplo de la RFC",
+			`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			"orden UTF-16 de claves",
+			`{"\u20ac": "Euro", "\r": "CR", "\ufb33": "Hebrew", "1": "One", "\ud83d\ude00": "Smiley", "\u0080": "Control", "\u00f6": "Latin"}`,
+			"{\"\\r\":\"CR\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin\",\"€\":\"Euro\",\"😀\":\"Smiley\",\"\ufb33\":\"Hebrew\"}",
+		},
+		{"anidado y vacío", `{"b": [], "a": {"d": {}, "c": -0}}`, `{"a":{"c":0,"d":{}},"b":[]}`},
+		{"escalar", `  "texto"  `, `"texto"`},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, opts := range []ParseOptions{{}, {PreserveOrder: true, NumberMode: NumberAsNumber}, {NumberMode: NumberAsInt64}} {
+				value, err := parser.ParseJSONWithOptions(tt.input, opts)
+				if err != nil {
+					t.Fatalf("ParseJSONWithOptions() error = %v", err)
+				}
+				got, err := Canonicalize(value)
+				if err != nil {
+					t.Fatalf("Canonicalize() error = %v", err)
+				}
+				if string(got) != tt.want {
+					t.Errorf("Canonicalize(%+v) = %s, want %s", opts, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// Test para el digest: documentos equivalentes producen el mismo hash
+func TestCanonicalDigest(t *testing.T) {
+	parser := NewParser()
+	a, _ := parser.ParseJSON(`{"id": 1.0, "tags": ["x"], "name": "Ana"}`)
+	b, _ := parser.ParseJSON("{\n\t\"name\": \"Ana\",\n\t\"tags\": [\"x\"],\n\t\"id\": 1e0\n}")
+
+	_, digestA, err := CanonicalDigest(a)
+	if err != nil {
+		t.Fatalf("CanonicalDigest() error = %v", err)
+	}
+	_, digestB, _ := CanonicalDigest(b)
+	if digestA != digestB {
+		t.Errorf("CanonicalDigest() = %s y %s, se esperaba el mismo hash", digestA, digestB)
+	}
+	if len(digestA) != 64 {
+		t.Errorf("CanonicalDigest() longitud = %d, want 64", len(digestA))
+	}
+
+	if _, err := Canonicalize(Number("1e400")); err == nil || !strings.Contains(err.Error(), "fuera del rango") {
+		t.Errorf("Canonicalize(1e400) error = %v", err)
+	}
+}
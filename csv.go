@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSVOptions dialecto del CSV; los valores cero usan ',' y '"'
+type CSVOptions struct {
+	Delimiter rune
+	Quote     rune
+}
+
+// withDefaults completa los caracteres no indicados
+func (opts CSVOptions) withDefaults() CSVOptions {
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	if opts.Quote == 0 {
+		opts.Quote = '"'
+	}
+	return opts
+}
+
+// validate verifica que el dialecto se pueda leer sin ambigüedades
+func (opts CSVOptions) validate() error {
+	opts = opts.withDefaults()
+	for _, r := range []rune{opts.Delimiter, opts.Quote} {
+		if r == '\n' || r == '\r' || r == utf8.RuneError {
+			return fmt.Errorf("el carácter %q no se puede usar como separador ni comilla", r)
+		}
+	}
+	if opts.Delimiter == opts.Quote {
+		return fmt.Errorf("el separador y la comilla deben ser distintos")
+	}
+	return nil
+}
+
+// ParseCSVChar interpreta el valor de un campo de formulario como carácter
+// del dialecto: vacío usa el valor por defecto y "\t" o "tab" es el tabulador
+func ParseCSVChar(value string) (rune, error) {
+	switch value {
+	case "":
+		return 0, nil
+	case `\t`, "tab":
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) {
+		return 0, fmt.Errorf("se esperaba un único carácter, se recibió '%s'", value)
+	}
+	return r, nil
+}
+
+// CSVError error de lectura con la línea donde ocurrió
+type CSVError struct {
+	Line int    `json:"line"`
+	Msg  string `json:"message"`
+}
+
+// Error implementa la interfaz error
+func (e *CSVError) Error() string {
+	return fmt.Sprintf("CSV inválido en la línea %d: %s", e.Line, e.Msg)
+}
+
+// AsCSVError extrae un *CSVError de la cadena de errores, o nil
+func AsCSVError(err error) *CSVError {
+	var csvErr *CSVError
+	if errors.As(err, &csvErr) {
+		return csvErr
+	}
+	return nil
+}
+
+// ReadCSV lee todos los registros de content según RFC 4180 con el dialecto
+// indicado: los campos entre comillas pueden contener separadores, saltos de
+// línea y comillas duplicadas; se aceptan finales de línea \n y \r\n, se
+// ignoran las líneas vacías y el BOM inicial. Todos los registros deben tener
+// la misma cantidad de campos que el primero.
+func ReadCSV(content string, opts CSVOptions) ([][]string, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+	content = strings.TrimPrefix(content, "\uFEFF")
+
+	var records [][]string
+	var record []string
+	var field strings.Builder
+	line, recordLine := 1, 1
+	quoted, inQuotes := false, false
+
+	endField := func() {
+		record = append(record, field.String())
+		field.Reset()
+		quoted = false
+	}
+	endRecord := func() error {
+		wasQuoted := quoted
+		endField()
+		// Una línea vacía no es un registro
+		if len(record) == 1 && record[0] == "" && !wasQuoted {
+			record = nil
+			return nil
+		}
+		if len(records) > 0 && len(record) != len(records[0]) {
+			return &CSVError{Line: recordLine, Msg: fmt.Sprintf("el registro tiene %d campos, se esperaban %d", len(record), len(records[0]))}
+		}
+		records = append(records, record)
+		record = nil
+		return nil
+	}
+
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRuneInString(content[i:])
+		i += size
+
+		if inQuotes {
+			switch {
+			case r == opts.Quote && strings.HasPrefix(content[i:], string(opts.Quote)):
+				field.WriteRune(r)
+				i += size
+			case r == opts.Quote:
+				inQuotes = false
+				next, _ := utf8.DecodeRuneInString(content[i:])
+				if i < len(content) && next != opts.Delimiter && next != '\n' && next != '\r' {
+					return nil, &CSVError{Line: line, Msg: fmt.Sprintf("carácter %q después de la comilla de cierre", next)}
+				}
+			default:
+				if r == '\n' {
+					line++
+				}
+				field.WriteRune(r)
+			}
+			continue
+		}
+
+		switch {
+		case r == opts.Quote && field.Len() == 0 && !quoted:
+			inQuotes, quoted = true, true
+		case r == opts.Quote:
+			return nil, &CSVError{Line: line, Msg: "comilla dentro de un campo sin comillas"}
+		case r == opts.Delimiter:
+			endField()
+		case r == '\r' && strings.HasPrefix(content[i:], "\n"):
+			// El \n siguiente cierra el registro
+		case r == '\n':
+			if err := endRecord(); err != nil {
+				return nil, err
+			}
+			line++
+			recordLine = line
+		default:
+			field.WriteRune(r)
+		}
+	}
+
+	if inQuotes {
+		return nil, &CSVError{Line: line, Msg: "campo entre comillas sin cerrar"}
+	}
+	if field.Len() > 0 || quoted || len(record) > 0 {
+		if err := endRecord(); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// WriteCSV escribe los registros según RFC 4180 con el dialecto indicado:
+// los campos con separador, comillas, saltos de línea o BOM inicial van entre
+// comillas (duplicando las internas) y cada registro termina en \r\n. Un
+// registro con un único campo vacío se escribe como "" para que ReadCSV no lo
+// descarte.
+func WriteCSV(records [][]string, opts CSVOptions) (string, error) {
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+	opts = opts.withDefaults()
+	quote := string(opts.Quote)
+
+	var builder strings.Builder
+	for _, record := range records {
+		for i, field := range record {
+			if i > 0 {
+				builder.WriteRune(opts.Delimiter)
+			}
+			needsQuotes := strings.ContainsRune(field, opts.Delimiter) || strings.Contains(field, quote) ||
+				strings.ContainsAny(field, "\r\n") || strings.HasPrefix(field, "\uFEFF") || (len(record) == 1 && field == "")
+			if !needsQuotes {
+				builder.WriteString(field)
+				continue
+			}
+			builder.WriteString(quote + strings.ReplaceAll(field, quote, quote+quote) + quote)
+		}
+		builder.WriteString("\r\n")
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test para la lectura de CSV con distintos dialectos
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  CSVOptions
+		want  [][]string
+	}{
+		{"simple", "a,b\n1,2\n", CSVOptions{}, [][]string{{"a", "b"}, {"1", "2"}}},
+		{"sin salto final y CRLF", "a,b\r\n1,2", CSVOptions{}, [][]string{{"a", "b"}, {"1", "2"}}},
+		{"comillas", "a,b\n\"x,y\",\"di \"\"hola\"\"\"\n", CSVOptions{}, [][]string{{"a", "b"}, {"x,y", `di "hola"`}}},
+		{"salto dentro de comillas", "a\n\"uno\r\ndos\"\n", CSVOptions{}, [][]string{{"a"}, {"uno\r\ndos"}}},
+		{"líneas vacías y BOM", "\uFEFFa\n\n1\n\n", CSVOptions{}, [][]string{{"a"}, {"1"}}},
+		{"campo vacío entre comillas", "a\n\"\"\n", CSVOptions{}, [][]string{{"a"}, {""}}},
+		{"punto y coma y comilla simple", "a;b\n'x;y';2\n", CSVOptions{Delimiter: ';', Quote: '\''}, [][]string{{"a", "b"}, {"x;y", "2"}}},
+		{"tabulador", "a\tb\n1\t\n", CSVOptions{Delimiter: '\t'}, [][]string{{"a", "b"}, {"1", ""}}},
+		{"vacío", "", CSVOptions{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadCSV(tt.input, tt.opts)
+			if err != nil {
+				t.Fatalf("ReadCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test para los errores de lectura
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  CSVOptions
+		want  string
+	}{
+		{"campos de más", "a,b\n1,2\n3,4,5\n", CSVOptions{}, "CSV inválido en la línea 3: el registro tiene 3 campos, se esperaban 2"},
+		{"comillas sin cerrar", "a\n\"abierto\nsigue\n", CSVOptions{}, "CSV inválido en la línea 4: campo entre comillas sin cerrar"},
+		{"texto tras la comilla", "a\n\"x\"y\n", CSVOptions{}, "CSV inválido en la línea 2: carácter 'y' después de la comilla de cierre"},
+		{"comilla suelta", "a\nx\"y\n", CSVOptions{}, "CSV inválido en la línea 2: comilla dentro de un campo sin comillas"},
+		{"dialecto ambiguo", "a", CSVOptions{Delimiter: '"'}, "el separador y la comilla deben ser distintos"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCSV(tt.input, tt.opts)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ReadCSV() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// Test para los valores de los campos delimiter y quote
+func TestParseCSVChar(t *testing.T) {
+	tests := map[string]rune{"": 0, ";": ';', `\t`: '\t', "tab": '\t', "|": '|', "¦": '¦'}
+	for input, want := range tests {
+		if got, err := ParseCSVChar(input); err != nil || got != want {
+			t.Errorf("ParseCSVChar(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseCSVChar(";;"); err == nil {
+		t.Error("se esperaba error para más de un carácter")
+	}
+}
+
+// Test: WriteCSV escribe registros que ReadCSV lee sin cambios
+func TestWriteCSV(t *testing.T) {
+	records := [][]string{
+		{"id", "texto", "vacío"},
+		{"1", "con, coma", ""},
+		{"2", "di \"hola\"\nen dos líneas", "\uFEFFbom"},
+	}
+	got, err := WriteCSV(records, CSVOptions{})
+	if err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "id,texto,vacío\r\n1,\"con, coma\",\r\n2,\"di \"\"hola\"\"\nen dos líneas\",\"\uFEFFbom\"\r\n"
+	if got != want {
+		t.Errorf("WriteCSV() = %q, want %q", got, want)
+	}
+
+	for _, opts := range []CSVOptions{{}, {Delimiter: ';', Quote: '\''}, {Delimiter: '\t'}} {
+		input := append(records, []string{"a;b\t'c'", "", "x"})
+		text, err := WriteCSV(input, opts)
+		if err != nil {
+			t.Fatalf("WriteCSV(%+v) error = %v", opts, err)
+		}
+		back, err := ReadCSV(text, opts)
+		if err != nil || !reflect.DeepEqual(back, input) {
+			t.Errorf("ReadCSV(WriteCSV(%+v)) = %q, %v", opts, back, err)
+		}
+	}
+
+	single, _ := WriteCSV([][]string{{"a"}, {""}}, CSVOptions{})
+	if back, _ := ReadCSV(single, CSVOptions{}); len(back) != 2 {
+		t.Errorf("el registro con un campo vacío se perdió: %q", single)
+	}
+	if _, err := WriteCSV(records, CSVOptions{Delimiter: '"'}); err == nil {
+		t.Error("se esperaba error con separador igual a la comilla")
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tipos de columna que reconoce el conversor CSV, del más al menos específico
+const (
+	csvInt    = "int64"
+	csvFloat  = "float64"
+	csvBool   = "bool"
+	csvTime   = "time.Time"
+	csvString = "string"
+)
+
+var (
+	// csvIntPattern y csvFloatPattern siguen la gramática numérica de JSON:
+	// "007" o "1." quedan como texto para no perder ceros ni formato
+	csvIntPattern   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	csvFloatPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+)
+
+// csvTimeLayouts formatos de fecha reconocidos, en orden de preferencia
+var csvTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// csvColumn una columna del CSV con su campo Go y el tipo inferido
+type csvColumn struct {
+	header string
+	field  string
+	kind   string
+	layout string // formato de las fechas (kind == csvTime)
+}
+
+// inferCSVColumns elige para cada columna el tipo más específico que acepta
+// todas sus celdas no vacías; una columna sin valores queda como string
+func inferCSVColumns(header []string, rows [][]string) []csvColumn {
+	columns := make([]csvColumn, len(header))
+	fields := map[string]bool{}
+	for i, name := range header {
+		field := exportedGoName(name)
+		if field == "" {
+			field = fmt.Sprintf("Column%d", i+1)
+		}
+		columns[i] = csvColumn{header: name, field: uniqueGoName(field, fields), kind: csvString}
+
+		var cells []string
+		for _, row := range rows {
+			if row[i] != "" {
+				cells = append(cells, row[i])
+			}
+		}
+		if len(cells) == 0 {
+			continue
+		}
+		switch {
+		case allCells(cells, csvIntPattern.MatchString) && allCells(cells, fitsInt64):
+			columns[i].kind = csvInt
+		case allCells(cells, csvFloatPattern.MatchString) && allCells(cells, fitsFloat64):
+			columns[i].kind = csvFloat
+		case allCells(cells, isCSVBool):
+			columns[i].kind = csvBool
+		default:
+			for _, layout := range csvTimeLayouts {
+				if allCells(cells, func(cell string) bool { _, err := time.Parse(layout, cell); return err == nil }) {
+					columns[i].kind, columns[i].layout = csvTime, layout
+					break
+				}
+			}
+		}
+	}
+	return columns
+}
+
+func allCells(cells []string, accept func(string) bool) bool {
+	for _, cell := range cells {
+		if !accept(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+func fitsInt64(cell string) bool {
+	_, err := strconv.ParseInt(cell, 10, 64)
+	return err == nil
+}
+
+// fitsFloat64 descarta valores que se desbordan a ±Inf (1e400)
+func fitsFloat64(cell string) bool {
+	_, err := strconv.ParseFloat(cell, 64)
+	return err == nil
+}
+
+func isCSVBool(cell string) bool {
+	return strings.EqualFold(cell, "true") || strings.EqualFold(cell, "false")
+}
+
+// csvCellLiteral escribe una celda como literal Go del tipo de su columna
+func csvCellLiteral(column csvColumn, cell string) string {
+	switch column.kind {
+	case csvInt:
+		return cell
+	case csvFloat:
+		number, _ := strconv.ParseFloat(cell, 64)
+		return strconv.FormatFloat(number, 'g', -1, 64)
+	case csvBool:
+		return strconv.FormatBool(strings.EqualFold(cell, "true"))
+	case csvTime:
+		t, _ := time.Parse(column.layout, cell)
+		location := "time.UTC"
+		if _, offset := t.Zone(); offset != 0 {
+			location = fmt.Sprintf("time.FixedZone(\"\", %d)", offset)
+		}
+		return fmt.Sprintf("time.Date(%d, time.%s, %d, %d, %d, %d, %d, %s)",
+			t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+	}
+	return strconv.Quote(cell)
+}
+
+// convertCSVToGo genera un struct con un campo exportado por columna (tipo
+// inferido de sus valores) y un slice literal con una fila por registro. Las
+// celdas vacías conservan el valor cero del campo.
+func convertCSVToGo(content, packageName, variableName, originalFilename string, opts CSVOptions) (string, error) {
+	records, err := ReadCSV(content, opts)
+	if err != nil {
+		return "", err
+	}
+	if len(records) == 0 {
+		return "", fmt.Errorf("el CSV está vacío: se esperaba una fila de encabezado")
+	}
+	columns := inferCSVColumns(records[0], records[1:])
+	rowType := capitalizeFirst(variableName) + "Row"
+	usesTime := false
+	for _, column := range columns {
+		usesTime = usesTime || column.kind == csvTime
+	}
+	if usesTime && variableName == "time" {
+		// La variable ocultaría el paquete time que usan las columnas de fechas
+		return "", fmt.Errorf("el identificador 'time' coincide con el paquete time que importan las columnas de fecha; elija otro")
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	builder.WriteString(fmt.Sprintf("// Archivo generado automáticamente desde: %s\n", goCommentText(originalFilename)))
+	builder.WriteString(fmt.Sprintf("// Generado el: %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	builder.WriteString("// Filas del CSV con tipos inferidos por columna\n\n")
+	if usesTime {
+		builder.WriteString("import \"time\"\n\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("// %s una fila de %s\n", rowType, goCommentText(originalFilename)))
+	builder.WriteString(fmt.Sprintf("type %s struct {\n", rowType))
+	for _, column := range columns {
+		builder.WriteString(fmt.Sprintf("\t%s %s %s\n", column.field, column.kind, goStructTag("csv:"+strconv.Quote(column.header))))
+	}
+	builder.WriteString("}\n\n")
+
+	builder.WriteString(fmt.Sprintf("// %s contiene las filas del archivo (%d en total)\n", variableName, len(records)-1))
+	builder.WriteString(fmt.Sprintf("var %s = []%s{\n", variableName, rowType))
+	for _, row := range records[1:] {
+		var values []string
+		for i, cell := range row {
+			if cell != "" {
+				values = append(values, columns[i].field+": "+csvCellLiteral(columns[i], cell))
+			}
+		}
+		builder.WriteString(fmt.Sprintf("\t{%s},\n", strings.Join(values, ", ")))
+	}
+	builder.WriteString("}\n")
+	return builder.String(), nil
+}
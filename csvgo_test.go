@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test para la inferencia de tipos de las columnas
+func TestInferCSVColumns(t *testing.T) {
+	header := []string{"id", "precio", "activo", "alta", "momento", "codigo", "nota", "vacía", "id"}
+	rows := [][]string{
+		{"1", "2.5", "true", "2024-01-02", "2024-01-02T03:04:05Z", "007", "x", "", "9"},
+		{"-20", "3", "FALSE", "2024-12-31", "2024-06-30T10:00:00-03:00", "12", "", "", "9223372036854775808"},
+	}
+	want := []csvColumn{
+		{header: "id", field: "ID", kind: csvInt},
+		{header: "precio", field: "Precio", kind: csvFloat},
+		{header: "activo", field: "Activo", kind: csvBool},
+		{header: "alta", field: "Alta", kind: csvTime, layout: "2006-01-02"},
+		{header: "momento", field: "Momento", kind: csvTime, layout: "2006-01-02T15:04:05.999999999Z07:00"},
+		{header: "codigo", field: "Codigo", kind: csvString},
+		{header: "nota", field: "Nota", kind: csvString},
+		{header: "vacía", field: "Vacía", kind: csvString},
+		{header: "id", field: "ID2", kind: csvFloat},
+	}
+
+	got := inferCSVColumns(header, rows)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("columna %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// Test: el CSV se convierte en un slice de structs que compila
+func TestConvertCSVToGo(t *testing.T) {
+	input := "id;nombre;salario;activo;alta\n1;'Pérez; Juan';55000.5;true;2024-01-02T03:04:05-03:00\n2;Ana;;false;2024-02-03T00:00:00Z\n"
+	source, err := convertCSVToGo(input, "datos", "empleados", "empleados.csv", CSVOptions{Delimiter: ';', Quote: '\''})
+	if err != nil {
+		t.Fatalf("convertCSVToGo() error = %v", err)
+	}
+	formatted, _, err := formatGoSource(source, "empleados_rows.go")
+	if err != nil {
+		t.Fatalf("formatGoSource() error = %v\n%s", err, source)
+	}
+	typeCheckGo(t, formatted)
+
+	for _, fragment := range []string{
+		"import \"time\"",
+		"type EmpleadosRow struct {\n\tID      int64     `csv:\"id\"`\n\tNombre  string    `csv:\"nombre\"`\n\tSalario float64   `csv:\"salario\"`\n\tActivo  bool      `csv:\"activo\"`\n\tAlta    time.Time `csv:\"alta\"`\n}",
+		"var empleados = []EmpleadosRow{\n" +
+			"\t{ID: 1, Nombre: \"Pérez; Juan\", Salario: 55000.5, Activo: true, Alta: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.FixedZone(\"\", -10800))},\n" +
+			"\t{ID: 2, Nombre: \"Ana\", Activo: false, Alta: time.Date(2024, time.February, 3, 0, 0, 0, 0, time.UTC)},\n}",
+	} {
+		if !strings.Contains(formatted, fragment) {
+			t.Errorf("falta %q en:\n%s", fragment, formatted)
+		}
+	}
+
+	if _, err := convertCSVToGo(input, "datos", "time", "empleados.csv", CSVOptions{Delimiter: ';', Quote: '\''}); err == nil || !strings.Contains(err.Error(), "identificador 'time'") {
+		t.Errorf("convertCSVToGo() con identificador time error = %v", err)
+	}
+	if _, err := convertCSVToGo("a\n1\n", "datos", "time", "numeros.csv", CSVOptions{}); err != nil {
+		t.Errorf("convertCSVToGo() sin fechas con identificador time error = %v", err)
+	}
+	if _, err := convertCSVToGo("", "main", "filas", "vacio.csv", CSVOptions{}); err == nil {
+		t.Error("se esperaba error para un CSV vacío")
+	}
+	if _, err := convertCSVToGo("a,b\n1\n", "main", "filas", "roto.csv", CSVOptions{}); AsCSVError(err) == nil {
+		t.Errorf("se esperaba *CSVError, got %v", err)
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxCSVArrayIndex índice máximo de una columna "lista[n]", para que un
+// encabezado no reserve arrays enormes
+const maxCSVArrayIndex = 10000
+
+// FlattenJSONRows convierte un array de objetos (o un único objeto) en un
+// encabezado y filas CSV. Los objetos anidados se aplanan con nombres de
+// columna separados por puntos ("cliente.nombre") y los arrays con el índice
+// como sufijo ("tags[0]", "items[1].sku"). Las columnas siguen el orden de
+// primera aparición; null y las claves ausentes quedan como celdas vacías, y
+// los objetos y arrays vacíos se escriben como {} y []. Dos hojas de un mismo
+// objeto que producen el mismo nombre de columna son un error.
+func FlattenJSONRows(value interface{}) ([]string, [][]string, error) {
+	items, isArray := value.([]interface{})
+	if !isArray {
+		if objectValues(value) == nil {
+			return nil, nil, fmt.Errorf("se esperaba un array de objetos o un objeto, se recibió %s", JSONTypeOf(value))
+		}
+		items = []interface{}{value}
+	}
+
+	var header []string
+	columns := map[string]int{}
+	cells := make([]map[string]string, len(items))
+	for i, item := range items {
+		if objectValues(item) == nil {
+			return nil, nil, fmt.Errorf("el elemento %d no es un objeto (%s)", i, JSONTypeOf(item))
+		}
+		cells[i] = map[string]string{}
+		err := flattenValue(item, "", true, func(column, cell string) error {
+			if _, exists := cells[i][column]; exists {
+				return fmt.Errorf("el elemento %d tiene dos valores para la columna '%s'", i, column)
+			}
+			if _, exists := columns[column]; !exists {
+				columns[column] = len(header)
+				header = append(header, column)
+			}
+			cells[i][column] = cell
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	rows := make([][]string, len(items))
+	for i := range items {
+		rows[i] = make([]string, len(header))
+		for column, cell := range cells[i] {
+			rows[i][columns[column]] = cell
+		}
+	}
+	return header, rows, nil
+}
+
+// flattenValue llama a emit con cada hoja de value y su nombre de columna.
+// root indica el objeto de la fila, cuyas claves no llevan '.' delante; no
+// basta con column == "" porque la clave vacía también es un nivel
+func flattenValue(value interface{}, column string, root bool, emit func(column, cell string) error) error {
+	if items, isArray := value.([]interface{}); isArray {
+		if len(items) == 0 {
+			return emit(column, "[]")
+		}
+		for i, item := range items {
+			if err := flattenValue(item, column+"["+strconv.Itoa(i)+"]", false, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if members := objectValues(value); members != nil {
+		if len(members) == 0 && !root {
+			return emit(column, "{}")
+		}
+		for _, key := range orderedKeys(value) {
+			child := escapeColumnKey(key)
+			if !root {
+				child = column + "." + child
+			}
+			if err := flattenValue(members[key], child, false, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return emit(column, "")
+	case string:
+		return emit(column, v)
+	case bool:
+		return emit(column, strconv.FormatBool(v))
+	default:
+		data, err := Marshal(value)
+		if err != nil {
+			return err
+		}
+		return emit(column, string(data))
+	}
+}
+
+// escapeColumnKey escapa con '\' los caracteres con significado en los nombres
+// de columna, para que una clave "a.b" no se lea como dos niveles
+func escapeColumnKey(key string) string {
+	if !strings.ContainsAny(key, `\.[`) {
+		return key
+	}
+	var builder strings.Builder
+	for _, r := range key {
+		if r == '\\' || r == '.' || r == '[' {
+			builder.WriteRune('\\')
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// columnSegment un paso del camino de una columna: una clave o un índice
+type columnSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseColumnPath divide un nombre de columna en claves (separadas por '.') y
+// los índices "[n]" que cierran cada clave. Un '[' que no forma un índice
+// final es parte de la clave, de modo que encabezados como "precio [USD]" se
+// conservan.
+func parseColumnPath(column string) ([]columnSegment, error) {
+	var segments []columnSegment
+	for _, part := range splitColumnParts(column) {
+		var indexes []columnSegment
+		for strings.HasSuffix(part, "]") {
+			open := strings.LastIndexByte(part, '[')
+			if open < 0 || isEscapedAt(part, open) {
+				break
+			}
+			digits := part[open+1 : len(part)-1]
+			if !csvIntPattern.MatchString(digits) || digits[0] == '-' {
+				break
+			}
+			index, err := strconv.Atoi(digits)
+			if err != nil || index > maxCSVArrayIndex {
+				return nil, fmt.Errorf("columna '%s': el índice %s supera el máximo de %d", column, digits, maxCSVArrayIndex)
+			}
+			indexes = append([]columnSegment{{index: index, isIndex: true}}, indexes...)
+			part = part[:open]
+		}
+		segments = append(segments, columnSegment{key: unescapeColumnKey(part)})
+		segments = append(segments, indexes...)
+	}
+	return segments, nil
+}
+
+// splitColumnParts separa el nombre de columna en los '.' no escapados,
+// conservando los escapes de cada parte
+func splitColumnParts(column string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(column); i++ {
+		switch column[i] {
+		case '\\':
+			i++
+		case '.':
+			parts = append(parts, column[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, column[start:])
+}
+
+// isEscapedAt indica si el carácter en offset está precedido por un '\' de escape
+func isEscapedAt(text string, offset int) bool {
+	backslashes := 0
+	for i := offset - 1; i >= 0 && text[i] == '\\'; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+// unescapeColumnKey quita los '\' de escape de una clave
+func unescapeColumnKey(key string) string {
+	if !strings.Contains(key, `\`) {
+		return key
+	}
+	var builder strings.Builder
+	for i := 0; i < len(key); i++ {
+		if key[i] == '\\' && i+1 < len(key) {
+			i++
+		}
+		builder.WriteByte(key[i])
+	}
+	return builder.String()
+}
+
+// UnflattenCSVRows reconstruye un array de objetos a partir de un encabezado
+// con nombres de columna aplanados (el inverso de FlattenJSONRows). Las celdas
+// vacías se omiten; salvo con keepStrings, "true"/"false" se leen como
+// booleanos, los números JSON como números exactos y "{}"/"[]" como
+// contenedores vacíos.
+func (p *Parser) UnflattenCSVRows(records [][]string, keepStrings bool) ([]interface{}, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("el CSV está vacío: se esperaba una fila de encabezado")
+	}
+	paths := make([][]columnSegment, len(records[0]))
+	for i, column := range records[0] {
+		path, err := parseColumnPath(column)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = path
+	}
+
+	rows := make([]interface{}, 0, len(records)-1)
+	for line, record := range records[1:] {
+		if len(record) != len(records[0]) {
+			return nil, fmt.Errorf("fila %d: tiene %d columnas, el encabezado tiene %d", line+1, len(record), len(records[0]))
+		}
+		var row interface{} = NewOrderedObject()
+		for i, cell := range record {
+			if cell == "" {
+				continue
+			}
+			value, err := p.csvCellValue(cell, keepStrings)
+			if err != nil {
+				return nil, err
+			}
+			if row, err = setColumnPath(row, paths[i], value); err != nil {
+				return nil, fmt.Errorf("fila %d, columna '%s': %w", line+1, records[0][i], err)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// csvCellValue interpreta el texto de una celda
+func (p *Parser) csvCellValue(cell string, keepStrings bool) (interface{}, error) {
+	if keepStrings {
+		return cell, nil
+	}
+	switch cell {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "{}":
+		return NewOrderedObject(), nil
+	case "[]":
+		return []interface{}{}, nil
+	}
+	if csvFloatPattern.MatchString(cell) {
+		return p.convertNumberMode(cell, NumberAsNumber)
+	}
+	return cell, nil
+}
+
+// setColumnPath guarda value en el camino dentro de current (creando los
+// objetos y arrays intermedios) y devuelve el contenedor actualizado
+func setColumnPath(current interface{}, path []columnSegment, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		if current != nil {
+			return nil, fmt.Errorf("el valor entra en conflicto con otra columna del mismo camino")
+		}
+		return value, nil
+	}
+
+	segment := path[0]
+	if segment.isIndex {
+		if current == nil {
+			current = []interface{}{}
+		}
+		items, isArray := current.([]interface{})
+		if !isArray {
+			return nil, fmt.Errorf("el índice [%d] se aplica a un valor que no es un array", segment.index)
+		}
+		for len(items) <= segment.index {
+			items = append(items, nil)
+		}
+		child, err := setColumnPath(items[segment.index], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		items[segment.index] = child
+		return items, nil
+	}
+
+	if current == nil {
+		current = NewOrderedObject()
+	}
+	members := objectValues(current)
+	if members == nil {
+		return nil, fmt.Errorf("la clave '%s' se aplica a un valor que no es un objeto", segment.key)
+	}
+	child, err := setColumnPath(members[segment.key], path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	setMember(current, segment.key, child)
+	return current, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test para el aplanado de objetos anidados en columnas
+func TestFlattenJSONRows(t *testing.T) {
+	input := `[
+		{"id": 1, "cliente": {"nombre": "Ana", "email": null}, "tags": ["a", "b"], "activo": true},
+		{"id": 2.50, "cliente": {"nombre": "Luis"}, "items": [{"sku": "X1", "qty": 2}], "extra": {}, "vacío": []},
+		{"id": 3, "a.b": "punto", "precio [USD]": "10", "x[0]": "literal"}
+	]`
+	header, rows, err := FlattenJSONRows(parseExact(t, input))
+	if err != nil {
+		t.Fatalf("FlattenJSONRows() error = %v", err)
+	}
+
+	wantHeader := []string{"id", "cliente.nombre", "cliente.email", "tags[0]", "tags[1]", "activo",
+		"items[0].sku", "items[0].qty", "extra", "vacío", `a\.b`, `precio \[USD]`, `x\[0]`}
+	if !reflect.DeepEqual(header, wantHeader) {
+		t.Errorf("header = %q, want %q", header, wantHeader)
+	}
+	wantRows := [][]string{
+		{"1", "Ana", "", "a", "b", "true", "", "", "", "", "", "", ""},
+		{"2.50", "Luis", "", "", "", "", "X1", "2", "{}", "[]", "", "", ""},
+		{"3", "", "", "", "", "", "", "", "", "", "punto", "10", "literal"},
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("rows = %q, want %q", rows, wantRows)
+	}
+
+	for _, invalid := range []string{`"texto"`, `[1, 2]`, `[{"a": 1}, null]`} {
+		if _, _, err := FlattenJSONRows(parseExact(t, invalid)); err == nil {
+			t.Errorf("FlattenJSONRows(%s) se esperaba error", invalid)
+		}
+	}
+}
+
+// Test para la lectura de nombres de columna aplanados
+func TestParseColumnPath(t *testing.T) {
+	tests := map[string][]columnSegment{
+		"nombre":          {{key: "nombre"}},
+		"cliente.nombre":  {{key: "cliente"}, {key: "nombre"}},
+		"items[1].sku":    {{key: "items"}, {index: 1, isIndex: true}, {key: "sku"}},
+		"m[0][2]":         {{key: "m"}, {index: 0, isIndex: true}, {index: 2, isIndex: true}},
+		`a\.b.c`:          {{key: "a.b"}, {key: "c"}},
+		"precio [USD]":    {{key: "precio [USD]"}},
+		`x\[0]`:           {{key: "x[0]"}},
+		"lista[01]":       {{key: "lista[01]"}},
+		"fin.":            {{key: "fin"}, {key: ""}},
+		"[3]":             {{key: ""}, {index: 3, isIndex: true}},
+		`barra\\[2]`:      {{key: `barra\`}, {index: 2, isIndex: true}},
+		"a[x].b[-1]":      {{key: "a[x]"}, {key: "b[-1]"}},
+		"lista[10000]":    {{key: "lista"}, {index: 10000, isIndex: true}},
+		"dos[0]puntos[1]": {{key: "dos[0]puntos"}, {index: 1, isIndex: true}},
+	}
+	for column, want := range tests {
+		got, err := parseColumnPath(column)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("parseColumnPath(%q) = %+v, %v, want %+v", column, got, err, want)
+		}
+	}
+
+	if _, err := parseColumnPath("lista[10001]"); err == nil || !strings.Contains(err.Error(), "supera el máximo") {
+		t.Errorf("parseColumnPath() con índice enorme error = %v", err)
+	}
+}
+
+// Test para la clave vacía: sigue siendo un nivel del nombre de columna
+func TestFlattenJSONRowsEmptyKey(t *testing.T) {
+	value := parseExact(t, `[{"":{"b":1},"b":2,"c":{"":3}}]`)
+	header, rows, err := FlattenJSONRows(value)
+	if err != nil {
+		t.Fatalf("FlattenJSONRows() error = %v", err)
+	}
+	wantHeader := []string{".b", "b", "c."}
+	if !reflect.DeepEqual(header, wantHeader) || !reflect.DeepEqual(rows, [][]string{{"1", "2", "3"}}) {
+		t.Errorf("FlattenJSONRows() = %q %q, want %q", header, rows, wantHeader)
+	}
+
+	back, err := NewParser().UnflattenCSVRows(append([][]string{header}, rows...), false)
+	if err != nil {
+		t.Fatalf("UnflattenCSVRows() error = %v", err)
+	}
+	if !EqualValues(back, value) {
+		t.Errorf("UnflattenCSVRows() = %s", mustMarshal(t, back))
+	}
+}
+
+// Test: UnflattenCSVRows reconstruye el anidamiento de FlattenJSONRows
+func TestUnflattenCSVRows(t *testing.T) {
+	input := `[{"id":1,"cliente":{"nombre":"Ana","tel":["1","2"]},"m":[[1,2],[3]],"a.b":true,"extra":{},"vacío":[]},{"id":2,"cliente":{"nombre":"Luis"}}]`
+	value := parseExact(t, input)
+	header, rows, err := FlattenJSONRows(value)
+	if err != nil {
+		t.Fatalf("FlattenJSONRows() error = %v", err)
+	}
+	text, err := WriteCSV(append([][]string{header}, rows...), CSVOptions{})
+	if err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	records, err := ReadCSV(text, CSVOptions{})
+	if err != nil {
+		t.Fatalf("ReadCSV() error = %v", err)
+	}
+
+	back, err := NewParser().UnflattenCSVRows(records, false)
+	if err != nil {
+		t.Fatalf("UnflattenCSVRows() error = %v", err)
+	}
+	// Los strings numéricos se leen como números salvo con keepStrings
+	want := strings.Replace(input, `"tel":["1","2"]`, `"tel":[1,2]`, 1)
+	if got := string(mustMarshal(t, back)); got != want {
+		t.Errorf("UnflattenCSVRows() = %s, want %s", got, want)
+	}
+
+	back, err = NewParser().UnflattenCSVRows([][]string{{"n", "ok", "lista[1]"}, {"007", "true", "x"}}, true)
+	if err != nil {
+		t.Fatalf("UnflattenCSVRows(keepStrings) error = %v", err)
+	}
+	if got := string(mustMarshal(t, back)); got != `[{"n":"007","ok":"true","lista":[null,"x"]}]` {
+		t.Errorf("UnflattenCSVRows(keepStrings) = %s", got)
+	}
+}
+
+// Test para las columnas que no se pueden combinar
+func TestUnflattenCSVRowsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		records [][]string
+		wantErr string
+	}{
+		{"vacío", nil, "el CSV está vacío"},
+		{"valor y objeto", [][]string{{"a", "a.b"}, {"1", "2"}}, "fila 1, columna 'a.b': la clave 'b' se aplica a un valor que no es un objeto"},
+		{"objeto y array", [][]string{{"a.b", "a[0]"}, {"1", "2"}}, "no es un array"},
+		{"columna repetida", [][]string{{"x", "x"}, {"1", "2"}}, "entra en conflicto"},
+		{"fila más ancha", [][]string{{"a"}, {"1", "2"}}, "fila 1: tiene 2 columnas, el encabezado tiene 1"},
+		{"fila más corta", [][]string{{"a", "b"}, {"1", "2"}, {"3"}}, "fila 2: tiene 1 columnas, el encabezado tiene 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser().UnflattenCSVRows(tt.records, false)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("UnflattenCSVRows() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Las celdas vacías no generan conflictos
+	if _, err := NewParser().UnflattenCSVRows([][]string{{"a", "a.b"}, {"", "2"}, {"1", ""}}, false); err != nil {
+		t.Errorf("UnflattenCSVRows() error = %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"io"
+)
+
+// Delim delimitador JSON devuelto por Decoder.Token: '{', '}', '[' o ']'
+type Delim rune
+
+// String implementa fmt.Stringer
+func (d Delim) String() string {
+	return string(d)
+}
+
+// Token valor devuelto por Decoder.Token: Delim, string, número, bool o nil
+type Token interface{}
+
+// Estados de la máquina de tokens del Decoder
+const (
+	decodeTopValue = iota
+	decodeArrayStart
+	decodeArrayValue
+	decodeArrayComma
+	decodeObjectStart
+	decodeObjectKey
+	decodeObjectColon
+	decodeObjectValue
+	decodeObjectComma
+)
+
+// decodeExpected describe lo esperado en cada estado (para errores)
+var decodeExpected = map[int]string{
+	decodeTopValue:    "valor JSON",
+	decodeArrayStart:  "valor JSON o ']'",
+	decodeArrayValue:  "valor JSON",
+	decodeArrayComma:  "',' o ']'",
+	decodeObjectStart: "clave entre comillas o '}'",
+	decodeObjectKey:   "clave entre comillas",
+	decodeObjectColon: "':'",
+	decodeObjectValue: "valor JSON",
+	decodeObjectComma: "',' o '}'",
+}
+
+// Decoder lee valores JSON desde un io.Reader con memoria acotada: el lexer
+// solo retiene el token en curso y únicamente se construyen los valores que
+// se piden con Decode. Token permite recorrer arrays y objetos gigantes
+// elemento a elemento.
+type Decoder struct {
+	state      *parseState
+	tokenState int
+	tokenStack []int
+	err        error
+}
+
+// NewDecoder crea un Decoder que lee desde r con la configuración del parser
+func (p *Parser) NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		state: &parseState{parser: p, lex: newReaderLexer(r)},
+	}
+}
+
+// NewDecoder función de conveniencia con un parser por defecto
+func NewDecoder(r io.Reader) *Decoder {
+	return NewParser().NewDecoder(r)
+}
+
+// PreserveOrder hace que los objetos se decodifiquen como *OrderedObject
+func (d *Decoder) PreserveOrder() {
+	d.state.opts.PreserveOrder = true
+}
+
+// SetNumberMode define la representación de los números decodificados
+func (d *Decoder) SetNumberMode(mode NumberMode) {
+	d.state.opts.NumberMode = mode
+}
+
+// LenientStrings activa la decodificación tolerante de strings
+// (ver ParseOptions.LenientStrings)
+func (d *Decoder) LenientStrings() {
+	d.state.opts.LenientStrings = true
+	d.state.lex.lenient = true
+}
+
+// InputOffset devuelve el offset absoluto del siguiente byte a procesar
+func (d *Decoder) InputOffset() int {
+	return d.state.lex.offset(d.state.lex.pos)
+}
+
+// More indica si quedan elementos en el array u objeto actual
+// (o más documentos en el flujo, en el nivel superior)
+func (d *Decoder) More() bool {
+	c, ok := d.state.lex.peek()
+	return ok && c != ']' && c != '}'
+}
+
+// Decode lee el siguiente valor JSON completo. Al agotarse el flujo en el
+// nivel superior devuelve io.EOF, lo que permite leer documentos concatenados.
+func (d *Decoder) Decode() (interface{}, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if err := d.prepareForDecode(); err != nil {
+		return nil, d.fail(err)
+	}
+
+	tok, err := d.state.next(decodeExpected[d.tokenState])
+	if err != nil {
+		return nil, d.fail(err)
+	}
+	if tok.kind == tokenEOF {
+		if d.tokenState == decodeTopValue {
+			return nil, io.EOF
+		}
+		return nil, d.fail(d.state.unexpectedEOF(tok))
+	}
+
+	value, err := d.state.parseValue(tok)
+	if err != nil {
+		return nil, d.fail(err)
+	}
+
+	d.valueEnd()
+	return value, nil
+}
+
+// Token devuelve el siguiente token del flujo: Delim para '{', '}', '[' y ']',
+// string para claves y strings, y el valor correspondiente para escalares.
+// Las comas y los dos puntos se validan y se omiten.
+func (d *Decoder) Token() (Token, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	for {
+		c, ok := d.state.lex.peek()
+		if !ok {
+			if err := d.state.lex.ioError(); err != nil {
+				return nil, d.fail(err)
+			}
+			if d.tokenState == decodeTopValue {
+				return nil, io.EOF
+			}
+			tok, _ := d.state.next("")
+			return nil, d.fail(d.state.unexpectedEOF(tok))
+		}
+
+		switch c {
+		case '[':
+			if !d.valueAllowed() {
+				return nil, d.fail(d.unexpected())
+			}
+			d.state.lex.next()
+			d.tokenStack = append(d.tokenStack, d.tokenState)
+			d.tokenState = decodeArrayStart
+			d.state.brackets++
+			return Delim('['), nil
+
+		case ']':
+			if d.tokenState != decodeArrayStart && d.tokenState != decodeArrayComma {
+				return nil, d.fail(d.unexpected())
+			}
+			d.state.lex.next()
+			d.pop()
+			d.state.brackets--
+			d.valueEnd()
+			return Delim(']'), nil
+
+		case '{':
+			if !d.valueAllowed() {
+				return nil, d.fail(d.unexpected())
+			}
+			d.state.lex.next()
+			d.tokenStack = append(d.tokenStack, d.tokenState)
+			d.tokenState = decodeObjectStart
+			d.state.braces++
+			return Delim('{'), nil
+
+		case '}':
+			if d.tokenState != decodeObjectStart && d.tokenState != decodeObjectComma {
+				return nil, d.fail(d.unexpected())
+			}
+			d.state.lex.next()
+			d.pop()
+			d.state.braces--
+			d.valueEnd()
+			return Delim('}'), nil
+
+		case ':':
+			if d.tokenState != decodeObjectColon {
+				return nil, d.fail(d.unexpected())
+			}
+			d.state.lex.next()
+			d.tokenState = decodeObjectValue
+			continue
+
+		case ',':
+			switch d.tokenState {
+			case decodeArrayComma:
+				d.state.lex.next()
+				d.tokenState = decodeArrayValue
+				continue
+			case decodeObjectComma:
+				d.state.lex.next()
+				d.tokenState = decodeObjectKey
+				continue
+			}
+			return nil, d.fail(d.unexpected())
+
+		case '"':
+			if d.tokenState == decodeObjectStart || d.tokenState == decodeObjectKey {
+				tok, err := d.state.next(decodeExpected[d.tokenState])
+				if err != nil {
+					return nil, d.fail(err)
+				}
+				d.tokenState = decodeObjectColon
+				return tok.text, nil
+			}
+		}
+
+		if !d.valueAllowed() {
+			return nil, d.fail(d.unexpected())
+		}
+		return d.Decode()
+	}
+}
+
+// expectEOF verifica que el flujo termine tras el valor decodificado
+func (d *Decoder) expectEOF() error {
+	if d.err != nil {
+		return d.err
+	}
+	return d.fail(d.state.expectEOF())
+}
+
+// prepareForDecode consume la coma o los dos puntos pendientes antes de un valor
+func (d *Decoder) prepareForDecode() error {
+	switch d.tokenState {
+	case decodeArrayComma:
+		c, ok := d.state.lex.peek()
+		if !ok || c != ',' {
+			return d.expectedSymbol(ok, c)
+		}
+		d.state.lex.next()
+		d.tokenState = decodeArrayValue
+	case decodeObjectColon:
+		c, ok := d.state.lex.peek()
+		if !ok || c != ':' {
+			return d.expectedSymbol(ok, c)
+		}
+		d.state.lex.next()
+		d.tokenState = decodeObjectValue
+	case decodeObjectStart, decodeObjectKey, decodeObjectComma:
+		// En posición de clave solo se admite Token
+		c, ok := d.state.lex.peek()
+		return d.expectedSymbol(ok, c)
+	}
+	return nil
+}
+
+// valueAllowed indica si el estado actual admite el inicio de un valor
+func (d *Decoder) valueAllowed() bool {
+	switch d.tokenState {
+	case decodeTopValue, decodeArrayStart, decodeArrayValue, decodeObjectValue:
+		return true
+	}
+	return false
+}
+
+// valueEnd avanza la máquina de estados tras completar un valor
+func (d *Decoder) valueEnd() {
+	switch d.tokenState {
+	case decodeArrayStart, decodeArrayValue:
+		d.tokenState = decodeArrayComma
+	case decodeObjectValue:
+		d.tokenState = decodeObjectComma
+	}
+}
+
+// pop restaura el estado del contenedor padre
+func (d *Decoder) pop() {
+	last := len(d.tokenStack) - 1
+	d.tokenState = d.tokenStack[last]
+	d.tokenStack = d.tokenStack[:last]
+}
+
+// unexpected construye el error para un carácter no válido en el estado actual
+func (d *Decoder) unexpected() error {
+	lex := d.state.lex
+	return lex.errorf(lex.offset(lex.pos), decodeExpected[d.tokenState], "formato JSON inválido: carácter inesperado %s", lex.charAt(lex.pos))
+}
+
+// expectedSymbol reporta la ausencia del separador requerido por el estado actual
+func (d *Decoder) expectedSymbol(ok bool, c byte) error {
+	if !ok {
+		if err := d.state.lex.ioError(); err != nil {
+			return err
+		}
+		tok, _ := d.state.next("")
+		return d.state.unexpectedEOF(tok)
+	}
+	return d.unexpected()
+}
+
+// fail registra un error definitivo: el Decoder no puede continuar tras él
+func (d *Decoder) fail(err error) error {
+	d.err = err
+	return err
+}
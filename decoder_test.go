@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// Test para documentos concatenados en un mismo flujo
+func TestDecoderDecodeStream(t *testing.T) {
+	input := "{\"a\": 1}\n[true, null]\n\"texto\" 42"
+	expected := []interface{}{
+		map[string]interface{}{"a": 1.0},
+		[]interface{}{true, nil},
+		"texto",
+		42.0,
+	}
+
+	dec := NewDecoder(strings.NewReader(input))
+	for i, want := range expected {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("documento %d: Decode() error = %v", i, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("documento %d: Decode() = %v, want %v", i, got, want)
+		}
+	}
+
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Errorf("Decode() al final = %v, want io.EOF", err)
+	}
+}
+
+// Test para la secuencia de tokens del Decoder
+func TestDecoderToken(t *testing.T) {
+	input := `{"a": [1, "x", true, null], "b": {}}`
+	expected := []Token{
+		Delim('{'), "a", Delim('['), 1.0, "x", true, nil, Delim(']'),
+		"b", Delim('{'), Delim('}'), Delim('}'),
+	}
+
+	dec := NewDecoder(iotest.OneByteReader(strings.NewReader(input)))
+	for i, want := range expected {
+		got, err := dec.Token()
+		if err != nil {
+			t.Fatalf("token %d: Token() error = %v", i, err)
+		}
+		if got != want {
+			t.Fatalf("token %d: Token() = %v (%T), want %v (%T)", i, got, got, want, want)
+		}
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		t.Errorf("Token() al final = %v, want io.EOF", err)
+	}
+}
+
+// Test para recorrer un array elemento a elemento combinando Token, More y Decode
+func TestDecoderArrayElements(t *testing.T) {
+	input := generateLargeJSON(200)
+	dec := NewDecoder(iotest.HalfReader(strings.NewReader(input)))
+
+	for _, want := range []Token{Delim('{'), "items", Delim('[')} {
+		if got, err := dec.Token(); err != nil || got != want {
+			t.Fatalf("Token() = %v, %v, want %v", got, err, want)
+		}
+	}
+
+	count := 0
+	for dec.More() {
+		element, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("elemento %d: Decode() error = %v", count, err)
+		}
+		item, ok := element.(map[string]interface{})
+		if !ok || item["id"] != float64(count) {
+			t.Fatalf("elemento %d: Decode() = %v", count, element)
+		}
+		count++
+	}
+
+	if count != 200 {
+		t.Errorf("se decodificaron %d elementos, want 200", count)
+	}
+	for _, want := range []Token{Delim(']'), Delim('}')} {
+		if got, err := dec.Token(); err != nil || got != want {
+			t.Fatalf("Token() = %v, %v, want %v", got, err, want)
+		}
+	}
+}
+
+// Test de paridad con ParseJSON leyendo de a un byte
+func TestDecoderParity(t *testing.T) {
+	input := generateLargeJSON(100)
+
+	want, err := NewParser().ParseJSON(input)
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+
+	got, err := NewDecoder(iotest.OneByteReader(strings.NewReader(input))).Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Error("Decode() y ParseJSON() producen resultados distintos")
+	}
+}
+
+// Test para que los errores del flujo conserven la ubicación absoluta
+func TestDecoderSyntaxErrorLocation(t *testing.T) {
+	input := "[\n" + strings.Repeat("  {\"ok\": true},\n", 5000) + "  {\"ok\": tru}\n]"
+
+	_, parseErr := NewParser().ParseJSON(input)
+	want := AsSyntaxError(parseErr)
+	if want == nil {
+		t.Fatalf("ParseJSON() error = %v, want *SyntaxError", parseErr)
+	}
+
+	_, err := NewDecoder(iotest.HalfReader(strings.NewReader(input))).Decode()
+	got := AsSyntaxError(err)
+	if got == nil {
+		t.Fatalf("Decode() error = %v, want *SyntaxError", err)
+	}
+
+	if got.Offset != want.Offset || got.Line != want.Line || got.Column != want.Column {
+		t.Errorf("Decode() posición = (%d, %d:%d), want (%d, %d:%d)",
+			got.Offset, got.Line, got.Column, want.Offset, want.Line, want.Column)
+	}
+	if got.Line != 5002 || got.Column != 10 {
+		t.Errorf("Decode() posición = %d:%d, want 5002:10", got.Line, got.Column)
+	}
+}
+
+// Test para errores de lectura y de estructura en el flujo
+func TestDecoderErrors(t *testing.T) {
+	readErr := errors.New("conexión interrumpida")
+	reader := io.MultiReader(strings.NewReader(`[1, 2, `), iotest.ErrReader(readErr))
+	if _, err := NewDecoder(reader).Decode(); !errors.Is(err, readErr) {
+		t.Errorf("Decode() error = %v, want %v", err, readErr)
+	}
+
+	dec := NewDecoder(strings.NewReader(`[1 2]`))
+	dec.Token()
+	dec.Token()
+	if _, err := dec.Token(); err == nil || !strings.Contains(err.Error(), "carácter inesperado '2'") {
+		t.Errorf("Token() error = %v, want carácter inesperado '2'", err)
+	}
+
+	dec = NewDecoder(strings.NewReader(`{"a": 1`))
+	for i := 0; i < 3; i++ {
+		dec.Token()
+	}
+	if _, err := dec.Token(); err == nil || !strings.Contains(err.Error(), "llaves desbalanceadas") {
+		t.Errorf("Token() error = %v, want llaves desbalanceadas", err)
+	}
+}
+
+// chunkedJSONReader genera un array JSON enorme sin materializarlo en memoria
+type chunkedJSONReader struct {
+	remaining int
+	pending   []byte
+	started   bool
+}
+
+func (r *chunkedJSONReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		switch {
+		case !r.started:
+			r.pending = []byte("[")
+			r.started = true
+		case r.remaining > 0:
+			r.pending = []byte(`{"id": 12345, "name": "elemento", "tags": ["a", "b"]}`)
+			r.remaining--
+			if r.remaining > 0 {
+				r.pending = append(r.pending, ',', '\n')
+			}
+		case r.remaining == 0:
+			r.pending = []byte("]")
+			r.remaining = -1
+		default:
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// Test para verificar que la memoria del lexer no crece con el tamaño del flujo
+func TestDecoderBoundedMemory(t *testing.T) {
+	const elements = 100000 // ~5.5 MB
+	dec := NewDecoder(&chunkedJSONReader{remaining: elements})
+
+	if tok, err := dec.Token(); err != nil || tok != Delim('[') {
+		t.Fatalf("Token() = %v, %v, want [", tok, err)
+	}
+
+	count := 0
+	for dec.More() {
+		if _, err := dec.Decode(); err != nil {
+			t.Fatalf("elemento %d: Decode() error = %v", count, err)
+		}
+		count++
+		if size := cap(dec.state.lex.data); size > 4*lexerChunkSize {
+			t.Fatalf("el buffer del lexer creció a %d bytes", size)
+		}
+	}
+
+	if count != elements {
+		t.Errorf("se decodificaron %d elementos, want %d", count, elements)
+	}
+	if dec.InputOffset() < 5*1024*1024 {
+		t.Errorf("InputOffset() = %d, se esperaba haber leído todo el flujo", dec.InputOffset())
+	}
+}
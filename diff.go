@@ -0,0 +1,357 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"strconv"
+)
+
+// Tipos de diferencia estructural
+const (
+	DiffAdded   = "added"   // la ruta solo existe en el documento modificado
+	DiffRemoved = "removed" // la ruta solo existe en el documento original
+	DiffChanged = "changed" // la ruta existe en ambos con valores distintos
+)
+
+// maxDiffCompareWork presupuesto de comparaciones entre elementos cuando la
+// igualdad depende de IgnorePaths y no se puede resolver con EqualValues ni
+// agrupar por hash; cada comparación recorre ambos elementos
+const maxDiffCompareWork = 1 << 20
+
+// DiffOptions ajustes de la comparación estructural
+type DiffOptions struct {
+	// IgnoreArrayOrder compara los arrays como multiconjuntos: un elemento
+	// reubicado no cuenta como diferencia
+	IgnoreArrayOrder bool
+
+	// IgnorePaths rutas (JSON Pointer) que no se comparan, junto con todo lo
+	// que cuelga de ellas; el segmento "*" coincide con cualquier clave o índice
+	IgnorePaths []Pointer
+}
+
+// DiffEntry una diferencia entre los documentos, identificada por su JSON Pointer
+type DiffEntry struct {
+	Kind string      `json:"kind"` // DiffAdded, DiffRemoved o DiffChanged
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"` // valor en el original (nil si se agregó)
+	New  interface{} `json:"new"` // valor en el modificado (nil si se eliminó)
+}
+
+// CompareValues devuelve las diferencias estructurales entre dos documentos
+// en orden de recorrido. A diferencia de Diff, que produce un JSON Patch
+// aplicable, aquí cada cambio se describe con el valor anterior y el nuevo.
+func CompareValues(original, modified interface{}, opts DiffOptions) []DiffEntry {
+	return appendDifferences(nil, nil, original, modified, opts)
+}
+
+// appendDifferences agrega las diferencias encontradas bajo path
+func appendDifferences(entries []DiffEntry, path []string, a, b interface{}, opts DiffOptions) []DiffEntry {
+	if opts.ignored(path) {
+		return entries
+	}
+
+	aObject, bObject := objectValues(a), objectValues(b)
+	aArray, aIsArray := a.([]interface{})
+	bArray, bIsArray := b.([]interface{})
+
+	switch {
+	case aObject != nil && bObject != nil:
+		for _, key := range orderedKeys(a) {
+			child := appendToken(path, key)
+			if bValue, exists := bObject[key]; exists {
+				entries = appendDifferences(entries, child, aObject[key], bValue, opts)
+			} else if !opts.ignored(child) {
+				entries = append(entries, DiffEntry{Kind: DiffRemoved, Path: FormatPointer(child), Old: aObject[key]})
+			}
+		}
+		for _, key := range orderedKeys(b) {
+			child := appendToken(path, key)
+			if _, exists := aObject[key]; !exists && !opts.ignored(child) {
+				entries = append(entries, DiffEntry{Kind: DiffAdded, Path: FormatPointer(child), New: bObject[key]})
+			}
+		}
+	case aIsArray && bIsArray && opts.IgnoreArrayOrder:
+		entries = appendUnorderedDifferences(entries, path, aArray, bArray, opts)
+	case aIsArray && bIsArray:
+		entries = appendOrderedDifferences(entries, path, aArray, bArray, opts)
+	case !EqualValues(a, b):
+		entries = append(entries, DiffEntry{Kind: DiffChanged, Path: FormatPointer(path), Old: a, New: b})
+	}
+	return entries
+}
+
+// appendOrderedDifferences alinea los arrays sobre su subsecuencia común más
+// larga, como Diff, para que insertar un elemento no desplace a los demás. Dos
+// elementos son comunes si no tienen diferencias (respetando IgnorePaths). En
+// cada tramo distinto los elementos se emparejan en orden y se comparan en
+// profundidad (con el índice original); los que sobran se informan como
+// eliminados (índice original) o agregados (índice nuevo). Si los arrays no se
+// pueden alinear se comparan posición a posición.
+func appendOrderedDifferences(entries []DiffEntry, path []string, a, b []interface{}, opts DiffOptions) []DiffEntry {
+	budget := maxArrayAlignWork
+	if opts.ignoresBelow(path) {
+		budget = maxDiffCompareWork
+	}
+	hunks, ok := alignArrays(len(a), len(b), budget, func(i, j int) bool {
+		return opts.sameElement(path, i, a[i], b[j])
+	})
+	if !ok {
+		hunks = []arrayHunk{{aStart: 0, aEnd: len(a), bStart: 0, bEnd: len(b)}}
+	}
+	for _, h := range hunks {
+		entries = appendPairedDifferences(entries, path, indexRange(h.aStart, h.aEnd), indexRange(h.bStart, h.bEnd), a, b, opts)
+	}
+	return entries
+}
+
+// appendUnorderedDifferences empareja cada elemento del original con uno
+// equivalente del modificado. Los que quedan sin pareja se emparejan en orden
+// y se comparan en profundidad, de modo que un elemento modificado aparece
+// como cambio; el resto se informa como eliminado o agregado
+func appendUnorderedDifferences(entries []DiffEntry, path []string, a, b []interface{}, opts DiffOptions) []DiffEntry {
+	var unmatchedA, unmatchedB []int
+	if opts.ignoresBelow(path) {
+		unmatchedA, unmatchedB = matchElementsPairwise(path, a, b, opts)
+	} else {
+		unmatchedA, unmatchedB = matchElementsByHash(path, a, b, opts)
+	}
+	return appendPairedDifferences(entries, path, unmatchedA, unmatchedB, a, b, opts)
+}
+
+// matchElementsByHash agrupa los elementos del modificado por diffHash y
+// busca la pareja de cada elemento del original solo en su grupo; sirve
+// cuando ninguna ruta ignorada cuelga de los elementos. Devuelve los índices
+// sin pareja de cada lado
+func matchElementsByHash(path []string, a, b []interface{}, opts DiffOptions) ([]int, []int) {
+	buckets := make(map[uint64][]int)
+	for j, item := range b {
+		hash := diffHash(item)
+		buckets[hash] = append(buckets[hash], j)
+	}
+
+	matched := make([]bool, len(b))
+	var unmatchedA []int
+	for i, item := range a {
+		hash := diffHash(item)
+		bucket, found := buckets[hash], false
+		for k, j := range bucket {
+			if opts.sameElement(path, i, item, b[j]) {
+				buckets[hash] = slices.Delete(bucket, k, k+1)
+				matched[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			unmatchedA = append(unmatchedA, i)
+		}
+	}
+	return unmatchedA, unmatchedIndexes(matched)
+}
+
+// matchElementsPairwise compara cada elemento del original con los del
+// modificado hasta agotar maxDiffCompareWork; a partir de ahí los elementos
+// restantes quedan sin pareja y se comparan en orden
+func matchElementsPairwise(path []string, a, b []interface{}, opts DiffOptions) ([]int, []int) {
+	matched := make([]bool, len(b))
+	var unmatchedA []int
+	budget := maxDiffCompareWork
+	for i, item := range a {
+		found := false
+		for j := 0; j < len(b) && budget > 0; j++ {
+			if matched[j] {
+				continue
+			}
+			budget--
+			if opts.sameElement(path, i, item, b[j]) {
+				matched[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			unmatchedA = append(unmatchedA, i)
+		}
+	}
+	return unmatchedA, unmatchedIndexes(matched)
+}
+
+// unmatchedIndexes los índices sin pareja, en orden
+func unmatchedIndexes(matched []bool) []int {
+	var indexes []int
+	for j, ok := range matched {
+		if !ok {
+			indexes = append(indexes, j)
+		}
+	}
+	return indexes
+}
+
+// appendPairedDifferences compara a[aIndexes[k]] con b[bIndexes[k]] y
+// reporta los índices sobrantes de uno u otro lado como eliminados o agregados
+func appendPairedDifferences(entries []DiffEntry, path []string, aIndexes, bIndexes []int, a, b []interface{}, opts DiffOptions) []DiffEntry {
+	paired := min(len(aIndexes), len(bIndexes))
+	for k := 0; k < paired; k++ {
+		child := appendToken(path, strconv.Itoa(aIndexes[k]))
+		entries = appendDifferences(entries, child, a[aIndexes[k]], b[bIndexes[k]], opts)
+	}
+	for _, i := range aIndexes[paired:] {
+		if child := appendToken(path, strconv.Itoa(i)); !opts.ignored(child) {
+			entries = append(entries, DiffEntry{Kind: DiffRemoved, Path: FormatPointer(child), Old: a[i]})
+		}
+	}
+	for _, j := range bIndexes[paired:] {
+		if child := appendToken(path, strconv.Itoa(j)); !opts.ignored(child) {
+			entries = append(entries, DiffEntry{Kind: DiffAdded, Path: FormatPointer(child), New: b[j]})
+		}
+	}
+	return entries
+}
+
+// indexRange los índices de start a end (sin incluirlo)
+func indexRange(start, end int) []int {
+	indexes := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// sameElement indica si el elemento index del array en path (x en el
+// original, y en el modificado) no tiene diferencias
+func (opts DiffOptions) sameElement(path []string, index int, x, y interface{}) bool {
+	// Dos valores iguales no tienen diferencias con ninguna opción
+	if EqualValues(x, y) {
+		return true
+	}
+	if !opts.IgnoreArrayOrder && !opts.ignoresBelow(path) {
+		return false
+	}
+	return opts.equivalent(appendToken(path, strconv.Itoa(index)), x, y)
+}
+
+// equivalent indica si a y b no tienen diferencias bajo path. Recorre los
+// objetos como appendDifferences pero se detiene en la primera diferencia;
+// los arrays se comparan con appendDifferences
+func (opts DiffOptions) equivalent(path []string, a, b interface{}) bool {
+	if opts.ignored(path) {
+		return true
+	}
+	aObject, bObject := objectValues(a), objectValues(b)
+	_, aIsArray := a.([]interface{})
+	_, bIsArray := b.([]interface{})
+	switch {
+	case aObject != nil && bObject != nil:
+		if !opts.ignoresBelow(path) && !opts.IgnoreArrayOrder {
+			return EqualValues(a, b)
+		}
+		for key, value := range aObject {
+			child := appendToken(path, key)
+			if other, exists := bObject[key]; exists {
+				if !opts.equivalent(child, value, other) {
+					return false
+				}
+			} else if !opts.ignored(child) {
+				return false
+			}
+		}
+		for key := range bObject {
+			if _, exists := aObject[key]; !exists && !opts.ignored(appendToken(path, key)) {
+				return false
+			}
+		}
+		return true
+	case aIsArray && bIsArray:
+		return len(appendDifferences(nil, path, a, b, opts)) == 0
+	}
+	return EqualValues(a, b)
+}
+
+// ignored indica si path coincide con alguna ruta ignorada (o cuelga de ella)
+func (opts DiffOptions) ignored(path []string) bool {
+	for _, ignore := range opts.IgnorePaths {
+		if tokens := ignore.Tokens(); len(tokens) <= len(path) && tokensMatch(tokens, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoresBelow indica si alguna ruta ignorada cuelga de path, con lo que la
+// igualdad de los valores bajo path depende de sus rutas
+func (opts DiffOptions) ignoresBelow(path []string) bool {
+	for _, ignore := range opts.IgnorePaths {
+		if tokens := ignore.Tokens(); len(tokens) > len(path) && tokensMatch(tokens[:len(path)], path) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokensMatch compara tokens con el inicio de path; "*" coincide con cualquiera
+func tokensMatch(tokens, path []string) bool {
+	for i, token := range tokens {
+		if token != "*" && token != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// diffHash resume un valor de modo que los valores sin diferencias con
+// IgnoreArrayOrder (números iguales en otra representación, objetos con otro
+// orden de claves, arrays con otro orden) den el mismo resultado. Las
+// colisiones se resuelven comparando los valores
+func diffHash(value interface{}) uint64 {
+	if _, isNumber := numericLiteral(value); isNumber {
+		f, err := canonicalFloat(value)
+		if err != nil || f == 0 {
+			f = 0 // -0 es igual a 0; los que exceden float64 comparten grupo
+		}
+		return mixHash(1, math.Float64bits(f))
+	}
+	switch v := value.(type) {
+	case string:
+		return mixHash(2, stringHash(v))
+	case bool:
+		if v {
+			return mixHash(3, 1)
+		}
+		return mixHash(3, 0)
+	case nil:
+		return mixHash(4, 0)
+	case []interface{}:
+		// La suma no depende del orden de los elementos
+		sum := uint64(len(v))
+		for _, item := range v {
+			sum += diffHash(item)
+		}
+		return mixHash(5, sum)
+	}
+	if object := objectValues(value); object != nil {
+		sum := uint64(len(object))
+		for key, item := range object {
+			sum += mixHash(stringHash(key), diffHash(item))
+		}
+		return mixHash(6, sum)
+	}
+	return 0
+}
+
+// mixHash combina dos valores de 64 bits (finalizador de SplitMix64)
+func mixHash(a, b uint64) uint64 {
+	x := a*0x9e3779b97f4a7c15 ^ b
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// stringHash FNV-1a de 64 bits de s
+func stringHash(s string) uint64 {
+	hash := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		hash ^= uint64(s[i])
+		hash *= 1099511628211
+	}
+	return hash
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// formatEntries resume las diferencias como "kind path" para comparar en los tests
+func formatEntries(t *testing.T, entries []DiffEntry) []string {
+	t.Helper()
+	result := []string{}
+	for _, entry := range entries {
+		line := entry.Kind + " " + entry.Path
+		if entry.Kind == DiffChanged {
+			line += " " + string(mustMarshal(t, entry.Old)) + "→" + string(mustMarshal(t, entry.New))
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// Test para la comparación estructural
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		opts DiffOptions
+		want []string
+	}{
+		{"iguales con otro orden y representación", `{"a":1,"b":[true,null]}`, `{"b":[true,null],"a":1.0}`, DiffOptions{}, []string{}},
+		{"claves", `{"a":1,"b":2}`, `{"b":3,"c":4}`, DiffOptions{}, []string{"removed /a", "changed /b 2→3", "added /c"}},
+		{"anidado", `{"x":{"y":[1,2]}}`, `{"x":{"y":[1,5,6]}}`, DiffOptions{}, []string{"changed /x/y/1 2→5", "added /x/y/2"}},
+		{"array más corto", `[1,2,3]`, `[1]`, DiffOptions{}, []string{"removed /1", "removed /2"}},
+		{"cambio de tipo", `{"a":[1]}`, `{"a":{"0":1}}`, DiffOptions{}, []string{`changed /a [1]→{"0":1}`}},
+		{"raíz escalar", `1`, `2`, DiffOptions{}, []string{"changed  1→2"}},
+		{"clave con /", `{"a/b":1}`, `{"a/b":2}`, DiffOptions{}, []string{"changed /a~1b 1→2"}},
+		{"orden de arrays", `[1,2,3]`, `[3,1,2]`, DiffOptions{}, []string{"added /0", "removed /2"}},
+		{"inserción al inicio", `[1,2,3]`, `[0,1,2,3]`, DiffOptions{}, []string{"added /0"}},
+		{"inserción en el medio", `[1,2,3,4]`, `[1,2,9,3,4]`, DiffOptions{}, []string{"added /2"}},
+		{"inserción y modificación", `[{"id":1},{"id":2,"v":"a"}]`, `[{"id":0},{"id":1},{"id":2,"v":"b"}]`, DiffOptions{}, []string{"added /0", `changed /1/v "a"→"b"`}},
+		{"eliminación en el medio", `["a","b","c","d"]`, `["a","d"]`, DiffOptions{}, []string{"removed /1", "removed /2"}},
+		{"ignorar orden", `[1,2,3]`, `[3,1,2]`, DiffOptions{IgnoreArrayOrder: true}, []string{}},
+		{"ignorar orden con cambios", `[1,2,2,{"a":[1,2]}]`, `[{"a":[2,1]},2,4,1]`, DiffOptions{IgnoreArrayOrder: true}, []string{"changed /2 2→4"}},
+		{"ignorar orden con modificado", `[{"id":1,"v":1},{"id":2}]`, `[{"id":2},{"id":1,"v":2}]`, DiffOptions{IgnoreArrayOrder: true}, []string{"changed /0/v 1→2"}},
+		{"ignorar ruta", `{"a":1,"meta":{"ts":1}}`, `{"a":2,"meta":{"ts":2,"by":"x"}}`, DiffOptions{IgnorePaths: []Pointer{NewPointer("meta")}}, []string{"changed /a 1→2"}},
+		{"ignorar ruta con comodín", `{"items":[{"id":1,"ts":1},{"id":2,"ts":1}]}`, `{"items":[{"id":1,"ts":5},{"id":3,"ts":6}]}`, DiffOptions{IgnorePaths: []Pointer{NewPointer("items", "*", "ts")}}, []string{"changed /items/1/id 2→3"}},
+		{"ignorar ruta eliminada", `{"a":1,"b":2}`, `{"a":1}`, DiffOptions{IgnorePaths: []Pointer{NewPointer("b")}}, []string{}},
+		{"alinear con rutas ignoradas", `[{"id":1,"t":1},{"id":2,"t":1}]`, `[{"id":2,"t":2}]`, DiffOptions{IgnorePaths: []Pointer{NewPointer("*", "t")}}, []string{"removed /0"}},
+		{"ignorar orden y rutas", `[{"id":1,"t":1},{"id":2,"t":1}]`, `[{"id":2,"t":5},{"id":1,"t":6}]`, DiffOptions{IgnoreArrayOrder: true, IgnorePaths: []Pointer{NewPointer("*", "t")}}, []string{}},
+		{"ignorar orden con otra representación", `[1,-0,"a",{"x":[1,2],"y":null}]`, `[{"y":null,"x":[2,1.0]},"a",0,1.0]`, DiffOptions{IgnoreArrayOrder: true}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatEntries(t, CompareValues(parseExact(t, tt.a), parseExact(t, tt.b), tt.opts))
+			if len(got) != len(tt.want) {
+				t.Fatalf("CompareValues() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("CompareValues()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// largeObjectArrays genera dos arrays de n objetos; el segundo en orden
+// inverso, con "t" igual a tb y con id 0 cambiado a -1
+func largeObjectArrays(t *testing.T, n, tb int) (interface{}, interface{}) {
+	t.Helper()
+	var a, b strings.Builder
+	a.WriteString("[")
+	b.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			a.WriteString(",")
+			b.WriteString(",")
+		}
+		id := n - 1 - i
+		if id == 0 {
+			id = -1
+		}
+		fmt.Fprintf(&a, `{"id":%d,"t":1}`, i)
+		fmt.Fprintf(&b, `{"id":%d,"t":%d}`, id, tb)
+	}
+	a.WriteString("]")
+	b.WriteString("]")
+	return parseExact(t, a.String()), parseExact(t, b.String())
+}
+
+// Test: los arrays grandes se comparan con un costo acotado
+func TestCompareValuesLargeArrays(t *testing.T) {
+	ignoreT := []Pointer{NewPointer("*", "t")}
+
+	// Sin rutas ignoradas bajo los elementos se agrupan por hash
+	a, b := largeObjectArrays(t, 50000, 1)
+	if got := formatEntries(t, CompareValues(a, b, DiffOptions{IgnoreArrayOrder: true})); len(got) != 1 || got[0] != "changed /0/id 0→-1" {
+		t.Errorf("CompareValues() sin orden = %v", got)
+	}
+
+	// Con rutas ignoradas la comparación por pares es exacta mientras alcance
+	// el presupuesto...
+	a, b = largeObjectArrays(t, 500, 2)
+	if got := formatEntries(t, CompareValues(a, b, DiffOptions{IgnoreArrayOrder: true, IgnorePaths: ignoreT})); len(got) != 1 || got[0] != "changed /0/id 0→-1" {
+		t.Errorf("CompareValues() sin orden con rutas ignoradas = %v", got)
+	}
+
+	// ...y al agotarlo los elementos restantes se comparan en orden
+	a, b = largeObjectArrays(t, 5000, 2)
+	for _, opts := range []DiffOptions{{IgnoreArrayOrder: true, IgnorePaths: ignoreT}, {IgnorePaths: ignoreT}} {
+		for _, entry := range CompareValues(a, b, opts) {
+			if strings.HasSuffix(entry.Path, "/t") {
+				t.Fatalf("CompareValues(%+v) informó una ruta ignorada: %s", opts, entry.Path)
+			}
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// EncodeOptions modos de serialización del árbol de valores
+type EncodeOptions struct {
+	// Indent sangría por nivel; vacío produce salida compacta
+	Indent string
+
+	// SortKeys ordena las claves de todos los objetos (también los ordenados)
+	SortKeys bool
+
+	// ASCIIOnly escapa todo carácter no ASCII como \uXXXX (con pares surrogate)
+	ASCIIOnly bool
+
+	// MaxInlineWidth con sangría, escribe en una sola línea los objetos y
+	// arrays que caben completos dentro de ese ancho (0 lo desactiva)
+	MaxInlineWidth int
+}
+
+// Encoder escribe valores JSON en un io.Writer, uno por línea, a partir del
+// árbol que produce el parser: map[string]interface{}, *OrderedObject,
+// []interface{}, string, float64, int64, Number, bool y nil
+type Encoder struct {
+	w    io.Writer
+	opts EncodeOptions
+}
+
+// NewEncoder crea un Encoder con salida compacta
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetIndent define la sangría por nivel ("" para salida compacta)
+func (e *Encoder) SetIndent(indent string) {
+	e.opts.Indent = indent
+}
+
+// SetSortKeys activa el orden alfabético de claves
+func (e *Encoder) SetSortKeys(sortKeys bool) {
+	e.opts.SortKeys = sortKeys
+}
+
+// SetASCIIOnly activa el escape de todo carácter no ASCII
+func (e *Encoder) SetASCIIOnly(asciiOnly bool) {
+	e.opts.ASCIIOnly = asciiOnly
+}
+
+// Encode serializa value seguido de un salto de línea
+func (e *Encoder) Encode(value interface{}) error {
+	data, err := MarshalWithOptions(value, e.opts)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+// Marshal serializa un valor en forma compacta
+func Marshal(value interface{}) ([]byte, error) {
+	return MarshalWithOptions(value, EncodeOptions{})
+}
+
+// MarshalIndent serializa un valor con la sangría indicada por nivel
+func MarshalIndent(value interface{}, indent string) ([]byte, error) {
+	return MarshalWithOptions(value, EncodeOptions{Indent: indent})
+}
+
+// MarshalWithOptions serializa un valor con todas las opciones disponibles
+func MarshalWithOptions(value interface{}, opts EncodeOptions) ([]byte, error) {
+	state := &encodeState{opts: opts}
+	if err := state.appendValue(value, 0); err != nil {
+		return nil, err
+	}
+	return state.buf, nil
+}
+
+// FormatValue serializa un valor parseado con sangría, respetando el orden de
+// los *OrderedObject
+func FormatValue(value interface{}, indent string) (string, error) {
+	data, err := MarshalIndent(value, indent)
+	return string(data), err
+}
+
+// encodeState buffer de salida de una serialización
+type encodeState struct {
+	buf  []byte
+	opts EncodeOptions
+
+	// inline escribe los contenedores en una línea con ", " y ": "
+	inline bool
+
+	// inlineLimit en modo inline, cantidad máxima de caracteres; al superarla
+	// la serialización se corta con errInlineOverflow
+	inlineLimit int
+}
+
+// errInlineOverflow corta un intento de escritura en una línea que ya no cabe
+var errInlineOverflow = errors.New("el valor no cabe en una línea")
+
+// appendValue serializa cualquier valor soportado del árbol
+func (e *encodeState) appendValue(value interface{}, depth int) error {
+	if e.opts.Indent != "" && e.opts.MaxInlineWidth > 0 && !e.inline && isContainer(value) {
+		if fits, err := e.appendInline(value, depth); fits || err != nil {
+			return err
+		}
+	}
+	if e.inline && e.overflowed() {
+		return errInlineOverflow
+	}
+
+	switch v := value.(type) {
+	case nil:
+		e.buf = append(e.buf, "null"...)
+	case bool:
+		e.buf = strconv.AppendBool(e.buf, v)
+	case string:
+		if e.inline && len(v) > utf8.UTFMax*e.inlineLimit {
+			return errInlineOverflow
+		}
+		e.appendString(v)
+	case float64:
+		return e.appendFloat(v)
+	case int64:
+		e.buf = strconv.AppendInt(e.buf, v, 10)
+	case int:
+		e.buf = strconv.AppendInt(e.buf, int64(v), 10)
+	case Number:
+		return e.appendNumber(string(v))
+	case json.Number:
+		return e.appendNumber(string(v))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		// Un mapa no tiene orden propio: siempre se ordena para una salida estable
+		sort.Strings(keys)
+		return e.appendObject(keys, v, depth)
+	case *OrderedObject:
+		keys := v.Keys
+		if e.opts.SortKeys {
+			keys = append([]string(nil), keys...)
+			sort.Strings(keys)
+		}
+		return e.appendObject(keys, v.Values, depth)
+	case []interface{}:
+		return e.appendArray(v, depth)
+	default:
+		return fmt.Errorf("tipo no soportado por el encoder: %T", value)
+	}
+	return nil
+}
+
+// isContainer indica si el valor es un objeto o array no vacío
+func isContainer(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return len(v) > 0
+	case *OrderedObject:
+		return v.Len() > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return false
+}
+
+// appendInline escribe el contenedor en una sola línea si cabe en
+// MaxInlineWidth contando desde la columna actual. El intento se abandona en
+// cuanto supera el ancho, así que cada nivel recorre a lo sumo una línea y no
+// todo su subárbol
+func (e *encodeState) appendInline(value interface{}, depth int) (bool, error) {
+	column := utf8.RuneCount(e.buf[bytes.LastIndexByte(e.buf, '\n')+1:])
+	inline := &encodeState{opts: e.opts, inline: true, inlineLimit: e.opts.MaxInlineWidth - column}
+	if inline.inlineLimit <= 0 {
+		return false, nil
+	}
+	if err := inline.appendValue(value, depth); err != nil {
+		if err == errInlineOverflow {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if inline.overflowed() {
+		return false, nil
+	}
+	e.buf = append(e.buf, inline.buf...)
+	return true, nil
+}
+
+// overflowed indica si lo escrito en modo inline ya supera inlineLimit
+func (e *encodeState) overflowed() bool {
+	if len(e.buf) <= e.inlineLimit {
+		return false
+	}
+	return utf8.RuneCount(e.buf) > e.inlineLimit
+}
+
+// appendObject serializa las claves en el orden recibido
+func (e *encodeState) appendObject(keys []string, values map[string]interface{}, depth int) error {
+	if len(keys) == 0 {
+		e.buf = append(e.buf, "{}"...)
+		return nil
+	}
+
+	e.buf = append(e.buf, '{')
+	for i, key := range keys {
+		if i > 0 {
+			e.separator()
+		}
+		e.newline(depth + 1)
+		e.appendString(key)
+		e.buf = append(e.buf, ':')
+		if e.opts.Indent != "" {
+			e.buf = append(e.buf, ' ')
+		}
+		if err := e.appendValue(values[key], depth+1); err != nil {
+			return err
+		}
+	}
+	e.newline(depth)
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+// appendArray serializa los elementos de un array
+func (e *encodeState) appendArray(items []interface{}, depth int) error {
+	if len(items) == 0 {
+		e.buf = append(e.buf, "[]"...)
+		return nil
+	}
+
+	e.buf = append(e.buf, '[')
+	for i, item := range items {
+		if i > 0 {
+			e.separator()
+		}
+		e.newline(depth + 1)
+		if err := e.appendValue(item, depth+1); err != nil {
+			return err
+		}
+	}
+	e.newline(depth)
+	e.buf = append(e.buf, ']')
+	return nil
+}
+
+// separator escribe la coma entre elementos
+func (e *encodeState) separator() {
+	e.buf = append(e.buf, ',')
+	if e.inline {
+		e.buf = append(e.buf, ' ')
+	}
+}
+
+// newline inicia una línea con la sangría del nivel (solo en modo indentado)
+func (e *encodeState) newline(depth int) {
+	if e.opts.Indent == "" || e.inline {
+		return
+	}
+	e.buf = append(e.buf, '\n')
+	for i := 0; i < depth; i++ {
+		e.buf = append(e.buf, e.opts.Indent...)
+	}
+}
+
+// appendFloat usa la misma notación que encoding/json: decimal salvo para
+// magnitudes muy pequeñas o muy grandes
+func (e *encodeState) appendFloat(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("número no representable en JSON: %v", f)
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	e.buf = strconv.AppendFloat(e.buf, f, format, -1, 64)
+	if format == 'e' {
+		// e-07 → e-7
+		n := len(e.buf)
+		if n >= 4 && e.buf[n-4] == 'e' && e.buf[n-3] == '-' && e.buf[n-2] == '0' {
+			e.buf[n-2] = e.buf[n-1]
+			e.buf = e.buf[:n-1]
+		}
+	}
+	return nil
+}
+
+// appendNumber escribe un literal numérico tal cual, verificando que sea JSON válido
+func (e *encodeState) appendNumber(literal string) error {
+	if literal == "" {
+		e.buf = append(e.buf, '0')
+		return nil
+	}
+	lex := newLexer([]byte(literal))
+	if tok, err := lex.scanNumber(); err != nil || tok.end != len(literal) {
+		return fmt.Errorf("número inválido: %s", literal)
+	}
+	e.buf = append(e.buf, literal...)
+	return nil
+}
+
+// appendString escribe un string con el escape mínimo de JSON; en modo
+// ASCIIOnly además escapa todo carácter fuera de ASCII
+func (e *encodeState) appendString(s string) {
+	e.buf = append(e.buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			e.buf = append(e.buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				e.buf = append(e.buf, '\\', c)
+			case '\b':
+				e.buf = append(e.buf, '\\', 'b')
+			case '\f':
+				e.buf = append(e.buf, '\\', 'f')
+			case '\n':
+				e.buf = append(e.buf, '\\', 'n')
+			case '\r':
+				e.buf = append(e.buf, '\\', 'r')
+			case '\t':
+				e.buf = append(e.buf, '\\', 't')
+			default:
+				e.appendUnicodeEscape(rune(c))
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		invalid := r == utf8.RuneError && size == 1
+		if !invalid && !e.opts.ASCIIOnly {
+			i += size
+			continue
+		}
+
+		e.buf = append(e.buf, s[start:i]...)
+		switch {
+		case invalid && !e.opts.ASCIIOnly:
+			e.buf = utf8.AppendRune(e.buf, utf8.RuneError)
+		case r > 0xFFFF:
+			r1, r2 := utf16.EncodeRune(r)
+			e.appendUnicodeEscape(r1)
+			e.appendUnicodeEscape(r2)
+		default:
+			e.appendUnicodeEscape(r)
+		}
+		i += size
+		start = i
+	}
+	e.buf = append(e.buf, s[start:]...)
+	e.buf = append(e.buf, '"')
+}
+
+// appendUnicodeEscape escribe \uXXXX en hexadecimal minúscula
+func (e *encodeState) appendUnicodeEscape(r rune) {
+	const hex = "0123456789abcdef"
+	e.buf = append(e.buf, '\\', 'u', hex[r>>12&0xF], hex[r>>8&0xF], hex[r>>4&0xF], hex[r&0xF])
+}
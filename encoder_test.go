@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test para los modos de serialización del encoder
+func TestMarshalWithOptions(t *testing.T) {
+	ordered := NewOrderedObject()
+	ordered.Set("z", 1.0)
+	ordered.Set("a", []interface{}{"ñ", true, nil})
+	ordered.Set("m", NewOrderedObject())
+
+	tests := []struct {
+		name  string
+		value interface{}
+		opts  EncodeOptions
+		want  string
+	}{
+		{"compacto respeta el orden", ordered, EncodeOptions{}, `{"z":1,"a":["ñ",true,null],"m":{}}`},
+		{"claves ordenadas", ordered, EncodeOptions{SortKeys: true}, `{"a":["ñ",true,null],"m":{},"z":1}`},
+		{"solo ASCII", ordered, EncodeOptions{ASCIIOnly: true}, `{"z":1,"a":["\u00f1",true,null],"m":{}}`},
+		{"indentado", ordered, EncodeOptions{Indent: "  "}, "{\n  \"z\": 1,\n  \"a\": [\n    \"ñ\",\n    true,\n    null\n  ],\n  \"m\": {}\n}"},
+		{"mapa con claves ordenadas", map[string]interface{}{"b": 2.0, "a": []interface{}{}}, EncodeOptions{}, `{"a":[],"b":2}`},
+		{"Number exacto", []interface{}{Number("9007199254740993"), Number("1.10"), int64(-7)}, EncodeOptions{}, `[9007199254740993,1.10,-7]`},
+		{"float64 como encoding/json", []interface{}{0.000001, 1e-7, 1e21, 123456789.0, -0.5}, EncodeOptions{}, `[0.000001,1e-7,1e+21,123456789,-0.5]`},
+		{"emoji con surrogates", "😀", EncodeOptions{ASCIIOnly: true}, `"\ud83d\ude00"`},
+		{"escape mínimo", "a\"b\\c\n\t\x01</>&\u2028", EncodeOptions{}, `"a\"b\\c\n\t\u0001</>&` + "\u2028" + `"`},
+		{"UTF-8 inválido", "a\xffb", EncodeOptions{}, "\"a\ufffdb\""},
+		{"UTF-8 inválido en ASCII", "a\xffb", EncodeOptions{ASCIIOnly: true}, `"a\ufffdb"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalWithOptions(tt.value, tt.opts)
+			if err != nil {
+				t.Fatalf("MarshalWithOptions() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalWithOptions() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test de ida y vuelta: parsear, serializar y volver a parsear
+func TestMarshalRoundTrip(t *testing.T) {
+	input := generateLargeJSON(20)
+	parser := NewParser()
+	opts := ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber}
+
+	original, err := parser.ParseJSONWithOptions(input, opts)
+	if err != nil {
+		t.Fatalf("ParseJSONWithOptions() error = %v", err)
+	}
+
+	for _, encodeOpts := range []EncodeOptions{{}, {Indent: "\t"}, {ASCIIOnly: true}, {SortKeys: true}} {
+		data, err := MarshalWithOptions(original, encodeOpts)
+		if err != nil {
+			t.Fatalf("MarshalWithOptions(%+v) error = %v", encodeOpts, err)
+		}
+		again, err := parser.ParseJSONWithOptions(string(data), opts)
+		if err != nil {
+			t.Fatalf("ParseJSONWithOptions(salida %+v) error = %v", encodeOpts, err)
+		}
+		if !EqualValues(original, again) {
+			t.Errorf("la ida y vuelta con %+v cambió el valor", encodeOpts)
+		}
+		if !encodeOpts.SortKeys && !reflect.DeepEqual(original.(*OrderedObject).Keys, again.(*OrderedObject).Keys) {
+			t.Errorf("la ida y vuelta con %+v cambió el orden de las claves", encodeOpts)
+		}
+	}
+
+	// La salida compacta coincide con encoding/json para el árbol por defecto
+	plain, _ := parser.ParseJSON(input)
+	got, _ := Marshal(plain)
+	var native bytes.Buffer
+	encoder := json.NewEncoder(&native)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(plain)
+	if string(got) != strings.TrimSuffix(native.String(), "\n") {
+		t.Error("Marshal() difiere de encoding/json para el árbol sin orden")
+	}
+}
+
+// Test para los valores que no se pueden serializar
+func TestMarshalErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		errSubstr string
+	}{
+		{"NaN", math.NaN(), "no representable"},
+		{"infinito anidado", []interface{}{math.Inf(1)}, "no representable"},
+		{"Number inválido", Number("01"), "número inválido"},
+		{"tipo desconocido", struct{}{}, "tipo no soportado"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Marshal(tt.value); err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("Marshal() error = %v, want %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
+
+// Test para el Encoder sobre un io.Writer (un documento por línea)
+func TestEncoderStream(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf)
+	encoder.SetSortKeys(true)
+	encoder.Encode(map[string]interface{}{"b": 1.0, "a": 2.0})
+	encoder.SetASCIIOnly(true)
+	encoder.Encode("é")
+
+	want := "{\"a\":2,\"b\":1}\n\"\\u00e9\"\n"
+	if buf.String() != want {
+		t.Errorf("Encode() = %q, want %q", buf.String(), want)
+	}
+
+	dec := NewDecoder(&buf)
+	for i := 0; i < 2; i++ {
+		if _, err := dec.Decode(); err != nil {
+			t.Fatalf("Decode() del documento %d error = %v", i, err)
+		}
+	}
+}
+
+// Test para el ancho máximo en línea del modo indentado
+func TestMarshalMaxInlineWidth(t *testing.T) {
+	value, err := NewParser().ParseJSONWithOptions(`{"punto":{"x":1,"y":2},"tags":["a","b"],"largo":["aaaaaaaaaa","bbbbbbbbbb","cccccccccc"]}`, ParseOptions{PreserveOrder: true})
+	if err != nil {
+		t.Fatalf("ParseJSONWithOptions() error = %v", err)
+	}
+
+	got, err := MarshalWithOptions(value, EncodeOptions{Indent: "  ", MaxInlineWidth: 30})
+	if err != nil {
+		t.Fatalf("MarshalWithOptions() error = %v", err)
+	}
+	want := `{
+  "punto": {"x": 1, "y": 2},
+  "tags": ["a", "b"],
+  "largo": [
+    "aaaaaaaaaa",
+    "bbbbbbbbbb",
+    "cccccccccc"
+  ]
+}`
+	if string(got) != want {
+		t.Errorf("MarshalWithOptions() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Con ancho suficiente todo el documento queda en una línea
+	got, _ = MarshalWithOptions(value, EncodeOptions{Indent: "\t", MaxInlineWidth: 200})
+	if strings.Contains(string(got), "\n") {
+		t.Errorf("MarshalWithOptions() = %s, se esperaba una sola línea", got)
+	}
+}
+
+// Test: con anidación profunda cada nivel abandona el intento en línea en
+// cuanto supera el ancho, sin volver a serializar todo su subárbol
+func TestMarshalMaxInlineWidthDeep(t *testing.T) {
+	const depth = 2000
+	var value interface{} = []interface{}{"ñandú", strings.Repeat("x", 100000)}
+	for i := 0; i < depth; i++ {
+		value = []interface{}{value}
+	}
+
+	got, err := MarshalWithOptions(value, EncodeOptions{Indent: " ", MaxInlineWidth: 80})
+	if err != nil {
+		t.Fatalf("MarshalWithOptions() error = %v", err)
+	}
+	if lines := strings.Count(string(got), "\n"); lines != 2*depth+3 {
+		t.Errorf("MarshalWithOptions() = %d líneas, want %d", lines, 2*depth+3)
+	}
+
+	// Los caracteres no ASCII cuentan como uno al medir el ancho
+	got, _ = MarshalWithOptions([]interface{}{"ñññññ"}, EncodeOptions{Indent: " ", MaxInlineWidth: 9})
+	if string(got) != `["ñññññ"]` {
+		t.Errorf("MarshalWithOptions() = %s", got)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetWidth ancho máximo (en caracteres) del fragmento de contexto
+const snippetWidth = 60
+
+// SyntaxError error de sintaxis con la ubicación exacta dentro del documento
+type SyntaxError struct {
+	Msg      string `json:"message"`            // descripción del problema
+	Offset   int    `json:"offset"`             // offset absoluto en bytes
+	Line     int    `json:"line"`               // línea, comenzando en 1
+	Column   int    `json:"column"`             // columna en caracteres, comenzando en 1
+	Expected string `json:"expected,omitempty"` // token esperado, si se conoce
+	Snippet  string `json:"snippet"`            // línea de contexto con un caret bajo el error
+}
+
+// Error implementa la interfaz error
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s en línea %d, columna %d", e.Msg, e.Line, e.Column)
+}
+
+// newSyntaxError calcula línea, columna y fragmento para un offset del documento
+func newSyntaxError(data []byte, offset int, expected, msg string) *SyntaxError {
+	return locateSyntaxError(data, offset, 0, 1, 0, expected, msg)
+}
+
+// locateSyntaxError ubica un error cuando data es solo una ventana del documento
+// (lectura por flujo): base, baseLine y baseColumn describen la posición de data[0]
+func locateSyntaxError(data []byte, index, base, baseLine, baseColumn int, expected, msg string) *SyntaxError {
+	if index > len(data) {
+		index = len(data)
+	}
+
+	line := baseLine + bytes.Count(data[:index], []byte("\n"))
+	lineStart := 0
+	column := baseColumn + 1 + utf8.RuneCount(data[:index])
+	if i := bytes.LastIndexByte(data[:index], '\n'); i >= 0 {
+		lineStart = i + 1
+		column = 1 + utf8.RuneCount(data[lineStart:index])
+	}
+
+	return &SyntaxError{
+		Msg:      msg,
+		Offset:   base + index,
+		Line:     line,
+		Column:   column,
+		Expected: expected,
+		Snippet:  buildSnippet(data, lineStart, index, lineStart == 0 && baseColumn > 0),
+	}
+}
+
+// buildSnippet devuelve la línea del error recortada alrededor de la posición
+// y una segunda línea con '^' apuntando a la columna exacta
+func buildSnippet(data []byte, lineStart, offset int, truncated bool) string {
+	lineEnd := len(data)
+	if i := bytes.IndexByte(data[offset:], '\n'); i >= 0 {
+		lineEnd = offset + i
+	}
+
+	before := []rune(string(data[lineStart:offset]))
+	after := []rune(strings.TrimSuffix(string(data[offset:lineEnd]), "\r"))
+
+	prefix, suffix := "", ""
+	if len(before) > snippetWidth/2 {
+		before = before[len(before)-snippetWidth/2:]
+		truncated = true
+	}
+	if truncated {
+		prefix = "..."
+	}
+	if len(after) > snippetWidth/2 {
+		after = after[:snippetWidth/2]
+		suffix = "..."
+	}
+
+	// Conservar tabulaciones para que el caret quede alineado
+	var caret strings.Builder
+	caret.WriteString(strings.Repeat(" ", len(prefix)))
+	for _, r := range before {
+		if r == '\t' {
+			caret.WriteRune('\t')
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+	caret.WriteRune('^')
+
+	return prefix + string(before) + string(after) + suffix + "\n" + caret.String()
+}
+
+// AsSyntaxError extrae el SyntaxError de un error, si lo contiene
+func AsSyntaxError(err error) *SyntaxError {
+	var syntaxErr *SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return syntaxErr
+	}
+	return nil
+}
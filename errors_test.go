@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// Test para la ubicación exacta reportada por SyntaxError
+func TestSyntaxErrorLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		offset   int
+		line     int
+		column   int
+		expected string
+		snippet  string
+	}{
+		{"Coma extra en segunda línea", "{\n  \"a\": 1,\n}", 10, 2, 9, "clave entre comillas",
+			"  \"a\": 1,\n        ^"},
+		{"Clave sin comillas", `{a: 1}`, 1, 1, 2, "clave entre comillas o '}'",
+			"{a: 1}\n ^"},
+		{"Objeto sin cerrar", "{\"a\": 1", 7, 1, 8, "'}'",
+			"{\"a\": 1\n       ^"},
+		{"Columna con caracteres multibyte", `["ñandú" 1]`, 11, 1, 10, "',' o ']'",
+			"[\"ñandú\" 1]\n         ^"},
+		{"Tabulaciones conservadas", "[\n\t\ttrue false]", 9, 2, 8, "',' o ']'",
+			"\t\ttrue false]\n\t\t     ^"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser().ParseJSON(tt.input)
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParseJSON() error = %v, want *SyntaxError", err)
+			}
+			if syntaxErr.Offset != tt.offset || syntaxErr.Line != tt.line || syntaxErr.Column != tt.column {
+				t.Errorf("posición = (%d, %d:%d), want (%d, %d:%d)",
+					syntaxErr.Offset, syntaxErr.Line, syntaxErr.Column, tt.offset, tt.line, tt.column)
+			}
+			if syntaxErr.Expected != tt.expected {
+				t.Errorf("Expected = %q, want %q", syntaxErr.Expected, tt.expected)
+			}
+			if syntaxErr.Snippet != tt.snippet {
+				t.Errorf("Snippet = %q, want %q", syntaxErr.Snippet, tt.snippet)
+			}
+		})
+	}
+}
+
+// Test para verificar que la validación rápida también devuelve SyntaxError
+func TestFastValidateJSONSyntaxError(t *testing.T) {
+	err := NewParser().FastValidateJSON("[1, 2,\n]")
+	syntaxErr := AsSyntaxError(err)
+	if syntaxErr == nil {
+		t.Fatalf("FastValidateJSON() error = %v, want *SyntaxError", err)
+	}
+	if syntaxErr.Line != 1 || syntaxErr.Column != 6 {
+		t.Errorf("FastValidateJSON() posición = %d:%d, want 1:6", syntaxErr.Line, syntaxErr.Column)
+	}
+}
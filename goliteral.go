@@ -0,0 +1,297 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// bytesPerLine bytes por línea en los literales []byte
+const bytesPerLine = 16
+
+// maxStringLiteralParts máximo de operandos concatenados en un literal de
+// goStringLiteral: cada '+' anida la expresión y go/parser limita la
+// profundidad, así que un texto con muchos tramos inseguros (un archivo CRLF
+// tiene uno por línea) se escribe como un único literal interpretado
+const maxStringLiteralParts = 8
+
+// goStringLiteral devuelve una expresión Go de tipo string (constante) cuyo
+// valor es exactamente content. Usa un literal raw cuando es seguro; si el
+// texto contiene backticks o caracteres que un literal raw no conserva (\r,
+// NUL, BOM, controles) concatena tramos raw con literales interpretados, y si
+// no es UTF-8 válido o harían falta más de maxStringLiteralParts tramos
+// recurre a un único literal interpretado.
+func goStringLiteral(content string) string {
+	if !utf8.ValidString(content) {
+		return strconv.Quote(content)
+	}
+
+	var parts []string
+	start, i := 0, 0
+	for i < len(content) {
+		r, size := utf8.DecodeRuneInString(content[i:])
+		if rawSafeRune(r) {
+			i += size
+			continue
+		}
+		if start < i {
+			parts = append(parts, "`"+content[start:i]+"`")
+		}
+		// Agrupa los caracteres inseguros consecutivos en un solo literal
+		end := i + size
+		for end < len(content) {
+			next, nextSize := utf8.DecodeRuneInString(content[end:])
+			if rawSafeRune(next) {
+				break
+			}
+			end += nextSize
+		}
+		parts = append(parts, strconv.Quote(content[i:end]))
+		if len(parts) >= maxStringLiteralParts {
+			return strconv.Quote(content)
+		}
+		start, i = end, end
+	}
+	if start < len(content) || len(parts) == 0 {
+		parts = append(parts, "`"+content[start:]+"`")
+	}
+	return strings.Join(parts, " + ")
+}
+
+// rawSafeRune indica si r se puede escribir tal cual dentro de un literal raw:
+// el backtick lo cerraría, el compilador descarta \r y rechaza NUL y BOM, y
+// los demás controles se escapan para que el código sea legible
+func rawSafeRune(r rune) bool {
+	switch {
+	case r == '\t' || r == '\n':
+		return true
+	case r == '`' || r == '\uFEFF' || r == 0x7F:
+		return false
+	}
+	return r >= 0x20
+}
+
+// isBinaryContent indica si content no es texto: UTF-8 inválido o bytes NUL
+func isBinaryContent(content string) bool {
+	return !utf8.ValidString(content) || strings.IndexByte(content, 0) >= 0
+}
+
+// goBytesLiteral devuelve un literal []byte con los bytes en hexadecimal,
+// bytesPerLine por línea, para datos binarios
+func goBytesLiteral(content string) string {
+	var builder strings.Builder
+	builder.WriteString("[]byte{")
+	for i := 0; i < len(content); i++ {
+		if i%bytesPerLine == 0 {
+			builder.WriteString("\n\t")
+		} else {
+			builder.WriteString(" ")
+		}
+		fmt.Fprintf(&builder, "0x%02x,", content[i])
+	}
+	builder.WriteString("\n}")
+	return builder.String()
+}
+
+// goCommentText adapta un texto para usarlo en un comentario de línea
+func goCommentText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7F {
+			return ' '
+		}
+		return r
+	}, strings.ToValidUTF8(text, "�"))
+}
+
+// goLiteralWriter escribe valores del documento como literales Go
+type goLiteralWriter struct {
+	builder   strings.Builder
+	ptrHelper string // función genérica para punteros a escalares
+	usedPtr   bool
+	err       error
+}
+
+// GoValueLiteral escribe value como literal Go de tipos dinámicos: objetos
+// como map[string]any (en el orden del documento), arrays como []any y
+// números como float64, igual que ParseJSON por defecto. Falla si un número
+// no es representable como float64 (p. ej. 1e400).
+func GoValueLiteral(value interface{}) (string, error) {
+	w := &goLiteralWriter{}
+	w.dynamic(value, 0)
+	return w.builder.String(), w.err
+}
+
+// GoTypedLiteral genera los tipos de GenerateGoStructs y un literal del tipo
+// raíz con el contenido de value, para asignarlo a variableName. Los campos
+// escalares que admiten null se inicializan con ptrHelper, una función
+// genérica que se declara junto con los tipos solo si hace falta. Ningún tipo
+// generado se llama como la variable o la función.
+func GoTypedLiteral(value interface{}, rootName, variableName, ptrHelper string) (types, literal string, err error) {
+	g, shape, name := newGoStructs(value, rootName, variableName, ptrHelper)
+	w := &goLiteralWriter{ptrHelper: ptrHelper}
+	if shape.fields == nil {
+		// Tipo raíz con nombre propio sobre un slice, escalar o interface{}
+		w.builder.WriteString(name + "(")
+		w.typed(value, shape, 0, false)
+		w.builder.WriteString(")")
+	} else {
+		w.typed(value, shape, 0, false)
+	}
+	if w.err != nil {
+		return "", "", w.err
+	}
+
+	if w.usedPtr {
+		g.decls = append(g.decls, fmt.Sprintf("// %s devuelve un puntero a una copia de v\nfunc %s[T any](v T) *T {\n\treturn &v\n}", ptrHelper, ptrHelper))
+	}
+	types, err = g.source()
+	return types, w.builder.String(), err
+}
+
+func (w *goLiteralWriter) indent(depth int) {
+	w.builder.WriteString(strings.Repeat("\t", depth))
+}
+
+// dynamic escribe un valor con tipos map[string]any/[]any
+func (w *goLiteralWriter) dynamic(value interface{}, depth int) {
+	switch v := value.(type) {
+	case nil:
+		w.builder.WriteString("nil")
+	case bool:
+		w.builder.WriteString(strconv.FormatBool(v))
+	case string:
+		w.builder.WriteString(strconv.Quote(v))
+	case []interface{}:
+		w.builder.WriteString("[]any{")
+		for _, item := range v {
+			w.builder.WriteString("\n")
+			w.indent(depth + 1)
+			w.dynamic(item, depth+1)
+			w.builder.WriteString(",")
+		}
+		w.closeBrace(len(v) > 0, depth)
+	default:
+		if members := objectValues(value); members != nil {
+			keys := orderedKeys(value)
+			w.builder.WriteString("map[string]any{")
+			for _, key := range keys {
+				w.builder.WriteString("\n")
+				w.indent(depth + 1)
+				w.builder.WriteString(strconv.Quote(key) + ": ")
+				w.dynamic(members[key], depth+1)
+				w.builder.WriteString(",")
+			}
+			w.closeBrace(len(keys) > 0, depth)
+			return
+		}
+		w.builder.WriteString(w.floatLiteral(value))
+	}
+}
+
+// typed escribe un valor con el tipo que el generador de structs asignó a su
+// forma; elide omite el nombre del tipo en los elementos de un slice
+func (w *goLiteralWriter) typed(value interface{}, shape *goShape, depth int, elide bool) {
+	goType := shape.goType
+	baseType := strings.TrimPrefix(goType, "*")
+	pointer := baseType != goType
+
+	switch {
+	case value == nil:
+		w.builder.WriteString("nil")
+	case goType == "interface{}" || goType == "[]interface{}" || goType == "map[string]interface{}":
+		w.dynamic(value, depth)
+	case shape.fields != nil:
+		if !elide {
+			if pointer {
+				w.builder.WriteString("&")
+			}
+			w.builder.WriteString(baseType)
+		}
+		w.builder.WriteString("{")
+		members := objectValues(value)
+		written := false
+		for _, key := range orderedKeys(value) {
+			if members[key] == nil {
+				continue // el valor cero del campo ya es nil
+			}
+			w.builder.WriteString("\n")
+			w.indent(depth + 1)
+			w.builder.WriteString(shape.fields[key] + ": ")
+			w.typed(members[key], shape.properties[key], depth+1, false)
+			w.builder.WriteString(",")
+			written = true
+		}
+		w.closeBrace(written, depth)
+	case strings.HasPrefix(goType, "[]"):
+		items := value.([]interface{})
+		if !elide {
+			w.builder.WriteString(goType)
+		}
+		w.builder.WriteString("{")
+		for _, item := range items {
+			w.builder.WriteString("\n")
+			w.indent(depth + 1)
+			w.typed(item, shape.items, depth+1, true)
+			w.builder.WriteString(",")
+		}
+		w.closeBrace(len(items) > 0, depth)
+	default:
+		literal := w.scalarLiteral(value, baseType)
+		if pointer {
+			w.usedPtr = true
+			literal = fmt.Sprintf("%s[%s](%s)", w.ptrHelper, baseType, literal)
+		}
+		w.builder.WriteString(literal)
+	}
+}
+
+// closeBrace cierra un literal compuesto, en su propia línea si tuvo elementos
+func (w *goLiteralWriter) closeBrace(multiline bool, depth int) {
+	if multiline {
+		w.builder.WriteString("\n")
+		w.indent(depth)
+	}
+	w.builder.WriteString("}")
+}
+
+// scalarLiteral escribe un string, bool o número del tipo Go indicado
+func (w *goLiteralWriter) scalarLiteral(value interface{}, goType string) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return strconv.Quote(v)
+	}
+	if goType == "int64" {
+		if number, ok := value.(Number); ok {
+			return string(number)
+		}
+		if number, ok := value.(int64); ok {
+			return strconv.FormatInt(number, 10)
+		}
+	}
+	return w.floatLiteral(value)
+}
+
+// floatLiteral escribe un número como constante de punto flotante (con parte
+// decimal o exponente, para que en un contexto any sea float64)
+func (w *goLiteralWriter) floatLiteral(value interface{}) string {
+	text := ""
+	switch v := value.(type) {
+	case Number:
+		text = string(v)
+		if _, err := strconv.ParseFloat(text, 64); err != nil && w.err == nil {
+			w.err = fmt.Errorf("el número %s no se puede representar como float64", text)
+		}
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	default:
+		number, _ := numericLiteral(value)
+		text = strconv.FormatFloat(number.float, 'g', -1, 64)
+	}
+	if !strings.ContainsAny(text, ".eE") {
+		text += ".0"
+	}
+	return text
+}
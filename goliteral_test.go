@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test para los literales dinámicos (map[string]any / []any)
+func TestGoValueLiteral(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"escalares", `[1, 2.5, -3e2, "a\"b", true, null]`, "[]any{\n\t1.0,\n\t2.5,\n\t-3e2,\n\t\"a\\\"b\",\n\ttrue,\n\tnil,\n}"},
+		{"objeto en orden", `{"z": {}, "a": []}`, "map[string]any{\n\t\"z\": map[string]any{},\n\t\"a\": []any{},\n}"},
+		{"anidado", `{"a": [{"b": 1}]}`, "map[string]any{\n\t\"a\": []any{\n\t\tmap[string]any{\n\t\t\t\"b\": 1.0,\n\t\t},\n\t},\n}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GoValueLiteral(parseExact(t, tt.input))
+			if err != nil {
+				t.Fatalf("GoValueLiteral() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GoValueLiteral() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := GoValueLiteral(parseExact(t, `[1e400]`)); err == nil || !strings.Contains(err.Error(), "1e400") {
+		t.Errorf("GoValueLiteral(1e400) error = %v", err)
+	}
+}
+
+// Test: el literal con tipos generados usa los structs y compila
+func TestGoTypedLiteral(t *testing.T) {
+	input := `{"id": 1, "tags": ["a"], "owner": {"name": "x", "age": null}, "items": [{"n": 1, "note": "x"}, {"n": 2, "note": null}], "extra": {}, "any": [1, "a"]}`
+	types, literal, err := GoTypedLiteral(parseExact(t, input), "Config", "config", "configPtr")
+	if err != nil {
+		t.Fatalf("GoTypedLiteral() error = %v", err)
+	}
+
+	want := "Config{\n" +
+		"\tID: 1,\n" +
+		"\tTags: []string{\n\t\t\"a\",\n\t},\n" +
+		"\tOwner: Owner{\n\t\tName: \"x\",\n\t},\n" +
+		"\tItems: []Item{\n\t\t{\n\t\t\tN:    1,\n\t\t\tNote: configPtr[string](\"x\"),\n\t\t},\n\t\t{\n\t\t\tN: 2,\n\t\t},\n\t},\n" +
+		"\tExtra: map[string]any{},\n" +
+		"\tAny: []any{\n\t\t1.0,\n\t\t\"a\",\n\t},\n" +
+		"}"
+	source := "package datos\n\nvar config = " + literal + "\n\n" + types
+	formatted, _, err := formatGoSource(source, "config.go")
+	if err != nil {
+		t.Fatalf("formatGoSource() error = %v\n%s", err, source)
+	}
+	typeCheckGo(t, formatted)
+	formattedWant, _, _ := formatGoSource("package datos\n\nvar config = "+want+"\n", "want.go")
+	if !strings.Contains(formatted, strings.TrimPrefix(formattedWant, "package datos\n\n")) {
+		t.Errorf("literal inesperado:\n%s", formatted)
+	}
+	if !strings.Contains(types, "func configPtr[T any](v T) *T") {
+		t.Errorf("falta la función de punteros:\n%s", types)
+	}
+
+	// Sin campos opcionales escalares no se declara la función auxiliar
+	types, literal, err = GoTypedLiteral(parseExact(t, `[{"a": 1}]`), "Rows", "rows", "rowsPtr")
+	if err != nil {
+		t.Fatalf("GoTypedLiteral() error = %v", err)
+	}
+	if strings.Contains(types, "rowsPtr") || !strings.HasPrefix(literal, "Rows([]Row{") {
+		t.Errorf("GoTypedLiteral() = %s\n%s", literal, types)
+	}
+}
+
+// Test: los tipos generados no repiten el nombre de la variable ni el de la
+// función de punteros
+func TestConvertJSONToGoLiteralNameCollisions(t *testing.T) {
+	tests := []struct {
+		input, identifier, wantType string
+	}{
+		{`{"config": {"port": 80}}`, "Config", "type Config2 struct"},
+		{`{"configPtr": {"port": 80}, "notes": [{"v": "a"}, {"v": null}]}`, "Config", "type ConfigPtr2 struct"},
+	}
+	for _, tt := range tests {
+		source, err := convertJSONToGoLiteral(tt.input, "datos", tt.identifier, "config.json", true)
+		if err != nil {
+			t.Fatalf("convertJSONToGoLiteral(%s) error = %v", tt.input, err)
+		}
+		formatted, _, err := formatGoSource(source, "config_data.go")
+		if err != nil {
+			t.Fatalf("formatGoSource() error = %v\n%s", err, source)
+		}
+		typeCheckGo(t, formatted)
+		if !strings.Contains(formatted, tt.wantType) {
+			t.Errorf("falta %q en:\n%s", tt.wantType, formatted)
+		}
+	}
+}
+
+// Test: los .json se convierten en código que compila en ambos modos
+func TestConvertJSONToGoLiteral(t *testing.T) {
+	input := `{"servidor": "localhost", "puerto": 8080, "debug": true, "usuarios": ["admin"], "timeout": null}`
+	for _, typed := range []bool{false, true} {
+		source, err := convertJSONToGoLiteral(input, "config", "Config", "config.json", typed)
+		if err != nil {
+			t.Fatalf("convertJSONToGoLiteral(typed=%v) error = %v", typed, err)
+		}
+		formatted, _, err := formatGoSource(source, "config_data.go")
+		if err != nil {
+			t.Fatalf("formatGoSource() error = %v\n%s", err, source)
+		}
+		typeCheckGo(t, formatted)
+		if typed && !strings.Contains(formatted, "var Config = ConfigData{") {
+			t.Errorf("se esperaba el tipo ConfigData:\n%s", formatted)
+		}
+	}
+
+	if _, err := convertJSONToGoLiteral(`{"a": 1,}`, "main", "datos", "roto.json", false); err == nil {
+		t.Error("se esperaba error para JSON inválido")
+	}
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// goInitialisms siglas que Go escribe en mayúsculas dentro de los identificadores
+var goInitialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "TCP": true, "TLS": true, "TTL": true, "UI": true, "URI": true,
+	"URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+// goShape lo observado en una posición del documento: los tipos JSON vistos,
+// las claves de los objetos (y en cuántos aparecieron) y la forma combinada de
+// los elementos de los arrays
+type goShape struct {
+	kinds map[string]bool
+
+	objects    int
+	keys       []string
+	properties map[string]*goShape
+	keyCounts  map[string]int
+
+	items *goShape
+
+	// Resultado de la generación: tipo Go asignado y, en los structs, el
+	// campo que corresponde a cada clave
+	goType string
+	fields map[string]string
+}
+
+func newGoShape() *goShape {
+	return &goShape{kinds: map[string]bool{}, properties: map[string]*goShape{}, keyCounts: map[string]int{}}
+}
+
+// observe incorpora un valor a la forma
+func (s *goShape) observe(value interface{}) {
+	s.kinds[goKindOf(value)] = true
+
+	switch v := value.(type) {
+	case map[string]interface{}, *OrderedObject:
+		s.objects++
+		members := objectValues(v)
+		for _, key := range orderedKeys(v) {
+			child, seen := s.properties[key]
+			if !seen {
+				child = newGoShape()
+				s.properties[key] = child
+				s.keys = append(s.keys, key)
+			}
+			s.keyCounts[key]++
+			child.observe(members[key])
+		}
+	case []interface{}:
+		if s.items == nil {
+			s.items = newGoShape()
+		}
+		for _, item := range v {
+			s.items.observe(item)
+		}
+	}
+}
+
+// goKindOf clasifica un valor; "integer" solo cuando el literal no tiene parte
+// decimal ni exponente y cabe en int64, porque encoding/json rechaza 1.0 en un int64
+func goKindOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case int64:
+		return "integer"
+	case Number:
+		if _, err := v.Int64(); err == nil && !strings.ContainsAny(string(v), ".eE") {
+			return "integer"
+		}
+		return "number"
+	}
+	if _, ok := numericLiteral(value); ok {
+		return "number"
+	}
+	return JSONTypeOf(value)
+}
+
+// goStructGenerator acumula las declaraciones de tipos generadas
+type goStructGenerator struct {
+	decls []string
+	used  map[string]bool
+}
+
+// GenerateGoStructs genera las definiciones de tipos Go que describen value:
+// los objetos anidados se convierten en structs nombrados a partir de su
+// clave, los elementos de un array se combinan en un único tipo, los campos
+// que pueden ser null pasan a ser punteros y las claves que no aparecen en
+// todos los objetos llevan omitempty. Devuelve el código formateado con gofmt.
+func GenerateGoStructs(value interface{}, rootName string) (string, error) {
+	g, _, _ := newGoStructs(value, rootName)
+	return g.source()
+}
+
+// newGoStructs genera las declaraciones y devuelve también la forma raíz
+// (anotada con los tipos asignados) y el nombre del tipo raíz. Los nombres de
+// reserved ya están declarados en el paquete y no se usan para los tipos
+func newGoStructs(value interface{}, rootName string, reserved ...string) (*goStructGenerator, *goShape, string) {
+	shape := newGoShape()
+	shape.observe(value)
+
+	name := exportedGoName(rootName)
+	if name == "" {
+		name = "Root"
+	}
+
+	g := &goStructGenerator{used: map[string]bool{}}
+	for _, declared := range reserved {
+		g.used[declared] = true
+	}
+	name = uniqueGoName(name, g.used)
+	if shape.isStruct() {
+		g.declareStruct(name, shape)
+		shape.goType = name
+	} else {
+		g.decls = append(g.decls, "")
+		g.decls[0] = fmt.Sprintf("type %s %s", name, g.typeOf(shape, name))
+	}
+	return g, shape, name
+}
+
+// source une las declaraciones y les da formato gofmt
+func (g *goStructGenerator) source() (string, error) {
+	source, err := format.Source([]byte(strings.Join(g.decls, "\n\n") + "\n"))
+	if err != nil {
+		return "", fmt.Errorf("el código generado no es Go válido: %w", err)
+	}
+	return string(source), nil
+}
+
+// isStruct indica si la forma se genera como struct: objetos con al menos
+// una clave, opcionalmente null
+func (s *goShape) isStruct() bool {
+	return s.kinds["object"] && len(s.keys) > 0 && len(s.nonNullKinds()) == 1
+}
+
+// nonNullKinds tipos observados sin contar null; enteros y decimales
+// mezclados cuentan como number (float64)
+func (s *goShape) nonNullKinds() []string {
+	var kinds []string
+	for _, kind := range []string{"object", "array", "string", "number", "integer", "boolean"} {
+		if s.kinds[kind] && !(kind == "integer" && s.kinds["number"]) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// declareStruct agrega la declaración del struct; las declaraciones de los
+// structs anidados quedan a continuación, en orden de aparición
+func (g *goStructGenerator) declareStruct(name string, shape *goShape) {
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "type %s struct {\n", name)
+	fields := map[string]bool{}
+	shape.fields = map[string]string{}
+	for _, key := range shape.keys {
+		field := uniqueGoName(exportedGoName(key), fields)
+		shape.fields[key] = field
+		fieldType := g.typeOf(shape.properties[key], field)
+		if !isValidJSONTagName(key) {
+			// Con la clave en la etiqueta, encoding/json la ignoraría o leería
+			// parte de ella como opción: el campo se excluye y se documenta
+			fmt.Fprintf(&body, "\t%s %s `json:\"-\"` // clave JSON %s: no se puede expresar en la etiqueta\n", field, fieldType, strconv.Quote(key))
+			continue
+		}
+		tag := key
+		if key == "-" {
+			// Una etiqueta "-" a secas omite el campo en encoding/json
+			tag = "-,"
+		}
+		if shape.keyCounts[key] < shape.objects {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&body, "\t%s %s %s\n", field, fieldType, goStructTag("json:"+strconv.Quote(tag)))
+	}
+	body.WriteString("}")
+	g.decls[index] = body.String()
+}
+
+// typeOf devuelve el tipo Go de una forma y lo registra en ella; structName
+// es el nombre propuesto si la forma resulta ser un objeto
+func (g *goStructGenerator) typeOf(shape *goShape, structName string) string {
+	shape.goType = g.resolveType(shape, structName)
+	return shape.goType
+}
+
+func (g *goStructGenerator) resolveType(shape *goShape, structName string) string {
+	kinds := shape.nonNullKinds()
+	if len(kinds) != 1 {
+		// Sin observaciones, solo null o tipos mezclados
+		return "interface{}"
+	}
+
+	var goType string
+	switch kinds[0] {
+	case "object":
+		if !shape.isStruct() {
+			return "map[string]interface{}"
+		}
+		name := uniqueGoName(structName, g.used)
+		g.declareStruct(name, shape)
+		goType = name
+	case "array":
+		if shape.items == nil {
+			return "[]interface{}"
+		}
+		return "[]" + g.typeOf(shape.items, singularGoName(structName))
+	case "string":
+		goType = "string"
+	case "integer":
+		goType = "int64"
+	case "number":
+		goType = "float64"
+	case "boolean":
+		goType = "bool"
+	}
+
+	if shape.kinds["null"] {
+		return "*" + goType
+	}
+	return goType
+}
+
+// isValidJSONTagName indica si encoding/json acepta la clave como nombre en
+// la etiqueta: no vacía y solo con letras, dígitos y la puntuación que admite
+// (sin comas, comillas ni barras invertidas)
+func isValidJSONTagName(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r) && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// goStructTag escribe la etiqueta como literal raw salvo que contenga un
+// backtick, que solo se puede expresar en un literal interpretado
+func goStructTag(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// exportedGoName convierte una clave JSON en un identificador Go exportado:
+// separa en palabras por caracteres no alfanuméricos y cambios de minúscula a
+// mayúscula, capitaliza cada palabra y respeta las siglas habituales
+// (user_id → UserID). Un nombre que no empezaría con mayúscula (un dígito o
+// una letra sin mayúsculas) recibe el prefijo X para quedar exportado.
+func exportedGoName(key string) string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	for _, r := range key {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(current) > 0 && unicode.IsLower(current[len(current)-1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	var name strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); goInitialisms[upper] {
+			name.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		name.WriteRune(unicode.ToUpper(runes[0]))
+		name.WriteString(string(runes[1:]))
+	}
+
+	result := name.String()
+	if result != "" && !unicode.IsUpper([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// uniqueGoName evita colisiones agregando un sufijo numérico
+func uniqueGoName(name string, used map[string]bool) string {
+	if name == "" {
+		name = "Field"
+	}
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// singularGoName propone el nombre del elemento de un array a partir del
+// nombre del array (Users → User, Categories → Category)
+func singularGoName(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ss"):
+		return name + "Item"
+	case strings.HasSuffix(name, "s") && len(name) > 1:
+		return strings.TrimSuffix(name, "s")
+	}
+	return name + "Item"
+}
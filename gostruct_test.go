@@ -0,0 +1,138 @@
+package main
+
+import (
+	"go/parser"
+	gotoken "go/token"
+	"strings"
+	"testing"
+)
+
+// Test para la generación de structs a partir de JSON
+func TestGenerateGoStructs(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "escalares",
+			input: `{"id": 1, "price": 2.5, "name": "x", "active": true}`,
+			want: "type Root struct {\n" +
+				"\tID     int64   `json:\"id\"`\n" +
+				"\tPrice  float64 `json:\"price\"`\n" +
+				"\tName   string  `json:\"name\"`\n" +
+				"\tActive bool    `json:\"active\"`\n" +
+				"}\n",
+		},
+		{
+			name:  "structs anidados y arrays combinados",
+			input: `{"user_name": "ana", "address": {"city": "Lima"}, "orders": [{"id": 1, "note": null}, {"id": 2.5, "note": "x", "gift": true}]}`,
+			want: "type Root struct {\n" +
+				"\tUserName string  `json:\"user_name\"`\n" +
+				"\tAddress  Address `json:\"address\"`\n" +
+				"\tOrders   []Order `json:\"orders\"`\n" +
+				"}\n\n" +
+				"type Address struct {\n" +
+				"\tCity string `json:\"city\"`\n" +
+				"}\n\n" +
+				"type Order struct {\n" +
+				"\tID   float64 `json:\"id\"`\n" +
+				"\tNote *string `json:\"note\"`\n" +
+				"\tGift bool    `json:\"gift,omitempty\"`\n" +
+				"}\n",
+		},
+		{
+			name:  "array en la raíz",
+			input: `[{"a": 1}, {"a": null}]`,
+			want: "type Root []RootItem\n\n" +
+				"type RootItem struct {\n" +
+				"\tA *int64 `json:\"a\"`\n" +
+				"}\n",
+		},
+		{
+			name:  "tipos sin forma fija",
+			input: `{"mixed": [1, "a"], "empty": [], "obj": {}, "nothing": null, "big": 1e400, "huge": 123456789012345678901}`,
+			want: "type Root struct {\n" +
+				"\tMixed   []interface{}          `json:\"mixed\"`\n" +
+				"\tEmpty   []interface{}          `json:\"empty\"`\n" +
+				"\tObj     map[string]interface{} `json:\"obj\"`\n" +
+				"\tNothing interface{}            `json:\"nothing\"`\n" +
+				"\tBig     float64                `json:\"big\"`\n" +
+				"\tHuge    float64                `json:\"huge\"`\n" +
+				"}\n",
+		},
+		{
+			name:  "nombres de campo",
+			input: `{"userId": 1, "user_id": 2, "2fa": true, "-": 0, "api-url": "", "precio [USD]": 1}`,
+			want: "type Root struct {\n" +
+				"\tUserID    int64  `json:\"userId\"`\n" +
+				"\tUserID2   int64  `json:\"user_id\"`\n" +
+				"\tX2fa      bool   `json:\"2fa\"`\n" +
+				"\tField     int64  `json:\"-,\"`\n" +
+				"\tAPIURL    string `json:\"api-url\"`\n" +
+				"\tPrecioUSD int64  `json:\"precio [USD]\"`\n" +
+				"}\n",
+		},
+		{
+			name:  "claves que la etiqueta no puede expresar",
+			input: `{"a,omitempty": 1, "say \"hi\"": "", "a` + "`" + `b": 0, "": true, "id": 1}`,
+			want: "type Root struct {\n" +
+				"\tAOmitempty int64  `json:\"-\"` // clave JSON \"a,omitempty\": no se puede expresar en la etiqueta\n" +
+				"\tSayHi      string `json:\"-\"` // clave JSON \"say \\\"hi\\\"\": no se puede expresar en la etiqueta\n" +
+				"\tAB         int64  `json:\"-\"` // clave JSON \"a`b\": no se puede expresar en la etiqueta\n" +
+				"\tField      bool   `json:\"-\"` // clave JSON \"\": no se puede expresar en la etiqueta\n" +
+				"\tID         int64  `json:\"id\"`\n" +
+				"}\n",
+		},
+		{
+			name:  "escalar en la raíz",
+			input: `"texto"`,
+			want:  "type Root string\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateGoStructs(parseExact(t, tt.input), "root")
+			if err != nil {
+				t.Fatalf("GenerateGoStructs() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateGoStructs() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test: los nombres de structs anidados no colisionan entre sí
+func TestGenerateGoStructsNameCollisions(t *testing.T) {
+	input := `{"item": {"item": {"id": 1}}, "categories": [{"name": "a"}]}`
+	got, err := GenerateGoStructs(parseExact(t, input), "Item")
+	if err != nil {
+		t.Fatalf("GenerateGoStructs() error = %v", err)
+	}
+	for _, decl := range []string{"type Item struct", "type Item2 struct", "type Item3 struct", "type Category struct"} {
+		if !strings.Contains(got, decl) {
+			t.Errorf("falta %q en:\n%s", decl, got)
+		}
+	}
+}
+
+// Test: el archivo generado es Go válido y lleva el paquete y el tipo pedidos
+func TestConvertJSONToGoStructs(t *testing.T) {
+	input := `{"id": 7, "tags": ["a"], "owner": {"email": "a@b.io"}}`
+	source, err := convertJSONToGoStructs(input, "models", "config", "config.json")
+	if err != nil {
+		t.Fatalf("convertJSONToGoStructs() error = %v", err)
+	}
+	if _, err := parser.ParseFile(gotoken.NewFileSet(), "config.go", source, 0); err != nil {
+		t.Fatalf("el código generado no compila: %v\n%s", err, source)
+	}
+	if !strings.HasPrefix(source, "package models\n") || !strings.Contains(source, "type Config struct") {
+		t.Errorf("código inesperado:\n%s", source)
+	}
+
+	if _, err := convertJSONToGoStructs(`{"a": }`, "main", "Root", "roto.json"); err == nil {
+		t.Error("se esperaba error para JSON inválido")
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"regexp"
+	"time"
+)
+
+// schemaDialect URI del draft de los esquemas que produce InferSchema
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// defaultMaxEnumValues cantidad máxima de strings distintos para proponer un enum
+const defaultMaxEnumValues = 5
+
+// InferOptions ajustes de la inferencia de esquemas
+type InferOptions struct {
+	// MaxEnumValues propone enum cuando un campo string toma como máximo esta
+	// cantidad de valores distintos y al menos uno se repite (0 usa el valor
+	// por defecto, negativo desactiva los enum)
+	MaxEnumValues int
+}
+
+var (
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// schemaTypeOrder orden estable de los tipos en la salida
+var schemaTypeOrder = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// schemaShape lo observado en una posición del documento a través de todas
+// las muestras
+type schemaShape struct {
+	types map[string]bool
+
+	// objetos: claves en orden de aparición y en cuántos objetos estuvieron
+	objects    int
+	keys       []string
+	properties map[string]*schemaShape
+	keyCounts  map[string]int
+
+	// arrays: forma combinada de todos los elementos
+	items *schemaShape
+
+	// strings: valores distintos (hasta el límite del enum) y formato común
+	strings       int
+	stringValues  []string
+	stringSeen    map[string]bool
+	tooManyValues bool
+	format        string
+	formatBroken  bool
+}
+
+func newSchemaShape() *schemaShape {
+	return &schemaShape{types: map[string]bool{}, properties: map[string]*schemaShape{}, keyCounts: map[string]int{}, stringSeen: map[string]bool{}}
+}
+
+// InferSchema propone un esquema draft 2020-12 que acepta todas las muestras:
+// combina los tipos vistos en cada posición, marca como requeridas las claves
+// presentes en todos los objetos y detecta enum y formatos (date-time, email,
+// uuid) en los strings. Devuelve el esquema como árbol de valores, listo para
+// serializar o para CompileSchema.
+func InferSchema(samples []interface{}, opts InferOptions) *OrderedObject {
+	if opts.MaxEnumValues == 0 {
+		opts.MaxEnumValues = defaultMaxEnumValues
+	}
+
+	shape := newSchemaShape()
+	for _, sample := range samples {
+		shape.observe(sample, opts)
+	}
+
+	schema := NewOrderedObject()
+	schema.Set("$schema", schemaDialect)
+	shape.describe(schema, opts)
+	return schema
+}
+
+// observe incorpora un valor a la forma
+func (s *schemaShape) observe(value interface{}, opts InferOptions) {
+	kind := schemaTypeOf(value)
+	s.types[kind] = true
+
+	switch v := value.(type) {
+	case map[string]interface{}, *OrderedObject:
+		s.objects++
+		members := objectValues(v)
+		for _, key := range orderedKeys(v) {
+			child, seen := s.properties[key]
+			if !seen {
+				child = newSchemaShape()
+				s.properties[key] = child
+				s.keys = append(s.keys, key)
+			}
+			s.keyCounts[key]++
+			child.observe(members[key], opts)
+		}
+	case []interface{}:
+		if s.items == nil {
+			s.items = newSchemaShape()
+		}
+		for _, item := range v {
+			s.items.observe(item, opts)
+		}
+	case string:
+		s.observeString(v, opts)
+	}
+}
+
+// observeString registra valores distintos y el formato común de los strings
+func (s *schemaShape) observeString(text string, opts InferOptions) {
+	s.strings++
+	if !s.stringSeen[text] && !s.tooManyValues {
+		if len(s.stringValues) >= opts.MaxEnumValues {
+			s.tooManyValues = true
+		} else {
+			s.stringSeen[text] = true
+			s.stringValues = append(s.stringValues, text)
+		}
+	}
+
+	format := detectFormat(text)
+	switch {
+	case s.strings == 1:
+		s.format = format
+	case format != s.format:
+		s.formatBroken = true
+	}
+}
+
+// detectFormat reconoce los formatos de JSON Schema más útiles para contratos
+func detectFormat(text string) string {
+	switch {
+	case uuidPattern.MatchString(text):
+		return "uuid"
+	case emailPattern.MatchString(text):
+		return "email"
+	}
+	if _, err := time.Parse(time.RFC3339Nano, text); err == nil {
+		return "date-time"
+	}
+	return ""
+}
+
+// describe escribe en schema las palabras clave de la forma
+func (s *schemaShape) describe(schema *OrderedObject, opts InferOptions) {
+	// integer y number se combinan en number
+	if s.types["integer"] && s.types["number"] {
+		delete(s.types, "integer")
+	}
+	var types []interface{}
+	for _, name := range schemaTypeOrder {
+		if s.types[name] {
+			types = append(types, name)
+		}
+	}
+	switch len(types) {
+	case 0:
+		// Sin observaciones (p. ej. items de arrays siempre vacíos): acepta todo
+	case 1:
+		schema.Set("type", types[0])
+	default:
+		schema.Set("type", types)
+	}
+
+	if s.objects > 0 {
+		properties := NewOrderedObject()
+		var required []interface{}
+		for _, key := range s.keys {
+			child := NewOrderedObject()
+			s.properties[key].describe(child, opts)
+			properties.Set(key, child)
+			if s.keyCounts[key] == s.objects {
+				required = append(required, key)
+			}
+		}
+		schema.Set("properties", properties)
+		if len(required) > 0 {
+			schema.Set("required", required)
+		}
+	}
+
+	if s.items != nil && len(s.items.types) > 0 {
+		items := NewOrderedObject()
+		s.items.describe(items, opts)
+		schema.Set("items", items)
+	}
+
+	if s.strings > 0 {
+		switch {
+		case s.format != "" && !s.formatBroken:
+			schema.Set("format", s.format)
+		case opts.MaxEnumValues > 0 && !s.tooManyValues && len(s.stringValues) < s.strings && len(types) == len(s.enumTypes()):
+			// El enum restringe todos los tipos: solo se propone si los demás valores son null
+			var values []interface{}
+			for _, value := range s.stringValues {
+				values = append(values, value)
+			}
+			if s.types["null"] {
+				values = append(values, nil)
+			}
+			schema.Set("enum", values)
+		}
+	}
+}
+
+// enumTypes tipos observados compatibles con un enum de strings
+func (s *schemaShape) enumTypes() []string {
+	var types []string
+	for _, name := range []string{"string", "null"} {
+		if s.types[name] {
+			types = append(types, name)
+		}
+	}
+	return types
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+// inferFromJSON infiere el esquema de varias muestras y lo serializa compacto
+func inferFromJSON(t *testing.T, opts InferOptions, samples ...string) (string, *OrderedObject) {
+	t.Helper()
+	values := make([]interface{}, len(samples))
+	for i, sample := range samples {
+		values[i] = parseExact(t, sample)
+	}
+	schema := InferSchema(values, opts)
+	schema.Delete("$schema")
+	return string(mustMarshal(t, schema)), schema
+}
+
+// Test para la inferencia de esquemas
+func TestInferSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []string
+		want    string
+	}{
+		{"escalares", []string{`1`}, `{"type":"integer"}`},
+		{"integer y number", []string{`1`, `2.5`}, `{"type":"number"}`},
+		{"tipos combinados", []string{`"a"`, `null`, `true`}, `{"type":["string","boolean","null"]}`},
+		{"requeridas en todas", []string{`{"id":1,"name":"x"}`, `{"id":2}`}, `{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"}},"required":["id"]}`},
+		{"arrays combinados", []string{`[1,"a"]`, `[]`}, `{"type":"array","items":{"type":["string","integer"]}}`},
+		{"array siempre vacío", []string{`[]`}, `{"type":"array"}`},
+		{"enum", []string{`{"s":"on"}`, `{"s":"off"}`, `{"s":"on"}`}, `{"type":"object","properties":{"s":{"type":"string","enum":["on","off"]}},"required":["s"]}`},
+		{"enum con null", []string{`["a","b","a",null]`}, `{"type":"array","items":{"type":["string","null"],"enum":["a","b",null]}}`},
+		{"sin repetidos no hay enum", []string{`["a","b"]`}, `{"type":"array","items":{"type":"string"}}`},
+		{"demasiados valores", []string{`["a","b","c","d","e","f","a"]`}, `{"type":"array","items":{"type":"string"}}`},
+		{"enum con otros tipos", []string{`["a","a",1]`}, `{"type":"array","items":{"type":["string","integer"]}}`},
+		{"date-time", []string{`["2024-01-02T03:04:05Z","2024-06-30T10:00:00.5-03:00"]`}, `{"type":"array","items":{"type":"string","format":"date-time"}}`},
+		{"email", []string{`["ana@ejemplo.com","ana@ejemplo.com"]`}, `{"type":"array","items":{"type":"string","format":"email"}}`},
+		{"uuid", []string{`["123e4567-e89b-12d3-a456-426614174000"]`}, `{"type":"array","items":{"type":"string","format":"uuid"}}`},
+		{"formatos mezclados", []string{`["ana@ejemplo.com","2024-01-02T03:04:05Z"]`}, `{"type":"array","items":{"type":"string"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := inferFromJSON(t, InferOptions{}, tt.samples...); got != tt.want {
+				t.Errorf("InferSchema() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test: el esquema inferido acepta todas las muestras de las que proviene
+func TestInferSchemaValidatesSamples(t *testing.T) {
+	samples := []string{
+		`{"id":"123e4567-e89b-12d3-a456-426614174000","user":{"email":"a@b.io","roles":["admin"]},"score":1,"at":"2024-01-02T03:04:05Z"}`,
+		`{"id":"123e4567-e89b-12d3-a456-426614174001","user":{"email":"c@d.io","roles":[]},"score":2.5,"note":null}`,
+		`{"id":"123e4567-e89b-12d3-a456-426614174002","user":{"email":"e@f.io","roles":["dev","admin"],"active":true},"score":3}`,
+	}
+	_, schema := inferFromJSON(t, InferOptions{}, samples...)
+	compiled, err := CompileSchema(schema)
+	if err != nil {
+		t.Fatalf("CompileSchema() error = %v", err)
+	}
+	for _, sample := range samples {
+		if violations := compiled.Validate(parseExact(t, sample)); len(violations) > 0 {
+			t.Errorf("la muestra %s no valida: %v", sample, violations)
+		}
+	}
+	if violations := compiled.Validate(parseExact(t, `{"user":{}}`)); len(violations) == 0 {
+		t.Error("un documento sin las claves requeridas no debería validar")
+	}
+}
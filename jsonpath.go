@@ -0,0 +1,1122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxJSONPathInt límite de índices y pasos en JSONPath (rango exacto de I-JSON)
+const maxJSONPathInt = 1<<53 - 1
+
+// JSONPathError error de sintaxis en una consulta JSONPath
+type JSONPathError struct {
+	Query  string `json:"query"`
+	Offset int    `json:"offset"` // posición en bytes dentro de la consulta
+	Msg    string `json:"message"`
+}
+
+// Error implementa la interfaz error
+func (e *JSONPathError) Error() string {
+	return fmt.Sprintf("consulta JSONPath inválida en la posición %d: %s", e.Offset, e.Msg)
+}
+
+// AsJSONPathError extrae un *JSONPathError de la cadena de errores, si existe
+func AsJSONPathError(err error) *JSONPathError {
+	var pathErr *JSONPathError
+	if errors.As(err, &pathErr) {
+		return pathErr
+	}
+	return nil
+}
+
+// JSONPathResult nodo seleccionado por una consulta, con su ruta normalizada
+type JSONPathResult struct {
+	Path  string      `json:"path"` // p. ej. $['store']['book'][0]
+	Value interface{} `json:"value"`
+}
+
+// JSONPath consulta JSONPath (RFC 9535) ya compilada; es de solo lectura y
+// puede evaluarse sobre varios documentos
+type JSONPath struct {
+	raw   string
+	query *pathQuery
+}
+
+// CompileJSONPath analiza una consulta JSONPath
+func CompileJSONPath(expr string) (*JSONPath, error) {
+	p := &pathParser{src: expr}
+	if !p.consume('$') {
+		return nil, p.errorf("la consulta debe comenzar con '$'")
+	}
+	query, err := p.parseSegments(true)
+	if err != nil {
+		return nil, err
+	}
+	// RFC 9535 no admite espacios antes ni después de la consulta
+	if p.pos < len(p.src) && strings.TrimSpace(p.rest()) == "" {
+		return nil, p.errorf("no se admiten espacios al final de la consulta")
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("contenido inesperado '%s'", p.rest())
+	}
+	return &JSONPath{raw: expr, query: query}, nil
+}
+
+// String devuelve la consulta original
+func (jp *JSONPath) String() string {
+	return jp.raw
+}
+
+// Evaluate aplica la consulta sobre un documento parseado
+func (jp *JSONPath) Evaluate(doc interface{}) []JSONPathResult {
+	nodes := jp.query.eval(doc, doc)
+	results := make([]JSONPathResult, len(nodes))
+	for i, node := range nodes {
+		results[i] = JSONPathResult{Path: node.path, Value: node.value}
+	}
+	return results
+}
+
+// QueryJSONPath función de conveniencia: compila y evalúa en un paso
+func QueryJSONPath(doc interface{}, expr string) ([]JSONPathResult, error) {
+	jp, err := CompileJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return jp.Evaluate(doc), nil
+}
+
+// ===== Árbol de la consulta =====
+
+// pathNode nodo del documento con su ruta normalizada
+type pathNode struct {
+	value interface{}
+	path  string
+}
+
+// pathQuery consulta absoluta ($) o relativa al nodo actual (@)
+type pathQuery struct {
+	relative bool
+	segments []pathSegment
+}
+
+// pathSegment segmento hijo ([...], .name) o descendiente (..)
+type pathSegment struct {
+	descendant bool
+	selectors  []pathSelector
+}
+
+// pathSelector selector individual dentro de un segmento
+type pathSelector interface {
+	selectFrom(node pathNode, root interface{}, out []pathNode) []pathNode
+}
+
+type nameSelector string
+
+type wildcardSelector struct{}
+
+type indexSelector int
+
+type sliceSelector struct {
+	start, end, step          int
+	hasStart, hasEnd, hasStep bool
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+// singular indica si la consulta produce como máximo un nodo (solo nombres e índices)
+func (q *pathQuery) singular() bool {
+	for _, segment := range q.segments {
+		if segment.descendant || len(segment.selectors) != 1 {
+			return false
+		}
+		switch segment.selectors[0].(type) {
+		case nameSelector, indexSelector:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// eval aplica los segmentos partiendo de la raíz o del nodo actual
+func (q *pathQuery) eval(root, current interface{}) []pathNode {
+	start := pathNode{value: root, path: "$"}
+	if q.relative {
+		start = pathNode{value: current, path: "@"}
+	}
+
+	nodes := []pathNode{start}
+	for _, segment := range q.segments {
+		var next []pathNode
+		for _, node := range nodes {
+			if segment.descendant {
+				next = segment.selectDescendants(node, root, next)
+			} else {
+				for _, selector := range segment.selectors {
+					next = selector.selectFrom(node, root, next)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// selectDescendants aplica los selectores al nodo y a todos sus descendientes, en preorden
+func (s pathSegment) selectDescendants(node pathNode, root interface{}, out []pathNode) []pathNode {
+	for _, selector := range s.selectors {
+		out = selector.selectFrom(node, root, out)
+	}
+	for _, child := range pathChildren(node) {
+		out = s.selectDescendants(child, root, out)
+	}
+	return out
+}
+
+// pathChildren devuelve los hijos de un objeto (en orden de claves) o array
+func pathChildren(node pathNode) []pathNode {
+	switch v := node.value.(type) {
+	case map[string]interface{}, *OrderedObject:
+		keys := orderedKeys(v)
+		values := objectValues(v)
+		children := make([]pathNode, len(keys))
+		for i, key := range keys {
+			children[i] = pathNode{value: values[key], path: node.path + normalizedName(key)}
+		}
+		return children
+	case []interface{}:
+		children := make([]pathNode, len(v))
+		for i, item := range v {
+			children[i] = pathNode{value: item, path: node.path + "[" + strconv.Itoa(i) + "]"}
+		}
+		return children
+	}
+	return nil
+}
+
+// orderedKeys claves de un objeto: orden original o alfabético para mapas
+func orderedKeys(value interface{}) []string {
+	if ordered, ok := value.(*OrderedObject); ok {
+		return ordered.Keys
+	}
+	values := objectValues(value)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizedName segmento de ruta normalizada para una clave: ['clave']
+func normalizedName(key string) string {
+	var sb strings.Builder
+	sb.WriteString("['")
+	for _, r := range key {
+		switch r {
+		case '\'':
+			sb.WriteString(`\'`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&sb, `\u%04x`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteString("']")
+	return sb.String()
+}
+
+func (s nameSelector) selectFrom(node pathNode, root interface{}, out []pathNode) []pathNode {
+	values := objectValues(node.value)
+	if values == nil {
+		return out
+	}
+	if value, exists := values[string(s)]; exists {
+		out = append(out, pathNode{value: value, path: node.path + normalizedName(string(s))})
+	}
+	return out
+}
+
+func (wildcardSelector) selectFrom(node pathNode, root interface{}, out []pathNode) []pathNode {
+	return append(out, pathChildren(node)...)
+}
+
+func (s indexSelector) selectFrom(node pathNode, root interface{}, out []pathNode) []pathNode {
+	array, ok := node.value.([]interface{})
+	if !ok {
+		return out
+	}
+	index := int(s)
+	if index < 0 {
+		index += len(array)
+	}
+	if index < 0 || index >= len(array) {
+		return out
+	}
+	return append(out, pathNode{value: array[index], path: node.path + "[" + strconv.Itoa(index) + "]"})
+}
+
+// selectFrom implementa la semántica de slices de RFC 9535 (sección 2.3.4.2.2)
+func (s sliceSelector) selectFrom(node pathNode, root interface{}, out []pathNode) []pathNode {
+	array, ok := node.value.([]interface{})
+	if !ok {
+		return out
+	}
+
+	length := len(array)
+	step := 1
+	if s.hasStep {
+		step = s.step
+	}
+	if step == 0 {
+		return out
+	}
+
+	normalize := func(i int) int {
+		if i >= 0 {
+			return i
+		}
+		return length + i
+	}
+	clamp := func(i, low, high int) int {
+		if i < low {
+			return low
+		}
+		if i > high {
+			return high
+		}
+		return i
+	}
+
+	if step > 0 {
+		start, end := 0, length
+		if s.hasStart {
+			start = normalize(s.start)
+		}
+		if s.hasEnd {
+			end = normalize(s.end)
+		}
+		lower, upper := clamp(start, 0, length), clamp(end, 0, length)
+		for i := lower; i < upper; i += step {
+			out = append(out, pathNode{value: array[i], path: node.path + "[" + strconv.Itoa(i) + "]"})
+		}
+		return out
+	}
+
+	start, end := length-1, -length-1
+	if s.hasStart {
+		start = normalize(s.start)
+	}
+	if s.hasEnd {
+		end = normalize(s.end)
+	}
+	upper, lower := clamp(start, -1, length-1), clamp(end, -1, length-1)
+	for i := upper; i > lower; i += step {
+		out = append(out, pathNode{value: array[i], path: node.path + "[" + strconv.Itoa(i) + "]"})
+	}
+	return out
+}
+
+func (s filterSelector) selectFrom(node pathNode, root interface{}, out []pathNode) []pathNode {
+	for _, child := range pathChildren(node) {
+		if s.expr.test(child.value, root) {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+// ===== Expresiones de filtro =====
+
+// filterExpr expresión lógica evaluada sobre el nodo actual (@)
+type filterExpr interface {
+	test(current, root interface{}) bool
+}
+
+type orExpr []filterExpr
+
+type andExpr []filterExpr
+
+type notExpr struct {
+	expr filterExpr
+}
+
+// existsExpr verdadero si la consulta selecciona al menos un nodo
+type existsExpr struct {
+	query *pathQuery
+}
+
+// logicalCallExpr función de tipo lógico usada como prueba (match, search)
+type logicalCallExpr struct {
+	call *pathFunctionCall
+}
+
+type compareExpr struct {
+	op          string
+	left, right pathOperand
+}
+
+func (e orExpr) test(current, root interface{}) bool {
+	for _, expr := range e {
+		if expr.test(current, root) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e andExpr) test(current, root interface{}) bool {
+	for _, expr := range e {
+		if !expr.test(current, root) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e notExpr) test(current, root interface{}) bool {
+	return !e.expr.test(current, root)
+}
+
+func (e existsExpr) test(current, root interface{}) bool {
+	return len(e.query.eval(root, current)) > 0
+}
+
+func (e logicalCallExpr) test(current, root interface{}) bool {
+	return e.call.logical(current, root)
+}
+
+// test compara dos valores; un operando sin valor ("Nothing") solo es igual a otro vacío
+func (e compareExpr) test(current, root interface{}) bool {
+	left, leftOK := evalOperand(e.left, current, root)
+	right, rightOK := evalOperand(e.right, current, root)
+
+	equal := func() bool {
+		if !leftOK || !rightOK {
+			return leftOK == rightOK
+		}
+		return EqualValues(left, right)
+	}
+	less := func(a, b interface{}) bool {
+		if !leftOK || !rightOK {
+			return false
+		}
+		return pathLess(a, b)
+	}
+
+	switch e.op {
+	case "==":
+		return equal()
+	case "!=":
+		return !equal()
+	case "<":
+		return less(left, right)
+	case "<=":
+		return less(left, right) || equal()
+	case ">":
+		return less(right, left)
+	case ">=":
+		return less(right, left) || equal()
+	}
+	return false
+}
+
+// pathLess orden de RFC 9535: solo entre números o entre strings
+func pathLess(a, b interface{}) bool {
+	if aNum, ok := numericLiteral(a); ok {
+		bNum, ok := numericLiteral(b)
+		return ok && compareNumbers(aNum, bNum) < 0
+	}
+	aStr, aOK := a.(string)
+	bStr, bOK := b.(string)
+	return aOK && bOK && aStr < bStr
+}
+
+// pathOperand operando de una comparación o argumento de función:
+// *pathLiteral, *pathQuery o *pathFunctionCall
+type pathOperand interface{}
+
+// pathLiteral valor literal (número, string, true, false o null)
+type pathLiteral struct {
+	value interface{}
+}
+
+// evalOperand obtiene el valor de un operando; false representa "Nothing"
+func evalOperand(operand pathOperand, current, root interface{}) (interface{}, bool) {
+	switch o := operand.(type) {
+	case *pathLiteral:
+		return o.value, true
+	case *pathQuery:
+		nodes := o.eval(root, current)
+		if len(nodes) != 1 {
+			return nil, false
+		}
+		return nodes[0].value, true
+	case *pathFunctionCall:
+		return o.value(current, root)
+	}
+	return nil, false
+}
+
+// ===== Funciones =====
+
+// pathType tipos del sistema de tipos de funciones de RFC 9535
+type pathType int
+
+const (
+	valueType pathType = iota
+	logicalType
+	nodesType
+)
+
+// pathFunction firma de una función de extensión
+type pathFunction struct {
+	params []pathType
+	result pathType
+}
+
+// pathFunctions funciones estándar de RFC 9535 (sección 2.4)
+var pathFunctions = map[string]pathFunction{
+	"length": {[]pathType{valueType}, valueType},
+	"count":  {[]pathType{nodesType}, valueType},
+	"match":  {[]pathType{valueType, valueType}, logicalType},
+	"search": {[]pathType{valueType, valueType}, logicalType},
+	"value":  {[]pathType{nodesType}, valueType},
+}
+
+// pathFunctionCall llamada a función; pattern guarda la regex precompilada
+// cuando el argumento es un literal
+type pathFunctionCall struct {
+	name    string
+	args    []pathOperand
+	pattern *regexp.Regexp
+}
+
+// value evalúa una función de tipo ValueType
+func (c *pathFunctionCall) value(current, root interface{}) (interface{}, bool) {
+	switch c.name {
+	case "length":
+		arg, ok := evalOperand(c.args[0], current, root)
+		if !ok {
+			return nil, false
+		}
+		switch v := arg.(type) {
+		case string:
+			return int64(utf8.RuneCountInString(v)), true
+		case []interface{}:
+			return int64(len(v)), true
+		case map[string]interface{}, *OrderedObject:
+			return int64(len(objectValues(v))), true
+		}
+		return nil, false
+	case "count":
+		return int64(len(c.args[0].(*pathQuery).eval(root, current))), true
+	case "value":
+		nodes := c.args[0].(*pathQuery).eval(root, current)
+		if len(nodes) != 1 {
+			return nil, false
+		}
+		return nodes[0].value, true
+	}
+	return nil, false
+}
+
+// logical evalúa una función de tipo LogicalType (match, search)
+func (c *pathFunctionCall) logical(current, root interface{}) bool {
+	subject, ok := evalOperand(c.args[0], current, root)
+	text, isString := subject.(string)
+	if !ok || !isString {
+		return false
+	}
+
+	pattern := c.pattern
+	if pattern == nil {
+		source, ok := evalOperand(c.args[1], current, root)
+		expr, isString := source.(string)
+		if !ok || !isString {
+			return false
+		}
+		var err error
+		if pattern, err = compileIRegexp(expr, c.name == "match"); err != nil {
+			return false
+		}
+	}
+	return pattern.MatchString(text)
+}
+
+// compileIRegexp compila una I-Regexp (RFC 9485); match exige coincidencia
+// completa. La expresión se analiza sola antes de anclarla, para que no pueda
+// cerrar el grupo que la envuelve ("a)|(?:b")
+func compileIRegexp(expr string, full bool) (*regexp.Regexp, error) {
+	tree, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	iRegexpDot(tree)
+	expr = tree.String()
+	if full {
+		expr = `\A(?:` + expr + `)\z`
+	}
+	return regexp.Compile(expr)
+}
+
+// iRegexpDot cambia cada '.' por [^\n\r], su significado en I-Regexp
+func iRegexpDot(re *syntax.Regexp) {
+	if re.Op == syntax.OpAnyChar || re.Op == syntax.OpAnyCharNotNL {
+		re.Op = syntax.OpCharClass
+		re.Rune = []rune{0, '\n' - 1, '\n' + 1, '\r' - 1, '\r' + 1, unicode.MaxRune}
+	}
+	for _, sub := range re.Sub {
+		iRegexpDot(sub)
+	}
+}
+
+// ===== Parser de consultas =====
+
+// pathParser analizador descendente recursivo de consultas JSONPath
+type pathParser struct {
+	src string
+	pos int
+}
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return &JSONPathError{Query: p.src, Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *pathParser) rest() string {
+	return p.src[p.pos:]
+}
+
+func (p *pathParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *pathParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *pathParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// parseSegments lee los segmentos que siguen a '$' o '@'
+func (p *pathParser) parseSegments(absolute bool) (*pathQuery, error) {
+	query := &pathQuery{relative: !absolute}
+	for {
+		// Se admiten espacios entre segmentos, pero no se consumen si no sigue otro
+		save := p.pos
+		p.skipSpace()
+		if p.peek() != '.' && p.peek() != '[' {
+			p.pos = save
+			return query, nil
+		}
+
+		segment, err := p.parseSegment()
+		if err != nil {
+			return nil, err
+		}
+		query.segments = append(query.segments, segment)
+	}
+}
+
+// parseSegment lee .name, .*, [selectores] o sus formas descendientes (..)
+func (p *pathParser) parseSegment() (pathSegment, error) {
+	if p.consume('[') {
+		selectors, err := p.parseBracketed()
+		return pathSegment{selectors: selectors}, err
+	}
+
+	p.pos++ // '.'
+	segment := pathSegment{}
+	if p.consume('.') {
+		segment.descendant = true
+		if p.consume('[') {
+			selectors, err := p.parseBracketed()
+			segment.selectors = selectors
+			return segment, err
+		}
+	}
+
+	if p.consume('*') {
+		segment.selectors = []pathSelector{wildcardSelector{}}
+		return segment, nil
+	}
+	name, ok := p.parseMemberName()
+	if !ok {
+		return segment, p.errorf("se esperaba un nombre de miembro, '*' o '['")
+	}
+	segment.selectors = []pathSelector{nameSelector(name)}
+	return segment, nil
+}
+
+// parseMemberName lee un nombre en notación punto: letra, '_' o no ASCII, seguidos de dígitos
+func (p *pathParser) parseMemberName() (string, bool) {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		isFirst := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= 0x80 && r != utf8.RuneError)
+		if !isFirst && (p.pos == start || r < '0' || r > '9') {
+			break
+		}
+		p.pos += size
+	}
+	return p.src[start:p.pos], p.pos > start
+}
+
+// parseBracketed lee la lista de selectores hasta ']'
+func (p *pathParser) parseBracketed() ([]pathSelector, error) {
+	var selectors []pathSelector
+	for {
+		p.skipSpace()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+
+		p.skipSpace()
+		if p.consume(']') {
+			return selectors, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("se esperaba ',' o ']'")
+		}
+	}
+}
+
+// parseSelector lee un selector: nombre entre comillas, '*', índice, slice o filtro
+func (p *pathParser) parseSelector() (pathSelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseStringLiteral()
+		return nameSelector(name), err
+	case c == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case c == '?':
+		p.pos++
+		p.skipSpace()
+		expr, err := p.parseLogicalOr()
+		return filterSelector{expr: expr}, err
+	case c == '-' || c == ':' || isDigit(c):
+		return p.parseIndexOrSlice()
+	}
+	return nil, p.errorf("selector inválido")
+}
+
+// parseIndexOrSlice lee un índice o un slice inicio:fin:paso
+func (p *pathParser) parseIndexOrSlice() (pathSelector, error) {
+	var slice sliceSelector
+	var err error
+
+	if p.peek() != ':' {
+		if slice.start, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+		slice.hasStart = true
+		p.skipSpace()
+		if p.peek() != ':' {
+			return indexSelector(slice.start), nil
+		}
+	}
+
+	p.pos++ // primer ':'
+	p.skipSpace()
+	if c := p.peek(); c == '-' || isDigit(c) {
+		if slice.end, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+		slice.hasEnd = true
+		p.skipSpace()
+	}
+
+	if p.consume(':') {
+		p.skipSpace()
+		if c := p.peek(); c == '-' || isDigit(c) {
+			if slice.step, err = p.parseInt(); err != nil {
+				return nil, err
+			}
+			slice.hasStep = true
+		}
+	}
+	return slice, nil
+}
+
+// parseInt lee un entero sin ceros a la izquierda dentro del rango de I-JSON
+func (p *pathParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume('-')
+	digits := p.pos
+	for isDigit(p.peek()) {
+		p.pos++
+	}
+
+	literal := p.src[start:p.pos]
+	switch {
+	case p.pos == digits:
+		return 0, p.errorf("se esperaba un entero")
+	case p.src[digits] == '0' && (p.pos-digits > 1 || digits > start):
+		p.pos = start
+		return 0, p.errorf("entero inválido '%s'", literal)
+	}
+
+	value, err := strconv.Atoi(literal)
+	if err != nil || value > maxJSONPathInt || value < -maxJSONPathInt {
+		p.pos = start
+		return 0, p.errorf("entero fuera de rango '%s'", literal)
+	}
+	return value, nil
+}
+
+// parseStringLiteral lee un string entre comillas simples o dobles con los
+// escapes de JSON (más \' dentro de comillas simples)
+func (p *pathParser) parseStringLiteral() (string, error) {
+	quote := p.src[p.pos]
+	start := p.pos
+	p.pos++
+
+	var raw []byte
+	for {
+		if p.pos >= len(p.src) {
+			p.pos = start
+			return "", p.errorf("string sin cerrar")
+		}
+		c := p.src[p.pos]
+		if c == quote {
+			p.pos++
+			break
+		}
+		if c == '\\' && p.pos+1 < len(p.src) {
+			if quote == '\'' && p.src[p.pos+1] == '\'' {
+				raw = append(raw, '\'')
+			} else {
+				raw = append(raw, c, p.src[p.pos+1])
+			}
+			p.pos += 2
+			continue
+		}
+		if c == '"' {
+			// Dentro de comillas simples la comilla doble es literal
+			raw = append(raw, '\\', '"')
+			p.pos++
+			continue
+		}
+		raw = append(raw, c)
+		p.pos++
+	}
+
+	text, offset, err := unquoteContent(raw, false)
+	if err != nil {
+		p.pos = start + 1 + offset
+		return "", p.errorf("%v", err)
+	}
+	return text, nil
+}
+
+// parseLogicalOr expr || expr ...
+func (p *pathParser) parseLogicalOr() (filterExpr, error) {
+	var terms orExpr
+	for {
+		term, err := p.parseLogicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		p.skipSpace()
+		if !strings.HasPrefix(p.rest(), "||") {
+			break
+		}
+		p.pos += 2
+		p.skipSpace()
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+// parseLogicalAnd expr && expr ...
+func (p *pathParser) parseLogicalAnd() (filterExpr, error) {
+	var terms andExpr
+	for {
+		term, err := p.parseBasicExpr()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		p.skipSpace()
+		if !strings.HasPrefix(p.rest(), "&&") {
+			break
+		}
+		p.pos += 2
+		p.skipSpace()
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+// parseBasicExpr negación, paréntesis, comparación o prueba de existencia
+func (p *pathParser) parseBasicExpr() (filterExpr, error) {
+	if p.consume('!') {
+		p.skipSpace()
+		if p.peek() == '(' {
+			expr, err := p.parseParen()
+			return notExpr{expr}, err
+		}
+		start := p.pos
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		expr, err := p.testExpr(operand, start)
+		return notExpr{expr}, err
+	}
+
+	if p.peek() == '(' {
+		return p.parseParen()
+	}
+
+	start := p.pos
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	op := p.comparisonOp()
+	if op == "" {
+		return p.testExpr(left, start)
+	}
+
+	if err := p.checkComparable(left, start); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	rightStart := p.pos
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkComparable(right, rightStart); err != nil {
+		return nil, err
+	}
+	return compareExpr{op: op, left: left, right: right}, nil
+}
+
+// parseParen ( expr )
+func (p *pathParser) parseParen() (filterExpr, error) {
+	p.pos++ // '('
+	p.skipSpace()
+	expr, err := p.parseLogicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.consume(')') {
+		return nil, p.errorf("se esperaba ')'")
+	}
+	return expr, nil
+}
+
+// comparisonOp consume un operador de comparación, si lo hay
+func (p *pathParser) comparisonOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(p.rest(), op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// testExpr convierte un operando usado como prueba: consulta (existencia) o función lógica
+func (p *pathParser) testExpr(operand pathOperand, start int) (filterExpr, error) {
+	switch o := operand.(type) {
+	case *pathQuery:
+		return existsExpr{query: o}, nil
+	case *pathFunctionCall:
+		if pathFunctions[o.name].result == logicalType {
+			return logicalCallExpr{call: o}, nil
+		}
+	}
+	p.pos = start
+	return nil, p.errorf("se esperaba una consulta, una función lógica o una comparación")
+}
+
+// checkComparable verifica que el operando produzca un único valor
+func (p *pathParser) checkComparable(operand pathOperand, start int) error {
+	switch o := operand.(type) {
+	case *pathLiteral:
+		return nil
+	case *pathQuery:
+		if o.singular() {
+			return nil
+		}
+		p.pos = start
+		return p.errorf("solo se pueden comparar consultas singulares (nombres e índices)")
+	case *pathFunctionCall:
+		if pathFunctions[o.name].result == valueType {
+			return nil
+		}
+		p.pos = start
+		return p.errorf("la función '%s' no devuelve un valor comparable", o.name)
+	}
+	p.pos = start
+	return p.errorf("operando no comparable")
+}
+
+// parseOperand lee un literal, una consulta (@ o $) o una llamada a función
+func (p *pathParser) parseOperand() (pathOperand, error) {
+	c := p.peek()
+	switch {
+	case c == '@' || c == '$':
+		p.pos++
+		return p.parseSegments(c == '$')
+	case c == '\'' || c == '"':
+		text, err := p.parseStringLiteral()
+		return &pathLiteral{value: text}, err
+	case c == '-' || isDigit(c):
+		lex := newLexer([]byte(p.rest()))
+		tok, err := lex.scanNumber()
+		if err != nil {
+			return nil, p.errorf("número inválido")
+		}
+		p.pos += tok.end
+		return &pathLiteral{value: Number(tok.text)}, nil
+	}
+
+	for _, literal := range []struct {
+		word  string
+		value interface{}
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if strings.HasPrefix(p.rest(), literal.word) && !p.identAt(p.pos+len(literal.word)) {
+			p.pos += len(literal.word)
+			return &pathLiteral{value: literal.value}, nil
+		}
+	}
+
+	if c >= 'a' && c <= 'z' {
+		return p.parseFunctionCall()
+	}
+	return nil, p.errorf("se esperaba un literal, una consulta o una función")
+}
+
+// identAt indica si en i continúa un identificador
+func (p *pathParser) identAt(i int) bool {
+	return i < len(p.src) && isIdentByte(p.src[i])
+}
+
+// parseFunctionCall nombre(arg, ...) con verificación de tipos de RFC 9535
+func (p *pathParser) parseFunctionCall() (pathOperand, error) {
+	start := p.pos
+	for p.pos < len(p.src) && isIdentByte(p.src[p.pos]) {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+	fn, known := pathFunctions[name]
+	if !known {
+		p.pos = start
+		return nil, p.errorf("función desconocida '%s'", name)
+	}
+	if !p.consume('(') {
+		return nil, p.errorf("se esperaba '(' después de '%s'", name)
+	}
+
+	call := &pathFunctionCall{name: name}
+	for {
+		p.skipSpace()
+		if len(call.args) == len(fn.params) {
+			break
+		}
+		if len(call.args) > 0 {
+			if !p.consume(',') {
+				return nil, p.errorf("la función '%s' espera %d argumentos", name, len(fn.params))
+			}
+			p.skipSpace()
+		}
+
+		argStart := p.pos
+		arg, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkArgument(arg, fn.params[len(call.args)], argStart); err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+	}
+	if !p.consume(')') {
+		return nil, p.errorf("la función '%s' espera %d argumentos", name, len(fn.params))
+	}
+
+	// Precompilar la regex cuando es un literal
+	if name == "match" || name == "search" {
+		if literal, ok := call.args[1].(*pathLiteral); ok {
+			if expr, isString := literal.value.(string); isString {
+				pattern, err := compileIRegexp(expr, name == "match")
+				if err != nil {
+					p.pos = start
+					return nil, p.errorf("expresión regular inválida: %v", err)
+				}
+				call.pattern = pattern
+			}
+		}
+	}
+	return call, nil
+}
+
+// checkArgument verifica el tipo de un argumento contra el parámetro declarado
+func (p *pathParser) checkArgument(arg pathOperand, param pathType, start int) error {
+	ok := false
+	switch param {
+	case valueType:
+		ok = p.checkComparable(arg, start) == nil
+	case nodesType:
+		_, ok = arg.(*pathQuery)
+	}
+	if !ok {
+		p.pos = start
+		return p.errorf("argumento de tipo incorrecto")
+	}
+	return nil
+}
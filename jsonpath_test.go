@@ -0,0 +1,251 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// bookstoreJSON documento de ejemplo de RFC 9535 (sección 1.5)
+const bookstoreJSON = `{
+  "store": {
+    "book": [
+      {"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+      {"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+      {"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+      {"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+    ],
+    "bicycle": {"color": "red", "price": 399}
+  }
+}`
+
+// queryPaths evalúa la consulta y devuelve solo las rutas normalizadas
+func queryPaths(t *testing.T, input, query string) []string {
+	t.Helper()
+	doc, err := NewParser().ParseJSONWithOptions(input, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	if err != nil {
+		t.Fatalf("ParseJSONWithOptions() error = %v", err)
+	}
+	results, err := QueryJSONPath(doc, query)
+	if err != nil {
+		t.Fatalf("QueryJSONPath(%s) error = %v", query, err)
+	}
+	paths := []string{}
+	for _, result := range results {
+		paths = append(paths, result.Path)
+	}
+	return paths
+}
+
+// Test para las consultas de ejemplo de RFC 9535 sobre la librería
+func TestJSONPathBookstore(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`$.store.book[*].author`, []string{
+			`$['store']['book'][0]['author']`, `$['store']['book'][1]['author']`,
+			`$['store']['book'][2]['author']`, `$['store']['book'][3]['author']`,
+		}},
+		{`$..author`, []string{
+			`$['store']['book'][0]['author']`, `$['store']['book'][1]['author']`,
+			`$['store']['book'][2]['author']`, `$['store']['book'][3]['author']`,
+		}},
+		{`$.store.*`, []string{`$['store']['book']`, `$['store']['bicycle']`}},
+		{`$.store..price`, []string{
+			`$['store']['book'][0]['price']`, `$['store']['book'][1]['price']`,
+			`$['store']['book'][2]['price']`, `$['store']['book'][3]['price']`,
+			`$['store']['bicycle']['price']`,
+		}},
+		{`$..book[2]`, []string{`$['store']['book'][2]`}},
+		{`$..book[-1]`, []string{`$['store']['book'][3]`}},
+		{`$..book[0,1]`, []string{`$['store']['book'][0]`, `$['store']['book'][1]`}},
+		{`$..book[:2]`, []string{`$['store']['book'][0]`, `$['store']['book'][1]`}},
+		{`$..book[?@.isbn]`, []string{`$['store']['book'][2]`, `$['store']['book'][3]`}},
+		{`$..book[?@.price<10]`, []string{`$['store']['book'][0]`, `$['store']['book'][2]`}},
+		{`$["store"]['bicycle'].color`, []string{`$['store']['bicycle']['color']`}},
+		{`$.store.book[?@.price > $.store.bicycle.price]`, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := queryPaths(t, bookstoreJSON, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test para los valores devueltos por una consulta
+func TestJSONPathValues(t *testing.T) {
+	doc, err := NewParser().ParseJSON(bookstoreJSON)
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+	results, err := QueryJSONPath(doc, `$.store.book[?@.author == 'Herman Melville'].title`)
+	if err != nil {
+		t.Fatalf("QueryJSONPath() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Value != "Moby Dick" {
+		t.Errorf("results = %v", results)
+	}
+
+	// La cantidad de nodos descendientes de $..*
+	results, _ = QueryJSONPath(doc, `$..*`)
+	if len(results) != 27 {
+		t.Errorf("$..* seleccionó %d nodos, want 27", len(results))
+	}
+}
+
+// Test para la semántica de slices
+func TestJSONPathSlices(t *testing.T) {
+	input := `["a","b","c","d","e","f","g"]`
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`$[1:3]`, []string{`$[1]`, `$[2]`}},
+		{`$[5:]`, []string{`$[5]`, `$[6]`}},
+		{`$[1:5:2]`, []string{`$[1]`, `$[3]`}},
+		{`$[5:1:-2]`, []string{`$[5]`, `$[3]`}},
+		{`$[::-1]`, []string{`$[6]`, `$[5]`, `$[4]`, `$[3]`, `$[2]`, `$[1]`, `$[0]`}},
+		{`$[-2:]`, []string{`$[5]`, `$[6]`}},
+		{`$[::0]`, []string{}},
+		{`$[10:20]`, []string{}},
+		{`$[-10:1]`, []string{`$[0]`}},
+		{`$[7]`, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := queryPaths(t, input, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test para filtros, comparaciones y funciones
+func TestJSONPathFilters(t *testing.T) {
+	input := `{"items":[
+		{"n":1,"tag":"alpha","list":[1,2,3]},
+		{"n":2.0,"tag":"beta","list":[]},
+		{"n":"2","tag":"b"},
+		{"n":null},
+		{"n":false,"tag":"gamma"}
+	]}`
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`$.items[?@.n == 2]`, []string{`$['items'][1]`}},
+		{`$.items[?@.n == '2']`, []string{`$['items'][2]`}},
+		{`$.items[?@.n == null]`, []string{`$['items'][3]`}},
+		{`$.items[?@.missing == @.other]`, []string{`$['items'][0]`, `$['items'][1]`, `$['items'][2]`, `$['items'][3]`, `$['items'][4]`}},
+		{`$.items[?@.n >= 1 && @.n < 2]`, []string{`$['items'][0]`}},
+		{`$.items[?@.n == false || @.n == null]`, []string{`$['items'][3]`, `$['items'][4]`}},
+		{`$.items[?!@.tag]`, []string{`$['items'][3]`}},
+		{`$.items[?!(@.n == 1 || @.tag)]`, []string{`$['items'][3]`}},
+		{`$.items[?@.tag > 'b']`, []string{`$['items'][1]`, `$['items'][4]`}},
+		{`$.items[?length(@.tag) == 4]`, []string{`$['items'][1]`}},
+		{`$.items[?count(@.list[*]) > 2]`, []string{`$['items'][0]`}},
+		{`$.items[?match(@.tag, 'b.*')]`, []string{`$['items'][1]`, `$['items'][2]`}},
+		{`$.items[?match(@.tag, 'b')]`, []string{`$['items'][2]`}},
+		{`$.items[?search(@.tag, 'mm')]`, []string{`$['items'][4]`}},
+		{`$.items[?value(@..tag) == 'alpha']`, []string{`$['items'][0]`}},
+		{`$.items[?@.list[?@ > 2]]`, []string{`$['items'][0]`}},
+		{`$.items[?@.n == $.items[0].n]`, []string{`$['items'][0]`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := queryPaths(t, input, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test para las I-Regexp de match y search (RFC 9485)
+func TestJSONPathIRegexp(t *testing.T) {
+	input := `["xb","b","a\rb","a\nb","axb","a.b"]`
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`$[?match(@, 'a.b')]`, []string{`$[4]`, `$[5]`}},
+		{`$[?search(@, 'a.b')]`, []string{`$[4]`, `$[5]`}},
+		{`$[?match(@, 'a[.]b')]`, []string{`$[5]`}},
+		{`$[?match(@, 'a\\.b')]`, []string{`$[5]`}},
+		{`$[?match(@, 'b|xb')]`, []string{`$[0]`, `$[1]`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := queryPaths(t, input, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// El error describe la expresión del usuario, no el ancla que la envuelve
+	_, err := CompileJSONPath(`$[?match(@, 'a\\')]`)
+	if err == nil || strings.Contains(err.Error(), `\z`) {
+		t.Errorf("CompileJSONPath() error = %v", err)
+	}
+}
+
+// Test para el escape de claves en rutas normalizadas
+func TestJSONPathNormalizedPaths(t *testing.T) {
+	input := `{"it's":{"a\\b":1},"line\nbreak":2,"ctl\u0001":3}`
+	got := queryPaths(t, input, `$..*`)
+	want := []string{`$['it\'s']`, `$['line\nbreak']`, `$['ctl\u0001']`, `$['it\'s']['a\\b']`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("paths = %v, want %v", got, want)
+	}
+
+	if got := queryPaths(t, input, `$['it\'s']["a\\b"]`); len(got) != 1 {
+		t.Errorf("la consulta con escapes no seleccionó el nodo: %v", got)
+	}
+}
+
+// Test para consultas inválidas
+func TestJSONPathSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`store.book`, "debe comenzar con '$'"},
+		{`$.`, "se esperaba un nombre de miembro"},
+		{`$[`, "selector inválido"},
+		{`$[0`, "se esperaba ',' o ']'"},
+		{`$[01]`, "entero inválido"},
+		{`$[-0]`, "entero inválido"},
+		{`$[9007199254740992]`, "entero fuera de rango"},
+		{`$['abc]`, "string sin cerrar"},
+		{`$[?@.a ==]`, "se esperaba un literal"},
+		{`$[?@.* == 1]`, "consultas singulares"},
+		{`$[?length(@)]`, "se esperaba una consulta, una función lógica"},
+		{`$[?match(@.a, 'x') == true]`, "no devuelve un valor comparable"},
+		{`$[?count(1) > 0]`, "argumento de tipo incorrecto"},
+		{`$[?foo(@)]`, "función desconocida"},
+		{`$[?match(@.a, '(')]`, "expresión regular inválida"},
+		{`$[?match(@.a, 'a)|(?:b')]`, "expresión regular inválida"},
+		{`$.a extra`, "contenido inesperado"},
+		{"$.a ", "espacios al final"},
+		{"$[0]\n", "espacios al final"},
+		{" $.a", "debe comenzar con '$'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := CompileJSONPath(tt.query)
+			if err == nil {
+				t.Fatalf("CompileJSONPath() no devolvió error")
+			}
+			pathErr := AsJSONPathError(err)
+			if pathErr == nil || !strings.Contains(pathErr.Msg, tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
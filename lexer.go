@@ -0,0 +1,476 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// tokenKind identifica el tipo de token producido por el lexer
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenBeginObject
+	tokenEndObject
+	tokenBeginArray
+	tokenEndArray
+	tokenColon
+	tokenComma
+	tokenString
+	tokenNumber
+	tokenTrue
+	tokenFalse
+	tokenNull
+)
+
+// lexerChunkSize tamaño de cada lectura cuando el lexer consume un io.Reader
+const lexerChunkSize = 32 * 1024
+
+// token unidad léxica con su posición absoluta dentro de la entrada
+type token struct {
+	kind  tokenKind
+	start int    // offset del primer byte del token
+	end   int    // offset siguiente al último byte del token
+	text  string // valor decodificado (strings), literal (números) o símbolo
+}
+
+// lexer analizador léxico de un solo recorrido: cada byte se examina una vez.
+// Puede trabajar sobre un buffer completo o sobre un io.Reader; en ese caso
+// solo retiene el token en curso y descarta lo ya consumido.
+type lexer struct {
+	data []byte
+	pos  int
+
+	// Lectura incremental (nil cuando la entrada completa está en data)
+	reader  io.Reader
+	readErr error
+
+	// Ubicación de data[0] dentro del documento completo
+	base       int // offset absoluto
+	baseLine   int // línea (desde 1)
+	baseColumn int // caracteres desde el inicio de la línea
+
+	// discard evita materializar el texto de strings y números (solo validación)
+	discard bool
+
+	// expected describe lo que el parser espera a continuación (para errores)
+	expected string
+
+	// lenient acepta caracteres de control y reemplaza surrogates sueltos y
+	// UTF-8 inválido por U+FFFD en lugar de rechazarlos
+	lenient bool
+}
+
+// newLexer crea un lexer sobre los bytes de la entrada
+func newLexer(data []byte) *lexer {
+	return &lexer{data: data, baseLine: 1}
+}
+
+// newReaderLexer crea un lexer que lee la entrada por bloques desde r
+func newReaderLexer(r io.Reader) *lexer {
+	return &lexer{data: make([]byte, 0, lexerChunkSize), reader: r, baseLine: 1}
+}
+
+// offset devuelve la posición absoluta de un índice del buffer
+func (l *lexer) offset(i int) int {
+	return l.base + i
+}
+
+// avail garantiza que data[i] esté disponible, leyendo más entrada si hace falta
+func (l *lexer) avail(i int) bool {
+	if i < len(l.data) {
+		return true
+	}
+	return l.fill(i)
+}
+
+// fill lee del reader hasta que data[i] exista o la entrada se agote
+func (l *lexer) fill(i int) bool {
+	for i >= len(l.data) {
+		if l.reader == nil || l.readErr != nil {
+			return false
+		}
+		if len(l.data) == cap(l.data) {
+			grown := make([]byte, len(l.data), 2*cap(l.data)+lexerChunkSize)
+			copy(grown, l.data)
+			l.data = grown
+		}
+		n, err := l.reader.Read(l.data[len(l.data):cap(l.data)])
+		l.data = l.data[:len(l.data)+n]
+		if err != nil {
+			l.readErr = err
+		}
+	}
+	return true
+}
+
+// compact descarta los bytes ya consumidos; solo se llama entre tokens para
+// que los índices locales de los escáneres sigan siendo válidos
+func (l *lexer) compact() {
+	if l.reader == nil || l.pos < lexerChunkSize {
+		return
+	}
+
+	consumed := l.data[:l.pos]
+	if i := bytes.LastIndexByte(consumed, '\n'); i >= 0 {
+		l.baseLine += bytes.Count(consumed, []byte("\n"))
+		l.baseColumn = utf8.RuneCount(consumed[i+1:])
+	} else {
+		l.baseColumn += utf8.RuneCount(consumed)
+	}
+
+	n := copy(l.data, l.data[l.pos:])
+	l.data = l.data[:n]
+	l.base += l.pos
+	l.pos = 0
+}
+
+// ioError devuelve el error de lectura real (distinto de io.EOF), si lo hubo
+func (l *lexer) ioError() error {
+	if l.readErr != nil && l.readErr != io.EOF {
+		return l.readErr
+	}
+	return nil
+}
+
+// errorf construye un SyntaxError con la posición absoluta del problema
+func (l *lexer) errorf(offset int, expected, format string, args ...interface{}) error {
+	// Una entrada truncada por un fallo de lectura no es un error de sintaxis
+	if err := l.ioError(); err != nil {
+		return err
+	}
+	return locateSyntaxError(l.data, offset-l.base, l.base, l.baseLine, l.baseColumn, expected, fmt.Sprintf(format, args...))
+}
+
+// skipWhitespace avanza sobre los espacios en blanco permitidos por JSON
+func (l *lexer) skipWhitespace() {
+	for l.avail(l.pos) {
+		switch l.data[l.pos] {
+		case ' ', '\t', '\n', '\r':
+			l.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek devuelve el siguiente byte significativo sin consumirlo
+func (l *lexer) peek() (byte, bool) {
+	l.skipWhitespace()
+	if !l.avail(l.pos) {
+		return 0, false
+	}
+	return l.data[l.pos], true
+}
+
+// next devuelve el siguiente token de la entrada
+func (l *lexer) next() (token, error) {
+	l.compact()
+	l.skipWhitespace()
+	if !l.avail(l.pos) {
+		if err := l.ioError(); err != nil {
+			return token{}, err
+		}
+		return token{kind: tokenEOF, start: l.offset(l.pos), end: l.offset(l.pos)}, nil
+	}
+
+	start := l.pos
+	c := l.data[start]
+	switch c {
+	case '{':
+		return l.symbol(tokenBeginObject, "{"), nil
+	case '}':
+		return l.symbol(tokenEndObject, "}"), nil
+	case '[':
+		return l.symbol(tokenBeginArray, "["), nil
+	case ']':
+		return l.symbol(tokenEndArray, "]"), nil
+	case ':':
+		return l.symbol(tokenColon, ":"), nil
+	case ',':
+		return l.symbol(tokenComma, ","), nil
+	case '"':
+		return l.scanString()
+	case 't':
+		return l.scanLiteral("true", tokenTrue)
+	case 'f':
+		return l.scanLiteral("false", tokenFalse)
+	case 'n':
+		return l.scanLiteral("null", tokenNull)
+	}
+
+	if c == '-' || (c >= '0' && c <= '9') {
+		return l.scanNumber()
+	}
+
+	expected := l.expected
+	if expected == "" {
+		expected = "valor JSON"
+	}
+	return token{}, l.errorf(l.offset(start), expected, "formato JSON inválido: carácter inesperado %s", l.charAt(start))
+}
+
+// symbol consume un token de un solo carácter
+func (l *lexer) symbol(kind tokenKind, text string) token {
+	start := l.offset(l.pos)
+	l.pos++
+	return token{kind: kind, start: start, end: start + 1, text: text}
+}
+
+// scanLiteral reconoce las palabras reservadas true, false y null
+func (l *lexer) scanLiteral(word string, kind tokenKind) (token, error) {
+	start := l.pos
+	end := start + len(word)
+	if !l.avail(end-1) || string(l.data[start:end]) != word || (l.avail(end) && isIdentByte(l.data[end])) {
+		return token{}, l.errorf(l.offset(start), "true, false o null", "formato JSON inválido: literal desconocido '%s'", l.wordAt(start))
+	}
+	l.pos = end
+	return token{kind: kind, start: l.offset(start), end: l.offset(end), text: word}, nil
+}
+
+// scanNumber reconoce números según la gramática estricta de JSON
+func (l *lexer) scanNumber() (token, error) {
+	start := l.pos
+	i := start
+
+	if l.avail(i) && l.data[i] == '-' {
+		i++
+	}
+
+	switch {
+	case l.avail(i) && l.data[i] == '0':
+		i++
+		if l.avail(i) && isDigit(l.data[i]) {
+			for l.avail(i) && isDigit(l.data[i]) {
+				i++
+			}
+			return token{}, l.errorf(l.offset(start), "", "números no pueden empezar con múltiples ceros: %s", l.data[start:i])
+		}
+	case l.avail(i) && isDigit(l.data[i]):
+		for l.avail(i) && isDigit(l.data[i]) {
+			i++
+		}
+	default:
+		return token{}, l.errorf(l.offset(i), "dígito", "formato JSON inválido: número incompleto '%s'", l.data[start:i])
+	}
+
+	if l.avail(i) && l.data[i] == '.' {
+		i++
+		if !l.avail(i) || !isDigit(l.data[i]) {
+			return token{}, l.errorf(l.offset(i), "dígito", "número decimal mal formado: %s", l.data[start:i])
+		}
+		for l.avail(i) && isDigit(l.data[i]) {
+			i++
+		}
+	}
+
+	if l.avail(i) && (l.data[i] == 'e' || l.data[i] == 'E') {
+		i++
+		if l.avail(i) && (l.data[i] == '+' || l.data[i] == '-') {
+			i++
+		}
+		if !l.avail(i) || !isDigit(l.data[i]) {
+			return token{}, l.errorf(l.offset(i), "dígito", "exponente inválido en notación científica: %s", l.data[start:i])
+		}
+		for l.avail(i) && isDigit(l.data[i]) {
+			i++
+		}
+	}
+
+	l.pos = i
+	tok := token{kind: tokenNumber, start: l.offset(start), end: l.offset(i)}
+	if !l.discard {
+		tok.text = string(l.data[start:i])
+	}
+	return tok, nil
+}
+
+// scanString reconoce un string entre comillas y decodifica sus escapes
+func (l *lexer) scanString() (token, error) {
+	start := l.pos
+	i := start + 1
+	decode := false   // escapes o caracteres de control: requiere decodificar
+	nonASCII := false // requiere validar UTF-8
+
+	for {
+		if !l.avail(i) {
+			return token{}, l.errorf(l.offset(start), "\"", "formato JSON inválido: string sin cerrar")
+		}
+		c := l.data[i]
+		if c == '"' {
+			break
+		}
+		if c == '\\' {
+			decode = true
+			i += 2
+			continue
+		}
+		if c < 0x20 {
+			decode = true
+		} else if c >= utf8.RuneSelf {
+			nonASCII = true
+		}
+		i++
+	}
+
+	content := l.data[start+1 : i]
+	if !decode && nonASCII && !utf8.Valid(content) {
+		decode = true
+	}
+
+	l.pos = i + 1
+	tok := token{kind: tokenString, start: l.offset(start), end: l.offset(l.pos)}
+	if !decode {
+		if !l.discard {
+			tok.text = string(content)
+		}
+		return tok, nil
+	}
+
+	text, offset, err := unquoteContent(content, l.lenient)
+	if err != nil {
+		expected := "carácter escapado o UTF-8 válido"
+		if content[offset] == '\\' {
+			expected = "secuencia de escape válida"
+		}
+		return token{}, l.errorf(l.offset(start+1+offset), expected, "%v", err)
+	}
+	if !l.discard {
+		tok.text = text
+	}
+	return tok, nil
+}
+
+// unquoteContent decodifica el contenido de un string JSON (sin comillas)
+// según RFC 8259: empareja surrogates UTF-16 y rechaza caracteres de control
+// sin escapar, surrogates sueltos y UTF-8 inválido. Con lenient los
+// caracteres de control se conservan y lo inválido se reemplaza por U+FFFD.
+// En caso de error devuelve el offset relativo de la secuencia inválida.
+func unquoteContent(s []byte, lenient bool) (string, int, error) {
+	buf := make([]byte, 0, len(s))
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\':
+			// Secuencia de escape: se procesa debajo
+		case c < 0x20:
+			if !lenient {
+				return "", i, fmt.Errorf("formato JSON inválido: carácter de control %U sin escapar en string", rune(c))
+			}
+			buf = append(buf, c)
+			i++
+			continue
+		case c < utf8.RuneSelf:
+			buf = append(buf, c)
+			i++
+			continue
+		default:
+			r, size := utf8.DecodeRune(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				if !lenient {
+					return "", i, fmt.Errorf("formato JSON inválido: secuencia UTF-8 inválida en string (byte 0x%02x)", c)
+				}
+				buf = utf8.AppendRune(buf, utf8.RuneError)
+			} else {
+				buf = append(buf, s[i:i+size]...)
+			}
+			i += size
+			continue
+		}
+
+		if i+1 >= len(s) {
+			return "", i, fmt.Errorf("formato JSON inválido: secuencia de escape incompleta")
+		}
+
+		switch s[i+1] {
+		case '"':
+			buf = append(buf, '"')
+		case '\\':
+			buf = append(buf, '\\')
+		case '/':
+			buf = append(buf, '/')
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			r, err := decodeUnicodeEscape(s, i)
+			if err != nil {
+				return "", i, err
+			}
+			start := i
+			i += 6
+
+			if utf16.IsSurrogate(r) {
+				// Un surrogate alto debe ir seguido de un \uXXXX con el surrogate bajo
+				if r < 0xDC00 && i+1 < len(s) && s[i] == '\\' && s[i+1] == 'u' {
+					if low, err := decodeUnicodeEscape(s, i); err == nil && low >= 0xDC00 && low <= 0xDFFF {
+						buf = utf8.AppendRune(buf, utf16.DecodeRune(r, low))
+						i += 6
+						continue
+					}
+				}
+				if !lenient {
+					return "", start, fmt.Errorf("formato JSON inválido: surrogate UTF-16 sin pareja '\\u%s'", s[start+2:start+6])
+				}
+				r = utf8.RuneError
+			}
+			buf = utf8.AppendRune(buf, r)
+			continue
+		default:
+			return "", i, fmt.Errorf("formato JSON inválido: secuencia de escape inválida '\\%c'", s[i+1])
+		}
+		i += 2
+	}
+	return string(buf), 0, nil
+}
+
+// decodeUnicodeEscape interpreta la secuencia \uXXXX que comienza en s[i]
+func decodeUnicodeEscape(s []byte, i int) (rune, error) {
+	if i+6 > len(s) {
+		return 0, fmt.Errorf("formato JSON inválido: secuencia unicode incompleta")
+	}
+	codePoint, err := strconv.ParseUint(string(s[i+2:i+6]), 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("formato JSON inválido: secuencia unicode inválida '\\u%s'", s[i+2:i+6])
+	}
+	return rune(codePoint), nil
+}
+
+// wordAt extrae la palabra que comienza en el índice i (para mensajes de error)
+func (l *lexer) wordAt(i int) string {
+	end := i
+	for l.avail(end) && isIdentByte(l.data[end]) {
+		end++
+	}
+	if end == i {
+		return l.charAt(i)
+	}
+	return string(l.data[i:end])
+}
+
+// charAt representa el carácter en el índice i entre comillas simples
+func (l *lexer) charAt(i int) string {
+	// Asegurar la runa completa aunque llegue partida entre lecturas
+	l.avail(i + utf8.UTFMax - 1)
+	r, _ := utf8.DecodeRune(l.data[i:])
+	return fmt.Sprintf("'%c'", r)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_'
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test para la secuencia de tokens producida por el lexer
+func TestLexerTokens(t *testing.T) {
+	input := `{"a": [1, -2.5e3, true, false, null], "b\n": "x"}`
+	expected := []struct {
+		kind tokenKind
+		text string
+	}{
+		{tokenBeginObject, "{"},
+		{tokenString, "a"},
+		{tokenColon, ":"},
+		{tokenBeginArray, "["},
+		{tokenNumber, "1"},
+		{tokenComma, ","},
+		{tokenNumber, "-2.5e3"},
+		{tokenComma, ","},
+		{tokenTrue, "true"},
+		{tokenComma, ","},
+		{tokenFalse, "false"},
+		{tokenComma, ","},
+		{tokenNull, "null"},
+		{tokenEndArray, "]"},
+		{tokenComma, ","},
+		{tokenString, "b\n"},
+		{tokenColon, ":"},
+		{tokenString, "x"},
+		{tokenEndObject, "}"},
+		{tokenEOF, ""},
+	}
+
+	lex := newLexer([]byte(input))
+	for i, want := range expected {
+		tok, err := lex.next()
+		if err != nil {
+			t.Fatalf("token %d: error inesperado %v", i, err)
+		}
+		if tok.kind != want.kind || tok.text != want.text {
+			t.Fatalf("token %d = (%d, %q), want (%d, %q)", i, tok.kind, tok.text, want.kind, want.text)
+		}
+	}
+}
+
+// Test para errores léxicos con su posición absoluta
+func TestLexerErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		errorContains string
+	}{
+		{"String sin cerrar", `"abc`, "string sin cerrar en línea 1, columna 1"},
+		{"Escape inválido", `"a\x"`, `secuencia de escape inválida '\x' en línea 1, columna 3`},
+		{"Unicode inválido", `"\u12G4"`, "secuencia unicode inválida"},
+		{"Literal desconocido", `nul`, "literal desconocido 'nul'"},
+		{"Literal pegado", `truex`, "literal desconocido 'truex'"},
+		{"Ceros a la izquierda", `0012`, "múltiples ceros: 0012"},
+		{"Decimal sin dígitos", `1.e5`, "número decimal mal formado: 1."},
+		{"Exponente vacío", `2e+`, "exponente inválido en notación científica: 2e+"},
+		{"Carácter inesperado", `@`, "carácter inesperado '@'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newLexer([]byte(tt.input)).next()
+			if err == nil {
+				t.Fatalf("next() esperaba error para %q", tt.input)
+			}
+			if !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("next() error = %q, want to contain %q", err.Error(), tt.errorContains)
+			}
+		})
+	}
+}
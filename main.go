@@ -1,16 +1,41 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	goparser "go/parser"
+	"go/scanner"
+	gotoken "go/token"
+	"go/types"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type ParseRequest struct {
-	JSON string `json:"json"`
+	JSON           string `json:"json"`
+	PreserveOrder  bool   `json:"preserve_order,omitempty"`  // Conservar el orden original de las claves
+	Format         bool   `json:"format,omitempty"`          // Devolver además el JSON formateado
+	NumberMode     string `json:"number_mode,omitempty"`     // float64 (defecto), number o int64
+	LenientStrings bool   `json:"lenient_strings,omitempty"` // Reemplazar surrogates sueltos / UTF-8 inválido
+}
+
+// options traduce los campos de la petición a opciones del parser
+func (req ParseRequest) options() (ParseOptions, error) {
+	mode, err := ParseNumberMode(req.NumberMode)
+	if err != nil {
+		return ParseOptions{}, err
+	}
+	return ParseOptions{PreserveOrder: req.PreserveOrder, NumberMode: mode, LenientStrings: req.LenientStrings}, nil
 }
 
 type ParseResponse struct {
@@ -22,66 +47,1362 @@ type ParseResponse struct {
 	Performance  string         `json:"performance,omitempty"`
 	JSONType     string         `json:"json_type,omitempty"`
 	ElementCount map[string]int `json:"element_count,omitempty"`
+	ErrorDetails *SyntaxError   `json:"error_details,omitempty"`
+	Formatted    string         `json:"formatted,omitempty"`
+}
+
+// FormatRequest petición de /api/format y /api/minify
+type FormatRequest struct {
+	JSON           string `json:"json"`
+	IndentWidth    int    `json:"indent_width,omitempty"`     // Espacios (o tabs) por nivel; 2 por defecto
+	UseTabs        bool   `json:"use_tabs,omitempty"`         // Sangría con tabs en lugar de espacios
+	SortKeys       bool   `json:"sort_keys,omitempty"`        // Ordenar claves alfabéticamente
+	MaxInlineWidth int    `json:"max_inline_width,omitempty"` // Contenedores cortos en una línea (0 = nunca)
+	ASCIIOnly      bool   `json:"ascii_only,omitempty"`       // Escapar todo lo no ASCII
+}
+
+// FormatResponse resultado del formateo/minificación con el ahorro de bytes
+type FormatResponse struct {
+	Success        bool         `json:"success"`
+	Output         string       `json:"output,omitempty"`
+	Error          string       `json:"error,omitempty"`
+	Method         string       `json:"method"`
+	ProcessTime    string       `json:"process_time,omitempty"`
+	OriginalBytes  int          `json:"original_bytes"`
+	OutputBytes    int          `json:"output_bytes"`
+	BytesSaved     int          `json:"bytes_saved"`
+	SavingsPercent string       `json:"savings_percent,omitempty"`
+	ErrorDetails   *SyntaxError `json:"error_details,omitempty"`
+}
+
+// CanonicalizeResponse forma canónica RFC 8785 y su digest SHA-256
+type CanonicalizeResponse struct {
+	Success      bool         `json:"success"`
+	Canonical    string       `json:"canonical,omitempty"`
+	SHA256       string       `json:"sha256,omitempty"`
+	Bytes        int          `json:"bytes,omitempty"`
+	Error        string       `json:"error,omitempty"`
+	Method       string       `json:"method"`
+	ProcessTime  string       `json:"process_time,omitempty"`
+	ErrorDetails *SyntaxError `json:"error_details,omitempty"`
+}
+
+// PointerRequest petición de /api/pointer
+type PointerRequest struct {
+	JSON    string `json:"json"`
+	Pointer string `json:"pointer"`         // JSON Pointer (RFC 6901); "" es la raíz
+	Op      string `json:"op,omitempty"`    // get (defecto), set o delete
+	Value   string `json:"value,omitempty"` // JSON del valor a asignar (solo set)
+}
+
+// PointerResponse valor apuntado o documento resultante de /api/pointer
+type PointerResponse struct {
+	Success      bool          `json:"success"`
+	Op           string        `json:"op"`
+	Pointer      string        `json:"pointer"`
+	Value        interface{}   `json:"value,omitempty"`
+	ValueType    string        `json:"value_type,omitempty"`
+	Formatted    string        `json:"formatted,omitempty"` // valor apuntado, exacto (get)
+	Document     string        `json:"document,omitempty"`  // documento modificado (set/delete)
+	Error        string        `json:"error,omitempty"`
+	Method       string        `json:"method"`
+	ErrorDetails *SyntaxError  `json:"error_details,omitempty"`
+	PointerError *PointerError `json:"pointer_error,omitempty"`
+}
+
+// QueryRequest petición de /api/query
+type QueryRequest struct {
+	JSON  string `json:"json"`
+	Query string `json:"query"` // consulta JSONPath (RFC 9535)
+}
+
+// QueryResponse nodos seleccionados por una consulta JSONPath
+type QueryResponse struct {
+	Success      bool             `json:"success"`
+	Query        string           `json:"query"`
+	Count        int              `json:"count"`
+	Results      []JSONPathResult `json:"results"`
+	Error        string           `json:"error,omitempty"`
+	Method       string           `json:"method"`
+	ErrorDetails *SyntaxError     `json:"error_details,omitempty"`
+	QueryError   *JSONPathError   `json:"query_error,omitempty"`
+}
+
+// PatchRequest petición de /api/patch
+type PatchRequest struct {
+	JSON  string `json:"json"`
+	Patch string `json:"patch"` // documento JSON Patch (RFC 6902)
+}
+
+// PatchResponse documento resultante de aplicar un JSON Patch
+type PatchResponse struct {
+	Success      bool         `json:"success"`
+	Document     string       `json:"document,omitempty"` // documento resultante formateado
+	Operations   int          `json:"operations"`
+	Error        string       `json:"error,omitempty"`
+	Method       string       `json:"method"`
+	ErrorDetails *SyntaxError `json:"error_details,omitempty"`
+	PatchError   *PatchError  `json:"patch_error,omitempty"`
+}
+
+// DiffPatchRequest petición de /api/diff-patch
+type DiffPatchRequest struct {
+	Original string `json:"original"`
+	Modified string `json:"modified"`
+}
+
+// DiffPatchResponse JSON Patch que transforma original en modified
+type DiffPatchResponse struct {
+	Success      bool         `json:"success"`
+	Patch        string       `json:"patch,omitempty"` // patch formateado
+	Operations   int          `json:"operations"`
+	Error        string       `json:"error,omitempty"`
+	Method       string       `json:"method"`
+	ErrorDetails *SyntaxError `json:"error_details,omitempty"`
+}
+
+// MergePatchRequest petición de /api/merge-patch
+type MergePatchRequest struct {
+	JSON     string `json:"json"`               // documento destino (apply) u original (create)
+	Patch    string `json:"patch,omitempty"`    // merge patch a aplicar (op "apply")
+	Modified string `json:"modified,omitempty"` // documento modificado (op "create")
+	Op       string `json:"op"`                 // apply (por defecto) o create
+}
+
+// MergePatchResponse documento resultante o merge patch generado
+type MergePatchResponse struct {
+	Success      bool         `json:"success"`
+	Op           string       `json:"op"`
+	Document     string       `json:"document,omitempty"` // resultado de apply, formateado
+	Patch        string       `json:"patch,omitempty"`    // resultado de create, formateado
+	Error        string       `json:"error,omitempty"`
+	Method       string       `json:"method"`
+	ErrorDetails *SyntaxError `json:"error_details,omitempty"`
+}
+
+// DiffRequest petición de /api/diff
+type DiffRequest struct {
+	Original         string   `json:"original"`
+	Modified         string   `json:"modified"`
+	IgnoreArrayOrder bool     `json:"ignore_array_order"`
+	IgnorePaths      []string `json:"ignore_paths"` // JSON Pointers; "*" coincide con cualquier segmento
+}
+
+// DiffResponse diferencias estructurales y ambos documentos formateados para
+// mostrarlos lado a lado
+type DiffResponse struct {
+	Success      bool           `json:"success"`
+	Equal        bool           `json:"equal"`
+	Entries      []DiffEntry    `json:"entries"`
+	Summary      map[string]int `json:"summary"` // cantidad por tipo de diferencia
+	Original     string         `json:"original,omitempty"`
+	Modified     string         `json:"modified,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	Method       string         `json:"method"`
+	ErrorDetails *SyntaxError   `json:"error_details,omitempty"`
+	PointerError *PointerError  `json:"pointer_error,omitempty"`
+}
+
+// SchemaValidateRequest petición de /api/schema/validate
+type SchemaValidateRequest struct {
+	Schema string `json:"schema"` // JSON Schema (draft 2020-12)
+	JSON   string `json:"json"`   // instancia a validar
+}
+
+// SchemaValidateResponse resultado de validar una instancia contra un esquema
+type SchemaValidateResponse struct {
+	Success      bool              `json:"success"` // la solicitud se procesó (esquema e instancia parseados)
+	Valid        bool              `json:"valid"`
+	ErrorCount   int               `json:"error_count"`
+	Errors       []SchemaViolation `json:"errors"`
+	Error        string            `json:"error,omitempty"`
+	Method       string            `json:"method"`
+	ErrorDetails *SyntaxError      `json:"error_details,omitempty"`
+	SchemaError  *SchemaError      `json:"schema_error,omitempty"`
+}
+
+// SchemaInferRequest petición de /api/schema/infer
+type SchemaInferRequest struct {
+	Samples       []string `json:"samples"`                   // documentos JSON de muestra
+	JSON          string   `json:"json,omitempty"`            // una muestra adicional
+	MaxEnumValues int      `json:"max_enum_values,omitempty"` // 0 usa el valor por defecto; negativo desactiva enum
+}
+
+// SchemaInferResponse esquema inferido a partir de las muestras
+type SchemaInferResponse struct {
+	Success      bool         `json:"success"`
+	Schema       string       `json:"schema,omitempty"` // esquema formateado
+	Samples      int          `json:"samples"`
+	Error        string       `json:"error,omitempty"`
+	Method       string       `json:"method"`
+	ErrorDetails *SyntaxError `json:"error_details,omitempty"`
+}
+
+// YAMLToJSONRequest petición de /api/convert/yaml-to-json
+type YAMLToJSONRequest struct {
+	YAML        string `json:"yaml"`
+	IndentWidth int    `json:"indent_width,omitempty"` // Espacios por nivel del JSON; 2 por defecto
+}
+
+// JSONToYAMLRequest petición de /api/convert/json-to-yaml
+type JSONToYAMLRequest struct {
+	JSON          string `json:"json"`
+	MultiDocument bool   `json:"multi_document,omitempty"` // Cada elemento del array raíz como un documento YAML
+}
+
+// YAMLConvertResponse resultado de las conversiones entre YAML y JSON
+type YAMLConvertResponse struct {
+	Success      bool         `json:"success"`
+	Output       string       `json:"output,omitempty"`
+	Documents    int          `json:"documents"` // documentos YAML leídos o escritos
+	Error        string       `json:"error,omitempty"`
+	Method       string       `json:"method"`
+	ProcessTime  string       `json:"process_time,omitempty"`
+	ErrorDetails *SyntaxError `json:"error_details,omitempty"`
+}
+
+// XMLToJSONRequest petición de /api/convert/xml-to-json
+type XMLToJSONRequest struct {
+	XML             string `json:"xml"`
+	Convention      string `json:"convention,omitempty"`       // attributes (defecto), badgerfish o parker
+	AttributePrefix string `json:"attribute_prefix,omitempty"` // Prefijo de atributos en attributes; "@" por defecto
+	TextKey         string `json:"text_key,omitempty"`         // Clave del texto en attributes; "#text" por defecto
+	IndentWidth     int    `json:"indent_width,omitempty"`     // Espacios por nivel del JSON; 2 por defecto
+}
+
+// JSONToXMLRequest petición de /api/convert/json-to-xml
+type JSONToXMLRequest struct {
+	JSON            string `json:"json"`
+	Convention      string `json:"convention,omitempty"`
+	AttributePrefix string `json:"attribute_prefix,omitempty"`
+	TextKey         string `json:"text_key,omitempty"`
+	RootName        string `json:"root_name,omitempty"` // Elemento raíz si el JSON no lo determina; "root" por defecto
+}
+
+// XMLConvertResponse resultado de las conversiones entre XML y JSON
+type XMLConvertResponse struct {
+	Success      bool         `json:"success"`
+	Output       string       `json:"output,omitempty"`
+	Convention   string       `json:"convention"`
+	Error        string       `json:"error,omitempty"`
+	Method       string       `json:"method"`
+	ProcessTime  string       `json:"process_time,omitempty"`
+	ErrorDetails *SyntaxError `json:"error_details,omitempty"`
+}
+
+// CSVToJSONRequest petición de /api/convert/csv-to-json
+type CSVToJSONRequest struct {
+	CSV         string `json:"csv"`
+	Delimiter   string `json:"delimiter,omitempty"`    // Separador; "," por defecto ("\t" o "tab" para tabulador)
+	Quote       string `json:"quote,omitempty"`        // Comilla; '"' por defecto
+	KeepStrings bool   `json:"keep_strings,omitempty"` // No convertir números, booleanos ni {} / []
+	IndentWidth int    `json:"indent_width,omitempty"` // Espacios por nivel del JSON; 2 por defecto
+}
+
+// JSONToCSVRequest petición de /api/convert/json-to-csv
+type JSONToCSVRequest struct {
+	JSON      string `json:"json"` // array de objetos (o un objeto)
+	Delimiter string `json:"delimiter,omitempty"`
+	Quote     string `json:"quote,omitempty"`
+}
+
+// CSVConvertResponse resultado de las conversiones entre CSV y JSON
+type CSVConvertResponse struct {
+	Success      bool         `json:"success"`
+	Output       string       `json:"output,omitempty"`
+	Rows         int          `json:"rows"`
+	Columns      []string     `json:"columns,omitempty"`
+	Error        string       `json:"error,omitempty"`
+	Method       string       `json:"method"`
+	ProcessTime  string       `json:"process_time,omitempty"`
+	ErrorDetails *SyntaxError `json:"error_details,omitempty"`
+	CSVError     *CSVError    `json:"csv_error,omitempty"`
+}
+
+// maxIndentWidth límite razonable de sangría por nivel
+const maxIndentWidth = 16
+
+// Parser global para reutilizar regex compiladas (máximo rendimiento)
+var globalParser = NewParser()
+
+func main() {
+	// Verificar que el parser está funcionando correctamente
+	fmt.Println("🔥 Inicializando Parser JSON con Expresiones Regulares...")
+	testResult, testErr := globalParser.ParseJSON(`{"test": "working"}`)
+	if testErr != nil {
+		log.Fatalf("❌ Error inicializando parser: %v", testErr)
+	}
+	fmt.Printf("✅ Parser inicializado correctamente: %+v\n", testResult)
+
+	// Servir archivos estáticos
+	http.Handle("/", http.FileServer(http.Dir("./static/")))
+
+	// API endpoints optimizados
+	http.HandleFunc("/api/parse", parseHandler)
+	http.HandleFunc("/api/validate", validateHandler)
+	http.HandleFunc("/api/format", formatHandler)
+	http.HandleFunc("/api/minify", minifyHandler)
+	http.HandleFunc("/api/canonicalize", canonicalizeHandler)
+	http.HandleFunc("/api/pointer", pointerHandler)
+	http.HandleFunc("/api/query", queryHandler)
+	http.HandleFunc("/api/patch", patchHandler)
+	http.HandleFunc("/api/diff-patch", diffPatchHandler)
+	http.HandleFunc("/api/merge-patch", mergePatchHandler)
+	http.HandleFunc("/api/diff", diffHandler)
+	http.HandleFunc("/api/schema/validate", schemaValidateHandler)
+	http.HandleFunc("/api/schema/infer", schemaInferHandler)
+	http.HandleFunc("/api/analyze", analyzeJSONHandler)
+	http.HandleFunc("/api/benchmark", benchmarkHandler)
+	http.HandleFunc("/api/examples", examplesHandler)
+	http.HandleFunc("/api/convert/yaml-to-json", yamlToJSONHandler)
+	http.HandleFunc("/api/convert/json-to-yaml", jsonToYAMLHandler)
+	http.HandleFunc("/api/convert/xml-to-json", xmlToJSONHandler)
+	http.HandleFunc("/api/convert/json-to-xml", jsonToXMLHandler)
+	http.HandleFunc("/api/convert/csv-to-json", csvToJSONHandler)
+	http.HandleFunc("/api/convert/json-to-csv", jsonToCSVHandler)
+	http.HandleFunc("/api/convert-to-go", convertToGoHandler) // Conversor simplificado
+
+	fmt.Println("🚀 PARSER JSON + CONVERSOR SIMPLIFICADO")
+	fmt.Println("📁 Sirviendo archivos desde: ./static/")
+	fmt.Println("🌐 Accede a: http://localhost:8080")
+	fmt.Println()
+	fmt.Println("⚡ CARACTERÍSTICAS PRINCIPALES:")
+	fmt.Println("   • 🔥 Parser JSON con Expresiones Regulares")
+	fmt.Println("   • ⚡ Detección Rápida de Tipos JSON")
+	fmt.Println("   • 🧠 Parsing Estructural Inteligente")
+	fmt.Println("   • 🛡️ Validación Completa con Regex")
+	fmt.Println("   • 📊 Análisis de Elementos")
+	fmt.Println("   • 🚀 Rendimiento Optimizado")
+	fmt.Println("   • 🎯 Conversor Archivo → Go SIMPLIFICADO")
+	fmt.Println()
+	fmt.Println("🔧 API ENDPOINTS DISPONIBLES:")
+	fmt.Println("   POST /api/parse           - Parsing JSON con regex")
+	fmt.Println("   POST /api/parse?stream=true - Parsing del cuerpo en flujo (documentos grandes)")
+	fmt.Println("   POST /api/parse {preserve_order} - Conserva el orden original de las claves")
+	fmt.Println("   POST /api/validate        - Validación rápida")
+	fmt.Println("   POST /api/format          - Formateo (sangría, orden de claves, ancho en línea)")
+	fmt.Println("   POST /api/minify          - Minificación con ahorro de bytes")
+	fmt.Println("   POST /api/canonicalize    - Forma canónica JCS (RFC 8785) + SHA-256")
+	fmt.Println("   POST /api/pointer         - JSON Pointer (RFC 6901): get, set, delete")
+	fmt.Println("   POST /api/query           - Consultas JSONPath (RFC 9535) con rutas normalizadas")
+	fmt.Println("   POST /api/patch           - Aplicar JSON Patch (RFC 6902)")
+	fmt.Println("   POST /api/diff-patch      - Generar el JSON Patch entre dos documentos")
+	fmt.Println("   POST /api/merge-patch     - JSON Merge Patch (RFC 7386): aplicar o generar")
+	fmt.Println("   POST /api/diff            - Diferencias estructurales por JSON Pointer")
+	fmt.Println("   POST /api/schema/validate - Validación con JSON Schema (draft 2020-12)")
+	fmt.Println("   POST /api/schema/infer    - Inferir un JSON Schema desde documentos de muestra")
+	fmt.Println("   POST /api/analyze         - Análisis completo del JSON")
+	fmt.Println("   POST /api/benchmark       - Comparación de rendimiento")
+	fmt.Println("   POST /api/convert/yaml-to-json - YAML 1.2 (anclas, varios documentos) → JSON")
+	fmt.Println("   POST /api/convert/json-to-yaml - JSON → YAML en estilo de bloque")
+	fmt.Println("   POST /api/convert/xml-to-json  - XML → JSON (attributes, BadgerFish, Parker)")
+	fmt.Println("   POST /api/convert/json-to-xml  - JSON → XML con la misma convención")
+	fmt.Println("   POST /api/convert/csv-to-json  - CSV → array de objetos (columnas a.b y lista[0])")
+	fmt.Println("   POST /api/convert/json-to-csv  - Array de objetos → CSV con columnas aplanadas")
+	fmt.Println("   POST /api/convert-to-go   - 🎯 CONVERSOR SIMPLIFICADO")
+	fmt.Println("   GET  /api/examples        - Ejemplos de prueba")
+	fmt.Println()
+	fmt.Println("🎯 CONVERSOR SIMPLIFICADO:")
+	fmt.Println("   • Solo sube archivo → conversión automática")
+	fmt.Println("   • Package: main (predeterminado)")
+	fmt.Println("   • Variable: textContent (predeterminado)")
+	fmt.Println("   • Tipo: var (predeterminado)")
+	fmt.Println("   • Formatos: .txt, .json, .md, .csv, .xml, .yaml, .yml")
+	fmt.Println()
+	fmt.Println("⏹️  Presiona Ctrl+C para detener el servidor")
+	fmt.Println(strings.Repeat("=", 80))
+
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
+
+func parseHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Modo flujo: el cuerpo es el documento JSON y se parsea mientras se lee
+	if r.URL.Query().Get("stream") == "true" {
+		parseStreamHandler(w, r)
+		return
+	}
+
+	var req ParseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "regex_parser")
+		return
+	}
+
+	// No recortar la entrada: las posiciones de error deben coincidir con el texto original
+	if strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El JSON no puede estar vacío", "regex_parser")
+		return
+	}
+
+	opts, err := req.options()
+	if err != nil {
+		respondWithError(w, err.Error(), "regex_parser")
+		return
+	}
+
+	// PARSING CON REGEX - MÁXIMO RENDIMIENTO
+	startTime := time.Now()
+	result, err := globalParser.ParseJSONWithOptions(req.JSON, opts)
+	parseTime := time.Since(startTime)
+
+	// Análisis adicional del JSON
+	jsonType := globalParser.ExtractJSONType(req.JSON)
+	elementCount, _ := globalParser.CountJSONElements(req.JSON)
+
+	if err != nil {
+		response := ParseResponse{
+			Success:      false,
+			Error:        err.Error(),
+			ParseTime:    parseTime.String(),
+			Method:       "regex_parser",
+			Performance:  "error",
+			JSONType:     jsonType,
+			ElementCount: elementCount,
+			ErrorDetails: AsSyntaxError(err),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := ParseResponse{
+		Success:      true,
+		Result:       result,
+		ParseTime:    parseTime.String(),
+		Method:       "regex_parser",
+		Performance:  determinePerformanceLevel(parseTime),
+		JSONType:     jsonType,
+		ElementCount: elementCount,
+	}
+	if req.Format {
+		formatted, err := FormatValue(result, "  ")
+		if err != nil {
+			respondWithError(w, "Error al formatear el resultado: "+err.Error(), "regex_parser")
+			return
+		}
+		response.Formatted = formatted
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseStreamHandler parsea el cuerpo de la petición directamente desde el
+// flujo, sin cargar el documento completo en un string
+func parseStreamHandler(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	mode, err := ParseNumberMode(r.URL.Query().Get("number_mode"))
+	if err != nil {
+		respondWithError(w, err.Error(), "stream_parser")
+		return
+	}
+
+	decoder := globalParser.NewDecoder(r.Body)
+	if r.URL.Query().Get("preserve_order") == "true" {
+		decoder.PreserveOrder()
+	}
+	decoder.SetNumberMode(mode)
+	if r.URL.Query().Get("lenient_strings") == "true" {
+		decoder.LenientStrings()
+	}
+	result, err := decoder.Decode()
+	if err == io.EOF {
+		respondWithError(w, "El JSON no puede estar vacío", "stream_parser")
+		return
+	}
+	if err == nil {
+		err = decoder.expectEOF()
+	}
+	parseTime := time.Since(startTime)
+
+	if err != nil {
+		response := ParseResponse{
+			Success:      false,
+			Error:        err.Error(),
+			ParseTime:    parseTime.String(),
+			Method:       "stream_parser",
+			Performance:  "error",
+			ErrorDetails: AsSyntaxError(err),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := ParseResponse{
+		Success:      true,
+		Result:       result,
+		ParseTime:    parseTime.String(),
+		Method:       "stream_parser",
+		Performance:  determinePerformanceLevel(parseTime),
+		JSONType:     JSONTypeOf(result),
+		ElementCount: CountValueElements(result),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// formatHandler reindenta el JSON conservando el orden de claves y los
+// números exactos (no pasa por JSON.stringify ni por float64)
+func formatHandler(w http.ResponseWriter, r *http.Request) {
+	serializeHandler(w, r, "formatter")
+}
+
+// minifyHandler elimina todo espacio innecesario del JSON
+func minifyHandler(w http.ResponseWriter, r *http.Request) {
+	serializeHandler(w, r, "minifier")
+}
+
+// serializeHandler lógica común de /api/format y /api/minify
+func serializeHandler(w http.ResponseWriter, r *http.Request, method string) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req FormatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), method)
+		return
+	}
+
+	if strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El JSON no puede estar vacío", method)
+		return
+	}
+
+	opts := EncodeOptions{SortKeys: req.SortKeys, ASCIIOnly: req.ASCIIOnly}
+	if method == "formatter" {
+		width := req.IndentWidth
+		if width == 0 {
+			width = 2
+		}
+		if width < 0 || width > maxIndentWidth {
+			respondWithError(w, fmt.Sprintf("indent_width debe estar entre 1 y %d", maxIndentWidth), method)
+			return
+		}
+		if req.MaxInlineWidth < 0 {
+			respondWithError(w, "max_inline_width no puede ser negativo", method)
+			return
+		}
+
+		unit := " "
+		if req.UseTabs {
+			unit = "\t"
+		}
+		opts.Indent = strings.Repeat(unit, width)
+		opts.MaxInlineWidth = req.MaxInlineWidth
+	}
+
+	startTime := time.Now()
+	value, err := globalParser.ParseJSONWithOptions(req.JSON, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	var output []byte
+	if err == nil {
+		output, err = MarshalWithOptions(value, opts)
+	}
+	processTime := time.Since(startTime)
+
+	response := FormatResponse{
+		Success:       err == nil,
+		Method:        method,
+		ProcessTime:   processTime.String(),
+		OriginalBytes: len(req.JSON),
+	}
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+	} else {
+		response.Output = string(output)
+		response.OutputBytes = len(output)
+		response.BytesSaved = response.OriginalBytes - response.OutputBytes
+		response.SavingsPercent = fmt.Sprintf("%.1f%%", float64(response.BytesSaved)/float64(response.OriginalBytes)*100)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// canonicalizeHandler devuelve la forma canónica JCS (RFC 8785) del JSON y
+// su SHA-256, útil para firmar o comparar documentos
+func canonicalizeHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ParseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "jcs")
+		return
+	}
+
+	if strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El JSON no puede estar vacío", "jcs")
+		return
+	}
+
+	startTime := time.Now()
+	// Number conserva el literal hasta la conversión a double que exige JCS
+	value, err := globalParser.ParseJSONWithOptions(req.JSON, ParseOptions{NumberMode: NumberAsNumber})
+	var canonical []byte
+	var digest string
+	if err == nil {
+		canonical, digest, err = CanonicalDigest(value)
+	}
+	processTime := time.Since(startTime)
+
+	response := CanonicalizeResponse{
+		Success:     err == nil,
+		Method:      "jcs",
+		ProcessTime: processTime.String(),
+	}
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+	} else {
+		response.Canonical = string(canonical)
+		response.SHA256 = digest
+		response.Bytes = len(canonical)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// pointerHandler resuelve, asigna o elimina un JSON Pointer sobre el documento
+func pointerHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req PointerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "json_pointer")
+		return
+	}
+
+	if strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El JSON no puede estar vacío", "json_pointer")
+		return
+	}
+
+	if req.Op == "" {
+		req.Op = "get"
+	}
+	response := PointerResponse{Op: req.Op, Pointer: req.Pointer, Method: "json_pointer"}
+	fail := func(err error) {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+		response.PointerError = AsPointerError(err)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+
+	// Orden de claves y números exactos para devolver el documento sin alterarlo
+	opts := ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber}
+	doc, err := globalParser.ParseJSONWithOptions(req.JSON, opts)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	pointer, err := ParsePointer(req.Pointer)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	switch req.Op {
+	case "get":
+		value, err := pointer.Get(doc)
+		if err != nil {
+			fail(err)
+			return
+		}
+		formatted, err := FormatValue(value, "  ")
+		if err != nil {
+			fail(err)
+			return
+		}
+		response.Value = value
+		response.ValueType = JSONTypeOf(value)
+		response.Formatted = formatted
+	case "set", "delete":
+		var result interface{}
+		if req.Op == "set" {
+			value, err := globalParser.ParseJSONWithOptions(req.Value, opts)
+			if err != nil {
+				fail(fmt.Errorf("valor a asignar inválido: %w", err))
+				return
+			}
+			result, err = pointer.Set(doc, value)
+		} else {
+			result, err = pointer.Delete(doc)
+		}
+		if err != nil {
+			fail(err)
+			return
+		}
+		formatted, err := FormatValue(result, "  ")
+		if err != nil {
+			fail(err)
+			return
+		}
+		response.Document = formatted
+	default:
+		fail(fmt.Errorf("operación desconocida: %s (use get, set o delete)", req.Op))
+		return
+	}
+
+	response.Success = true
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// queryHandler evalúa una consulta JSONPath y devuelve cada nodo con su ruta normalizada
+func queryHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req QueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "jsonpath")
+		return
+	}
+
+	if strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El JSON no puede estar vacío", "jsonpath")
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		respondWithError(w, "La consulta no puede estar vacía", "jsonpath")
+		return
+	}
+
+	// El parser es estricto con los espacios; los del campo del formulario se
+	// recortan aquí para que las posiciones de error sigan siendo válidas
+	req.Query = strings.TrimSpace(req.Query)
+	response := QueryResponse{Query: req.Query, Results: []JSONPathResult{}, Method: "jsonpath"}
+
+	// La consulta se compila antes de parsear para reportar primero los errores de sintaxis
+	query, err := CompileJSONPath(req.Query)
+	if err == nil {
+		var doc interface{}
+		doc, err = globalParser.ParseJSONWithOptions(req.JSON, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+		if err == nil {
+			response.Success = true
+			response.Results = query.Evaluate(doc)
+			response.Count = len(response.Results)
+		}
+	}
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+		response.QueryError = AsJSONPathError(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// patchHandler aplica un JSON Patch de forma atómica: si una operación falla
+// se informa cuál y el documento no se modifica
+func patchHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req PatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "json_patch")
+		return
+	}
+
+	if strings.TrimSpace(req.JSON) == "" || strings.TrimSpace(req.Patch) == "" {
+		respondWithError(w, "El JSON y el patch no pueden estar vacíos", "json_patch")
+		return
+	}
+
+	response := PatchResponse{Method: "json_patch"}
+	fail := func(err error) {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+		response.PatchError = AsPatchError(err)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+
+	opts := ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber}
+	doc, err := globalParser.ParseJSONWithOptions(req.JSON, opts)
+	if err != nil {
+		fail(err)
+		return
+	}
+	patchValue, err := globalParser.ParseJSONWithOptions(req.Patch, opts)
+	if err != nil {
+		fail(fmt.Errorf("patch inválido: %w", err))
+		return
+	}
+	patch, err := ParsePatch(patchValue)
+	if err != nil {
+		fail(err)
+		return
+	}
+	response.Operations = len(patch)
+
+	result, err := ApplyPatch(doc, patch)
+	if err != nil {
+		fail(err)
+		return
+	}
+	if response.Document, err = FormatValue(result, "  "); err != nil {
+		fail(err)
+		return
+	}
+	response.Success = true
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// diffPatchHandler genera el JSON Patch que lleva de original a modified
+func diffPatchHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req DiffPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "json_patch_diff")
+		return
+	}
+
+	if strings.TrimSpace(req.Original) == "" || strings.TrimSpace(req.Modified) == "" {
+		respondWithError(w, "Los documentos original y modificado no pueden estar vacíos", "json_patch_diff")
+		return
+	}
+
+	response := DiffPatchResponse{Method: "json_patch_diff"}
+	opts := ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber}
+	original, err := globalParser.ParseJSONWithOptions(req.Original, opts)
+	if err != nil {
+		err = fmt.Errorf("documento original: %w", err)
+	}
+	var modified interface{}
+	if err == nil {
+		if modified, err = globalParser.ParseJSONWithOptions(req.Modified, opts); err != nil {
+			err = fmt.Errorf("documento modificado: %w", err)
+		}
+	}
+	if err == nil {
+		patch := Diff(original, modified)
+		response.Operations = len(patch)
+		response.Patch, err = FormatValue(PatchValue(patch), "  ")
+	}
+
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+	} else {
+		response.Success = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// mergePatchHandler aplica un merge patch sobre el documento (op "apply") o
+// genera el merge patch entre json y modified (op "create")
+func mergePatchHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req MergePatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "merge_patch")
+		return
+	}
+
+	if strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El JSON no puede estar vacío", "merge_patch")
+		return
+	}
+
+	if req.Op == "" {
+		req.Op = "apply"
+	}
+	response := MergePatchResponse{Op: req.Op, Method: "merge_patch"}
+
+	opts := ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber}
+	doc, err := globalParser.ParseJSONWithOptions(req.JSON, opts)
+	if err == nil {
+		switch req.Op {
+		case "apply":
+			var patch interface{}
+			if patch, err = globalParser.ParseJSONWithOptions(req.Patch, opts); err != nil {
+				err = fmt.Errorf("merge patch inválido: %w", err)
+				break
+			}
+			response.Document, err = FormatValue(MergePatch(doc, patch), "  ")
+		case "create":
+			var modified, patch interface{}
+			if modified, err = globalParser.ParseJSONWithOptions(req.Modified, opts); err != nil {
+				err = fmt.Errorf("documento modificado: %w", err)
+				break
+			}
+			if patch, err = CreateMergePatch(doc, modified); err == nil {
+				response.Patch, err = FormatValue(patch, "  ")
+			}
+		default:
+			err = fmt.Errorf("operación desconocida '%s' (use apply o create)", req.Op)
+		}
+	}
+
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+	} else {
+		response.Success = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// diffHandler compara dos documentos y lista lo agregado, eliminado y
+// modificado, cada entrada identificada por su JSON Pointer
+func diffHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req DiffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "structural_diff")
+		return
+	}
+
+	if strings.TrimSpace(req.Original) == "" || strings.TrimSpace(req.Modified) == "" {
+		respondWithError(w, "Los documentos original y modificado no pueden estar vacíos", "structural_diff")
+		return
+	}
+
+	response := DiffResponse{Entries: []DiffEntry{}, Method: "structural_diff"}
+	fail := func(err error) {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+		response.PointerError = AsPointerError(err)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+
+	opts := DiffOptions{IgnoreArrayOrder: req.IgnoreArrayOrder}
+	for _, path := range req.IgnorePaths {
+		if strings.TrimSpace(path) == "" {
+			continue
+		}
+		pointer, err := ParsePointer(strings.TrimSpace(path))
+		if err != nil {
+			fail(err)
+			return
+		}
+		opts.IgnorePaths = append(opts.IgnorePaths, pointer)
+	}
+
+	parseOpts := ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber}
+	original, err := globalParser.ParseJSONWithOptions(req.Original, parseOpts)
+	if err != nil {
+		fail(fmt.Errorf("documento original: %w", err))
+		return
+	}
+	modified, err := globalParser.ParseJSONWithOptions(req.Modified, parseOpts)
+	if err != nil {
+		fail(fmt.Errorf("documento modificado: %w", err))
+		return
+	}
+
+	response.Entries = CompareValues(original, modified, opts)
+	response.Summary = map[string]int{DiffAdded: 0, DiffRemoved: 0, DiffChanged: 0}
+	for _, entry := range response.Entries {
+		response.Summary[entry.Kind]++
+	}
+	response.Equal = len(response.Entries) == 0
+	if response.Original, err = FormatValue(original, "  "); err == nil {
+		response.Modified, err = FormatValue(modified, "  ")
+	}
+	if err != nil {
+		fail(err)
+		return
+	}
+	response.Success = true
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// schemaValidateHandler valida una instancia contra un JSON Schema y devuelve
+// todas las violaciones con su ruta en la instancia y en el esquema
+func schemaValidateHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SchemaValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "json_schema")
+		return
+	}
+
+	if strings.TrimSpace(req.Schema) == "" || strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El esquema y el JSON no pueden estar vacíos", "json_schema")
+		return
+	}
+
+	response := SchemaValidateResponse{Errors: []SchemaViolation{}, Method: "json_schema"}
+
+	opts := ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber}
+	var schema *Schema
+	schemaValue, err := globalParser.ParseJSONWithOptions(req.Schema, opts)
+	if err != nil {
+		err = fmt.Errorf("esquema: %w", err)
+	} else {
+		schema, err = CompileSchema(schemaValue)
+	}
+	if err == nil {
+		var instance interface{}
+		if instance, err = globalParser.ParseJSONWithOptions(req.JSON, opts); err == nil {
+			response.Success = true
+			response.Errors = schema.Validate(instance)
+			response.ErrorCount = len(response.Errors)
+			response.Valid = response.ErrorCount == 0
+		}
+	}
+
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+		response.SchemaError = AsSchemaError(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// schemaInferHandler propone un JSON Schema que acepta todas las muestras
+func schemaInferHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SchemaInferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "schema_inference")
+		return
+	}
+
+	sources := req.Samples
+	if strings.TrimSpace(req.JSON) != "" {
+		sources = append(sources, req.JSON)
+	}
+	if len(sources) == 0 {
+		respondWithError(w, "Se necesita al menos una muestra", "schema_inference")
+		return
+	}
+
+	response := SchemaInferResponse{Samples: len(sources), Method: "schema_inference"}
+	samples := make([]interface{}, len(sources))
+	var err error
+	for i, source := range sources {
+		samples[i], err = globalParser.ParseJSONWithOptions(source, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+		if err != nil {
+			err = fmt.Errorf("muestra %d: %w", i+1, err)
+			break
+		}
+	}
+	if err == nil {
+		response.Schema, err = FormatValue(InferSchema(samples, InferOptions{MaxEnumValues: req.MaxEnumValues}), "  ")
+	}
+
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+	} else {
+		response.Success = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// yamlToJSONHandler convierte YAML 1.2 a JSON conservando el orden de las
+// claves y los números exactos; un flujo con varios documentos se devuelve
+// como un array con un elemento por documento
+func yamlToJSONHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req YAMLToJSONRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "yaml_to_json")
+		return
+	}
+
+	width := req.IndentWidth
+	if width == 0 {
+		width = 2
+	}
+	if width < 0 || width > maxIndentWidth {
+		respondWithError(w, fmt.Sprintf("indent_width debe estar entre 1 y %d", maxIndentWidth), "yaml_to_json")
+		return
+	}
+
+	startTime := time.Now()
+	docs, err := globalParser.ParseYAMLDocuments(req.YAML, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	var output string
+	if err == nil {
+		var value interface{}
+		switch len(docs) {
+		case 0:
+		case 1:
+			value = docs[0]
+		default:
+			value = docs
+		}
+		output, err = FormatValue(value, strings.Repeat(" ", width))
+	}
+
+	response := YAMLConvertResponse{Success: err == nil, Method: "yaml_to_json", Documents: len(docs), ProcessTime: time.Since(startTime).String()}
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+	} else {
+		response.Output = output
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// jsonToYAMLHandler convierte JSON a YAML en estilo de bloque; con
+// multi_document cada elemento del array raíz se escribe como un documento
+func jsonToYAMLHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req JSONToYAMLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "json_to_yaml")
+		return
+	}
+
+	if strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El JSON no puede estar vacío", "json_to_yaml")
+		return
+	}
+
+	startTime := time.Now()
+	response := YAMLConvertResponse{Method: "json_to_yaml", Documents: 1}
+	value, err := globalParser.ParseJSONWithOptions(req.JSON, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	if err == nil {
+		if items, ok := value.([]interface{}); ok && req.MultiDocument {
+			response.Documents = len(items)
+			response.Output, err = MarshalYAMLDocuments(items)
+		} else if req.MultiDocument {
+			err = fmt.Errorf("multi_document requiere un array en la raíz, se recibió %s", JSONTypeOf(value))
+		} else {
+			response.Output, err = MarshalYAML(value)
+		}
+	}
+	response.ProcessTime = time.Since(startTime).String()
+
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+		response.Documents = 0
+	} else {
+		response.Success = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// xmlToJSONHandler convierte XML a JSON con la convención de mapeo pedida
+func xmlToJSONHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req XMLToJSONRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "xml_to_json")
+		return
+	}
+
+	width := req.IndentWidth
+	if width == 0 {
+		width = 2
+	}
+	if width < 0 || width > maxIndentWidth {
+		respondWithError(w, fmt.Sprintf("indent_width debe estar entre 1 y %d", maxIndentWidth), "xml_to_json")
+		return
+	}
+
+	opts := XMLOptions{Convention: req.Convention, AttributePrefix: req.AttributePrefix, TextKey: req.TextKey}
+	startTime := time.Now()
+	value, err := globalParser.ParseXML(req.XML, opts, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	var output string
+	if err == nil {
+		output, err = FormatValue(value, strings.Repeat(" ", width))
+	}
+
+	response := XMLConvertResponse{Success: err == nil, Method: "xml_to_json", Convention: opts.withDefaults().Convention, ProcessTime: time.Since(startTime).String()}
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+	} else {
+		response.Output = output
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
-// Parser global para reutilizar regex compiladas (máximo rendimiento)
-var globalParser = NewParser()
+// jsonToXMLHandler convierte JSON a XML con la convención de mapeo pedida
+func jsonToXMLHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
 
-func main() {
-	// Verificar que el parser está funcionando correctamente
-	fmt.Println("🔥 Inicializando Parser JSON con Expresiones Regulares...")
-	testResult, testErr := globalParser.ParseJSON(`{"test": "working"}`)
-	if testErr != nil {
-		log.Fatalf("❌ Error inicializando parser: %v", testErr)
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
 	}
-	fmt.Printf("✅ Parser inicializado correctamente: %+v\n", testResult)
 
-	// Servir archivos estáticos
-	http.Handle("/", http.FileServer(http.Dir("./static/")))
+	var req JSONToXMLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "json_to_xml")
+		return
+	}
 
-	// API endpoints optimizados
-	http.HandleFunc("/api/parse", parseHandler)
-	http.HandleFunc("/api/validate", validateHandler)
-	http.HandleFunc("/api/analyze", analyzeJSONHandler)
-	http.HandleFunc("/api/benchmark", benchmarkHandler)
-	http.HandleFunc("/api/examples", examplesHandler)
-	http.HandleFunc("/api/convert-to-go", convertToGoHandler) // Conversor simplificado
+	if strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El JSON no puede estar vacío", "json_to_xml")
+		return
+	}
 
-	fmt.Println("🚀 PARSER JSON + CONVERSOR SIMPLIFICADO")
-	fmt.Println("📁 Sirviendo archivos desde: ./static/")
-	fmt.Println("🌐 Accede a: http://localhost:8080")
-	fmt.Println()
-	fmt.Println("⚡ CARACTERÍSTICAS PRINCIPALES:")
-	fmt.Println("   • 🔥 Parser JSON con Expresiones Regulares")
-	fmt.Println("   • ⚡ Detección Rápida de Tipos JSON")
-	fmt.Println("   • 🧠 Parsing Estructural Inteligente")
-	fmt.Println("   • 🛡️ Validación Completa con Regex")
-	fmt.Println("   • 📊 Análisis de Elementos")
-	fmt.Println("   • 🚀 Rendimiento Optimizado")
-	fmt.Println("   • 🎯 Conversor Archivo → Go SIMPLIFICADO")
-	fmt.Println()
-	fmt.Println("🔧 API ENDPOINTS DISPONIBLES:")
-	fmt.Println("   POST /api/parse           - Parsing JSON con regex")
-	fmt.Println("   POST /api/validate        - Validación rápida")
-	fmt.Println("   POST /api/analyze         - Análisis completo del JSON")
-	fmt.Println("   POST /api/benchmark       - Comparación de rendimiento")
-	fmt.Println("   POST /api/convert-to-go   - 🎯 CONVERSOR SIMPLIFICADO")
-	fmt.Println("   GET  /api/examples        - Ejemplos de prueba")
-	fmt.Println()
-	fmt.Println("🎯 CONVERSOR SIMPLIFICADO:")
-	fmt.Println("   • Solo sube archivo → conversión automática")
-	fmt.Println("   • Package: main (predeterminado)")
-	fmt.Println("   • Variable: textContent (predeterminado)")
-	fmt.Println("   • Tipo: var (predeterminado)")
-	fmt.Println("   • Formatos: .txt, .json, .md, .csv, .xml, .yaml, .yml")
-	fmt.Println()
-	fmt.Println("⏹️  Presiona Ctrl+C para detener el servidor")
-	fmt.Println(strings.Repeat("=", 80))
+	opts := XMLOptions{Convention: req.Convention, AttributePrefix: req.AttributePrefix, TextKey: req.TextKey, RootName: req.RootName}
+	startTime := time.Now()
+	value, err := globalParser.ParseJSONWithOptions(req.JSON, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	var output string
+	if err == nil {
+		output, err = MarshalXML(value, opts)
+	}
 
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	response := XMLConvertResponse{Success: err == nil, Method: "json_to_xml", Convention: opts.withDefaults().Convention, ProcessTime: time.Since(startTime).String()}
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+	} else {
+		response.Output = output
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
-func parseHandler(w http.ResponseWriter, r *http.Request) {
+// csvToJSONHandler convierte un CSV con encabezado en un array de objetos,
+// reconstruyendo el anidamiento de las columnas "a.b" y "lista[0]"
+func csvToJSONHandler(w http.ResponseWriter, r *http.Request) {
 	setupCORS(w)
 	if r.Method == "OPTIONS" {
 		return
@@ -92,51 +1413,100 @@ func parseHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req ParseRequest
+	var req CSVToJSONRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "regex_parser")
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "csv_to_json")
 		return
 	}
 
-	req.JSON = strings.TrimSpace(req.JSON)
-	if req.JSON == "" {
-		respondWithError(w, "El JSON no puede estar vacío", "regex_parser")
+	width := req.IndentWidth
+	if width == 0 {
+		width = 2
+	}
+	if width < 0 || width > maxIndentWidth {
+		respondWithError(w, fmt.Sprintf("indent_width debe estar entre 1 y %d", maxIndentWidth), "csv_to_json")
+		return
+	}
+	opts, err := csvOptionsFromStrings(req.Delimiter, req.Quote)
+	if err != nil {
+		respondWithError(w, err.Error(), "csv_to_json")
 		return
 	}
 
-	// PARSING CON REGEX - MÁXIMO RENDIMIENTO
 	startTime := time.Now()
-	result, err := globalParser.ParseJSON(req.JSON)
-	parseTime := time.Since(startTime)
+	response := CSVConvertResponse{Method: "csv_to_json"}
+	records, err := ReadCSV(req.CSV, opts)
+	var rows []interface{}
+	if err == nil {
+		rows, err = globalParser.UnflattenCSVRows(records, req.KeepStrings)
+	}
+	if err == nil {
+		response.Output, err = FormatValue(rows, strings.Repeat(" ", width))
+		response.Rows, response.Columns = len(rows), records[0]
+	}
+	response.ProcessTime = time.Since(startTime).String()
 
-	// Análisis adicional del JSON
-	jsonType := globalParser.ExtractJSONType(req.JSON)
-	elementCount, _ := globalParser.CountJSONElements(req.JSON)
+	if err != nil {
+		response.Error = err.Error()
+		response.CSVError = AsCSVError(err)
+	} else {
+		response.Success = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// jsonToCSVHandler convierte un array de objetos en CSV, aplanando los
+// objetos anidados en columnas "a.b" y los arrays en "lista[0]"
+func jsonToCSVHandler(w http.ResponseWriter, r *http.Request) {
+	setupCORS(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
 
+	if r.Method != "POST" {
+		http.Error(w, "Método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req JSONToCSVRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, "Error al decodificar la solicitud: "+err.Error(), "json_to_csv")
+		return
+	}
+
+	if strings.TrimSpace(req.JSON) == "" {
+		respondWithError(w, "El JSON no puede estar vacío", "json_to_csv")
+		return
+	}
+	opts, err := csvOptionsFromStrings(req.Delimiter, req.Quote)
 	if err != nil {
-		response := ParseResponse{
-			Success:      false,
-			Error:        err.Error(),
-			ParseTime:    parseTime.String(),
-			Method:       "regex_parser",
-			Performance:  "error",
-			JSONType:     jsonType,
-			ElementCount: elementCount,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
+		respondWithError(w, err.Error(), "json_to_csv")
 		return
 	}
 
-	response := ParseResponse{
-		Success:      true,
-		Result:       result,
-		ParseTime:    parseTime.String(),
-		Method:       "regex_parser",
-		Performance:  determinePerformanceLevel(parseTime),
-		JSONType:     jsonType,
-		ElementCount: elementCount,
+	startTime := time.Now()
+	response := CSVConvertResponse{Method: "json_to_csv"}
+	value, err := globalParser.ParseJSONWithOptions(req.JSON, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	var header []string
+	var rows [][]string
+	if err == nil {
+		header, rows, err = FlattenJSONRows(value)
 	}
+	if err == nil {
+		response.Output, err = WriteCSV(append([][]string{header}, rows...), opts)
+		response.Rows, response.Columns = len(rows), header
+	}
+	response.ProcessTime = time.Since(startTime).String()
+
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorDetails = AsSyntaxError(err)
+	} else {
+		response.Success = true
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -158,8 +1528,8 @@ func validateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req.JSON = strings.TrimSpace(req.JSON)
-	if req.JSON == "" {
+	// No recortar la entrada: las posiciones de error deben coincidir con el texto original
+	if strings.TrimSpace(req.JSON) == "" {
 		respondWithError(w, "El JSON no puede estar vacío", "regex_validator")
 		return
 	}
@@ -173,12 +1543,13 @@ func validateHandler(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		response := ParseResponse{
-			Success:     false,
-			Error:       err.Error(),
-			ParseTime:   validateTime.String(),
-			Method:      "regex_validator",
-			Performance: "validation_error",
-			JSONType:    jsonType,
+			Success:      false,
+			Error:        err.Error(),
+			ParseTime:    validateTime.String(),
+			Method:       "regex_validator",
+			Performance:  "validation_error",
+			JSONType:     jsonType,
+			ErrorDetails: AsSyntaxError(err),
 		}
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(response)
@@ -214,8 +1585,8 @@ func analyzeJSONHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req.JSON = strings.TrimSpace(req.JSON)
-	if req.JSON == "" {
+	// No recortar la entrada: las posiciones de error deben coincidir con el texto original
+	if strings.TrimSpace(req.JSON) == "" {
 		respondWithError(w, "El JSON no puede estar vacío", "regex_analyzer")
 		return
 	}
@@ -297,18 +1668,24 @@ func benchmarkHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req.JSON = strings.TrimSpace(req.JSON)
-	if req.JSON == "" {
+	// No recortar la entrada: las posiciones de error deben coincidir con el texto original
+	if strings.TrimSpace(req.JSON) == "" {
 		respondWithError(w, "El JSON no puede estar vacío", "benchmark")
 		return
 	}
 
+	opts, err := req.options()
+	if err != nil {
+		respondWithError(w, err.Error(), "benchmark")
+		return
+	}
+
 	// BENCHMARK COMPREHENSIVO
 	results := make(map[string]interface{})
 
 	// 1. Parser con Regex
 	startTime := time.Now()
-	regexResult, regexErr := globalParser.ParseJSON(req.JSON)
+	regexResult, regexErr := globalParser.ParseJSONWithOptions(req.JSON, opts)
 	regexTime := time.Since(startTime)
 
 	// 2. Validación rápida
@@ -319,7 +1696,18 @@ func benchmarkHandler(w http.ResponseWriter, r *http.Request) {
 	// 3. Parser nativo de Go (para comparación)
 	startTime = time.Now()
 	var nativeResult interface{}
-	nativeErr := json.Unmarshal([]byte(req.JSON), &nativeResult)
+	nativeDecoder := json.NewDecoder(strings.NewReader(req.JSON))
+	if opts.NumberMode != NumberAsFloat64 {
+		// Sin redondeo a float64 para comparar contra los números exactos
+		nativeDecoder.UseNumber()
+	}
+	nativeErr := nativeDecoder.Decode(&nativeResult)
+	if nativeErr == nil {
+		// Igual que json.Unmarshal: no se admite contenido después del valor
+		if _, err := nativeDecoder.Token(); err != io.EOF {
+			nativeErr = fmt.Errorf("contenido extra después del valor JSON")
+		}
+	}
 	nativeTime := time.Since(startTime)
 
 	// 4. Análisis completo
@@ -553,16 +1941,96 @@ func convertToGoHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// CONFIGURACIÓN AUTOMÁTICA PREDETERMINADA
+	// CONFIGURACIÓN AUTOMÁTICA PREDETERMINADA, ajustable con los campos
+	// opcionales package, identifier y mode
 	packageName := "main"
 	variableName := "textContent"
 	conversionType := "variable"
+	autoGenerated := true
+	if value := r.FormValue("package"); value != "" {
+		packageName, autoGenerated = value, false
+	}
+	if value := r.FormValue("identifier"); value != "" {
+		variableName, autoGenerated = value, false
+	}
+	if value := r.FormValue("mode"); value != "" {
+		conversionType, autoGenerated = value, false
+	} else if strings.HasSuffix(fileName, ".json") {
+		// Los .json se convierten por defecto en un literal Go nativo
+		conversionType = "json"
+	}
+	if err := validateConversionParams(packageName, variableName, conversionType); err != nil {
+		respondWithError(w, err.Error(), "simplified_converter")
+		return
+	}
+	var csvOpts CSVOptions
+	if conversionType == "csv" {
+		if csvOpts, err = csvOptionsFromForm(r); err != nil {
+			respondWithError(w, err.Error(), "simplified_converter")
+			return
+		}
+	}
+	xmlOpts := XMLOptions{Convention: r.FormValue("xml_convention")}
+	if err := xmlOpts.validate(); err != nil {
+		respondWithError(w, err.Error(), "simplified_converter")
+		return
+	}
 
 	// Convertir a código Go
 	startTime := time.Now()
-	goCode := convertTextToGo(string(content), packageName, variableName, conversionType, header.Filename)
+	var goCode string
+	if conversionType == "structs" {
+		// Tipos Go que describen el JSON del archivo
+		goCode, err = convertJSONToGoStructs(string(content), packageName, variableName, header.Filename)
+		if err != nil {
+			respondWithError(w, "Error al generar los structs: "+err.Error(), "simplified_converter")
+			return
+		}
+	} else if conversionType == "json" || conversionType == "json-typed" {
+		// Literal Go con el contenido del JSON, dinámico o de tipos generados
+		goCode, err = convertJSONToGoLiteral(string(content), packageName, variableName, header.Filename, conversionType == "json-typed")
+		if err != nil {
+			respondWithError(w, "Error al convertir el JSON: "+err.Error(), "simplified_converter")
+			return
+		}
+	} else if conversionType == "xml" || conversionType == "xml-typed" {
+		// Literal Go con el árbol del XML según la convención elegida
+		goCode, err = convertXMLToGoLiteral(string(content), packageName, variableName, header.Filename, conversionType == "xml-typed", xmlOpts)
+		if err != nil {
+			respondWithError(w, "Error al convertir el XML: "+err.Error(), "simplified_converter")
+			return
+		}
+	} else if conversionType == "csv" {
+		// Struct por fila y slice literal con los registros
+		goCode, err = convertCSVToGo(string(content), packageName, variableName, header.Filename, csvOpts)
+		if err != nil {
+			respondWithError(w, "Error al convertir el CSV: "+err.Error(), "simplified_converter")
+			return
+		}
+	} else {
+		goCode = convertTextToGo(string(content), packageName, variableName, conversionType, header.Filename)
+	}
+	// Verificar que el código generado compile y darle formato gofmt
+	downloadFilename := generateGoFilename(header.Filename, conversionType)
+	goCode, diagnostics, err := formatGoSource(goCode, downloadFilename)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":     false,
+			"method":      "simplified_converter",
+			"error":       "El código generado no es Go válido: " + err.Error(),
+			"diagnostics": diagnostics,
+			"go_code":     goCode,
+		})
+		return
+	}
 	conversionTime := time.Since(startTime)
 
+	message := "Archivo convertido automáticamente con configuración predeterminada"
+	if !autoGenerated {
+		message = "Archivo convertido con la configuración indicada"
+	}
+
 	// Responder con el código Go generado
 	response := map[string]interface{}{
 		"success":         true,
@@ -571,14 +2039,15 @@ func convertToGoHandler(w http.ResponseWriter, r *http.Request) {
 		"file_size":       header.Size,
 		"conversion_time": conversionTime.String(),
 		"go_code":         goCode,
+		"formatted":       true,
 		"parameters": map[string]interface{}{
 			"package_name":    packageName,
 			"variable_name":   variableName,
 			"conversion_type": conversionType,
-			"auto_generated":  true,
+			"auto_generated":  autoGenerated,
 		},
-		"download_filename": generateGoFilename(header.Filename, conversionType),
-		"message":           "Archivo convertido automáticamente con configuración predeterminada",
+		"download_filename": downloadFilename,
+		"message":           message,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -667,35 +2136,102 @@ func determineComplexity(elementCount map[string]int) string {
 	}
 }
 
+// conversionModes modos aceptados por /api/convert-to-go
+var conversionModes = []string{"variable", "const", "function", "struct", "slice", "map", "structs", "json", "json-typed", "csv", "xml", "xml-typed"}
+
+// validateConversionParams verifica que el paquete y el identificador sean
+// identificadores Go utilizables y que el modo exista
+func validateConversionParams(packageName, identifier, mode string) error {
+	if err := validateGoIdentifier(packageName, ""); err != nil {
+		return fmt.Errorf("nombre de paquete inválido '%s': %w", packageName, err)
+	}
+	if err := validateGoIdentifier(identifier, packageName); err != nil {
+		return fmt.Errorf("identificador inválido '%s': %w", identifier, err)
+	}
+	for _, known := range conversionModes {
+		if mode == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("modo de conversión no soportado '%s' (modos: %s)", mode, strings.Join(conversionModes, ", "))
+}
+
+// csvOptionsFromForm lee el dialecto CSV de los campos delimiter y quote
+func csvOptionsFromForm(r *http.Request) (CSVOptions, error) {
+	return csvOptionsFromStrings(r.FormValue("delimiter"), r.FormValue("quote"))
+}
+
+// csvOptionsFromStrings interpreta el separador y la comilla de una petición
+func csvOptionsFromStrings(delimiterValue, quoteValue string) (CSVOptions, error) {
+	delimiter, err := ParseCSVChar(delimiterValue)
+	if err != nil {
+		return CSVOptions{}, fmt.Errorf("separador inválido: %w", err)
+	}
+	quote, err := ParseCSVChar(quoteValue)
+	if err != nil {
+		return CSVOptions{}, fmt.Errorf("comilla inválida: %w", err)
+	}
+	opts := CSVOptions{Delimiter: delimiter, Quote: quote}
+	return opts, opts.validate()
+}
+
+// validateGoIdentifier explica por qué name no sirve como identificador Go.
+// Si packageName no está vacío, name se declara en ese paquete y tampoco
+// puede ser init ni (en el paquete main) main, que solo pueden ser funciones
+func validateGoIdentifier(name, packageName string) error {
+	switch {
+	case gotoken.IsKeyword(name):
+		return fmt.Errorf("es una palabra reservada de Go")
+	case name == "_":
+		return fmt.Errorf("el identificador vacío '_' no se puede usar")
+	case !gotoken.IsIdentifier(name):
+		return fmt.Errorf("debe empezar con una letra o '_' y contener solo letras, dígitos y '_'")
+	case packageName != "" && name == "init":
+		return fmt.Errorf("'init' está reservado para las funciones de inicialización")
+	case packageName == "main" && name == "main":
+		return fmt.Errorf("'main' está reservado para la función principal del paquete main")
+	}
+	return nil
+}
+
 func convertTextToGo(content, packageName, variableName, conversionType, originalFilename string) string {
 	var builder strings.Builder
 
+	// El paquete, el identificador y el modo llegan ya validados desde el
+	// formulario; sin modo se usa 'variable'
+	if conversionType == "" {
+		conversionType = "variable" // Valor por defecto
+	}
+
 	// Header del archivo Go
 	builder.WriteString(fmt.Sprintf("package %s\n\n", packageName))
-	builder.WriteString(fmt.Sprintf("// Archivo generado automáticamente desde: %s\n", originalFilename))
+	builder.WriteString(fmt.Sprintf("// Archivo generado automáticamente desde: %s\n", goCommentText(originalFilename)))
 	builder.WriteString(fmt.Sprintf("// Generado el: %s\n", time.Now().Format("2006-01-02 15:04:05")))
-	builder.WriteString("// Conversión automática con configuración predeterminada\n\n")
+	builder.WriteString(fmt.Sprintf("// Conversión de texto en modo %s\n\n", conversionType))
 
-	// Para el conversor simplificado, siempre usar 'variable' pero mantener la lógica completa
-	// por si se quiere extender en el futuro
-	if conversionType == "" {
-		conversionType = "variable" // Valor por defecto
-	}
+	// Los literales se eligen según el contenido: raw cuando es seguro,
+	// concatenado o interpretado si hay backticks, \r, NUL o bytes no UTF-8
+	literal := goStringLiteral(content)
 
 	switch conversionType {
 	case "variable":
+		if isBinaryContent(content) {
+			builder.WriteString(fmt.Sprintf("// %s contiene los bytes del archivo (contenido binario)\n", variableName))
+			builder.WriteString(fmt.Sprintf("var %s = %s\n", variableName, goBytesLiteral(content)))
+			break
+		}
 		builder.WriteString(fmt.Sprintf("// %s contiene el contenido del archivo de texto\n", variableName))
-		builder.WriteString(fmt.Sprintf("var %s = `%s`\n", variableName, content))
+		builder.WriteString(fmt.Sprintf("var %s = %s\n", variableName, literal))
 
 	case "const":
 		builder.WriteString(fmt.Sprintf("// %s contiene el contenido del archivo de texto como constante\n", variableName))
-		builder.WriteString(fmt.Sprintf("const %s = `%s`\n", variableName, content))
+		builder.WriteString(fmt.Sprintf("const %s = %s\n", variableName, literal))
 
 	case "function":
 		funcName := capitalizeFirst(variableName)
 		builder.WriteString(fmt.Sprintf("// Get%s retorna el contenido del archivo de texto\n", funcName))
 		builder.WriteString(fmt.Sprintf("func Get%s() string {\n", funcName))
-		builder.WriteString(fmt.Sprintf("\treturn `%s`\n", content))
+		builder.WriteString(fmt.Sprintf("\treturn %s\n", literal))
 		builder.WriteString("}\n")
 
 	case "struct":
@@ -709,8 +2245,8 @@ func convertTextToGo(content, packageName, variableName, conversionType, origina
 		builder.WriteString(fmt.Sprintf("// New%s crea una nueva instancia con el contenido del archivo\n", structName))
 		builder.WriteString(fmt.Sprintf("func New%s() *%s {\n", structName, structName))
 		builder.WriteString(fmt.Sprintf("\treturn &%s{\n", structName))
-		builder.WriteString(fmt.Sprintf("\t\tContent:  `%s`,\n", content))
-		builder.WriteString(fmt.Sprintf("\t\tFilename: \"%s\",\n", originalFilename))
+		builder.WriteString(fmt.Sprintf("\t\tContent:  %s,\n", literal))
+		builder.WriteString(fmt.Sprintf("\t\tFilename: %s,\n", strconv.Quote(originalFilename)))
 		builder.WriteString(fmt.Sprintf("\t\tSize:     %d,\n", len(content)))
 		builder.WriteString("\t}\n")
 		builder.WriteString("}\n")
@@ -720,9 +2256,7 @@ func convertTextToGo(content, packageName, variableName, conversionType, origina
 		builder.WriteString(fmt.Sprintf("// %s contiene las líneas del archivo como slice\n", variableName))
 		builder.WriteString(fmt.Sprintf("var %s = []string{\n", variableName))
 		for _, line := range lines {
-			// Escapar caracteres especiales
-			escapedLine := strings.ReplaceAll(line, "`", "` + \"`\" + `")
-			builder.WriteString(fmt.Sprintf("\t`%s`,\n", escapedLine))
+			builder.WriteString(fmt.Sprintf("\t%s,\n", goStringLiteral(line)))
 		}
 		builder.WriteString("}\n")
 
@@ -731,26 +2265,155 @@ func convertTextToGo(content, packageName, variableName, conversionType, origina
 		builder.WriteString(fmt.Sprintf("// %s contiene las líneas del archivo como map[int]string\n", variableName))
 		builder.WriteString(fmt.Sprintf("var %s = map[int]string{\n", variableName))
 		for i, line := range lines {
-			escapedLine := strings.ReplaceAll(line, "`", "` + \"`\" + `")
-			builder.WriteString(fmt.Sprintf("\t%d: `%s`,\n", i+1, escapedLine))
+			builder.WriteString(fmt.Sprintf("\t%d: %s,\n", i+1, goStringLiteral(line)))
 		}
 		builder.WriteString("}\n")
 
 	default:
 		// Por defecto, usar variable (configuración automática)
 		builder.WriteString(fmt.Sprintf("// %s contiene el contenido del archivo (generado automáticamente)\n", variableName))
-		builder.WriteString(fmt.Sprintf("var %s = `%s`\n", variableName, content))
+		builder.WriteString(fmt.Sprintf("var %s = %s\n", variableName, literal))
 	}
 
 	return builder.String()
 }
 
+// convertJSONToGoStructs genera las definiciones de tipos Go que describen el
+// JSON de content; el tipo raíz toma el nombre de typeName
+func convertJSONToGoStructs(content, packageName, typeName, originalFilename string) (string, error) {
+	value, err := globalParser.ParseJSONWithOptions(content, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	if err != nil {
+		return "", err
+	}
+	types, err := GenerateGoStructs(value, typeName)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	builder.WriteString(fmt.Sprintf("// Archivo generado automáticamente desde: %s\n", goCommentText(originalFilename)))
+	builder.WriteString(fmt.Sprintf("// Generado el: %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	builder.WriteString("// Tipos inferidos a partir del contenido JSON\n\n")
+	builder.WriteString(types)
+	return builder.String(), nil
+}
+
+// convertJSONToGoLiteral embebe el JSON de content como literal Go: con
+// typed usa los tipos de GenerateGoStructs, si no map[string]any y []any
+func convertJSONToGoLiteral(content, packageName, variableName, originalFilename string, typed bool) (string, error) {
+	value, err := globalParser.ParseJSONWithOptions(content, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	if err != nil {
+		return "", err
+	}
+	return goLiteralSource(value, packageName, variableName, originalFilename, typed, "Datos JSON como literal Go, sin decodificar en tiempo de ejecución")
+}
+
+// convertXMLToGoLiteral embebe el XML de content como literal Go, mapeado
+// al árbol de valores con la convención de opts
+func convertXMLToGoLiteral(content, packageName, variableName, originalFilename string, typed bool, opts XMLOptions) (string, error) {
+	value, err := globalParser.ParseXML(content, opts, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	if err != nil {
+		return "", err
+	}
+	description := fmt.Sprintf("Datos XML (convención %s) como literal Go", opts.withDefaults().Convention)
+	return goLiteralSource(value, packageName, variableName, originalFilename, typed, description)
+}
+
+// goLiteralSource escribe el archivo Go con value como literal: con typed
+// usa los tipos de GenerateGoStructs, si no map[string]any y []any
+func goLiteralSource(value interface{}, packageName, variableName, originalFilename string, typed bool, description string) (string, error) {
+	var err error
+	var types, literal string
+	if typed {
+		// El tipo raíz no puede llamarse igual que la variable
+		typeName := capitalizeFirst(variableName)
+		if typeName == variableName {
+			typeName += "Data"
+		}
+		types, literal, err = GoTypedLiteral(value, typeName, variableName, variableName+"Ptr")
+	} else {
+		literal, err = GoValueLiteral(value)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	builder.WriteString(fmt.Sprintf("// Archivo generado automáticamente desde: %s\n", goCommentText(originalFilename)))
+	builder.WriteString(fmt.Sprintf("// Generado el: %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	builder.WriteString("// " + description + "\n\n")
+	builder.WriteString(fmt.Sprintf("// %s contiene los datos de %s\n", variableName, goCommentText(originalFilename)))
+	builder.WriteString(fmt.Sprintf("var %s = %s\n", variableName, literal))
+	if types != "" {
+		builder.WriteString("\n" + types)
+	}
+	return builder.String(), nil
+}
+
+// GoDiagnostic error de sintaxis o de tipos en el código Go generado
+type GoDiagnostic struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// formatGoSource parsea el código generado con go/parser, verifica los tipos
+// con go/types y lo devuelve con formato gofmt. Si no es Go válido devuelve
+// el código sin cambios junto con un diagnóstico por cada error.
+func formatGoSource(source, filename string) (string, []GoDiagnostic, error) {
+	fset := gotoken.NewFileSet()
+	file, err := goparser.ParseFile(fset, filename, source, goparser.ParseComments|goparser.AllErrors)
+	if err != nil {
+		var diagnostics []GoDiagnostic
+		if list, ok := err.(scanner.ErrorList); ok {
+			for _, syntaxErr := range list {
+				diagnostics = append(diagnostics, GoDiagnostic{Line: syntaxErr.Pos.Line, Column: syntaxErr.Pos.Column, Message: syntaxErr.Msg})
+			}
+		}
+		return source, diagnostics, err
+	}
+	if diagnostics := checkGoTypes(fset, file); len(diagnostics) > 0 {
+		first := diagnostics[0]
+		return source, diagnostics, fmt.Errorf("%s:%d:%d: %s", filename, first.Line, first.Column, first.Message)
+	}
+
+	var formatted bytes.Buffer
+	if err := format.Node(&formatted, fset, file); err != nil {
+		return source, nil, err
+	}
+	return formatted.String(), nil, nil
+}
+
+// checkGoTypes verifica los tipos de file y devuelve un diagnóstico por error.
+// Un paquete importado que no se puede cargar (servidor sin la biblioteca
+// estándar de Go) no cuenta como error: go/types omite entonces los errores
+// que dependen de él
+func checkGoTypes(fset *gotoken.FileSet, file *ast.File) []GoDiagnostic {
+	var diagnostics []GoDiagnostic
+	config := types.Config{
+		Importer: importer.Default(),
+		Error: func(err error) {
+			typeErr, ok := err.(types.Error)
+			if !ok || strings.HasPrefix(typeErr.Msg, "could not import ") {
+				return
+			}
+			pos := fset.Position(typeErr.Pos)
+			diagnostics = append(diagnostics, GoDiagnostic{Line: pos.Line, Column: pos.Column, Message: typeErr.Msg})
+		},
+	}
+	config.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	return diagnostics
+}
+
 // capitalizeFirst capitaliza la primera letra de una cadena
 func capitalizeFirst(s string) string {
 	if len(s) == 0 {
 		return s
 	}
-	return strings.ToUpper(s[:1]) + s[1:]
+	first, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(first)) + s[size:]
 }
 
 func generateGoFilename(originalFilename, conversionType string) string {
@@ -785,6 +2448,12 @@ func generateGoFilename(originalFilename, conversionType string) string {
 		filename += "_slice"
 	case "map":
 		filename += "_map"
+	case "structs":
+		filename += "_types"
+	case "json", "json-typed", "xml", "xml-typed":
+		filename += "_data"
+	case "csv":
+		filename += "_rows"
 	default:
 		// Para "variable" o cualquier otro caso, no agregar sufijo
 		// Mantiene el nombre original limpio
@@ -801,13 +2470,8 @@ func getFileExtension(filename string) string {
 	return filename[lastDot:]
 }
 
+// compareResults compara por valor: tolera orden de claves y distintas
+// representaciones numéricas (float64, int64, Number, json.Number)
 func compareResults(result1, result2 interface{}) bool {
-	json1, err1 := json.Marshal(result1)
-	json2, err2 := json.Marshal(result2)
-
-	if err1 != nil || err2 != nil {
-		return false
-	}
-
-	return string(json1) == string(json2)
+	return EqualValues(result1, result2)
 }
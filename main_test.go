@@ -0,0 +1,194 @@
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	gotoken "go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+// Test para la validación de los parámetros del conversor
+func TestValidateConversionParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		pkg        string
+		identifier string
+		mode       string
+		wantErr    string
+	}{
+		{"valores por defecto", "main", "textContent", "variable", ""},
+		{"unicode", "datos", "contenidoÑandú", "const", ""},
+		{"structs", "models", "_config", "structs", ""},
+		{"paquete reservado", "func", "x", "variable", "nombre de paquete inválido 'func': es una palabra reservada de Go"},
+		{"paquete con guion", "mi-paquete", "x", "variable", "nombre de paquete inválido 'mi-paquete': debe empezar"},
+		{"identificador con dígito inicial", "main", "1valor", "map", "identificador inválido '1valor': debe empezar"},
+		{"identificador reservado", "main", "range", "slice", "identificador inválido 'range': es una palabra reservada de Go"},
+		{"identificador vacío", "main", "_", "function", "identificador inválido '_': el identificador vacío"},
+		{"modo desconocido", "main", "x", "yaml", "modo de conversión no soportado 'yaml'"},
+		{"identificador init", "datos", "init", "variable", "identificador inválido 'init': 'init' está reservado"},
+		{"identificador main en main", "main", "main", "const", "identificador inválido 'main': 'main' está reservado"},
+		{"identificador main en otro paquete", "datos", "main", "variable", ""},
+		{"paquete main", "main", "x", "variable", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateConversionParams(tt.pkg, tt.identifier, tt.mode)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("validateConversionParams() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.wantErr)):
+				t.Errorf("validateConversionParams() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Test: capitalizeFirst respeta caracteres de varios bytes
+func TestCapitalizeFirst(t *testing.T) {
+	tests := map[string]string{"": "", "texto": "Texto", "ñandú": "Ñandú", "_x": "_x"}
+	for input, want := range tests {
+		if got := capitalizeFirst(input); got != want {
+			t.Errorf("capitalizeFirst(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// typeCheckGo parsea y verifica tipos del código generado, como lo haría el compilador
+func typeCheckGo(t *testing.T, source string) *types.Package {
+	t.Helper()
+	fset := gotoken.NewFileSet()
+	file, err := parser.ParseFile(fset, "generado.go", source, 0)
+	if err != nil {
+		t.Fatalf("el código generado no compila: %v\n%s", err, source)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := config.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("el código generado no compila: %v\n%s", err, source)
+	}
+	return pkg
+}
+
+// contenidos problemáticos para los literales Go
+var trickyContents = map[string]string{
+	"vacío":         "",
+	"texto":         "hola\n\tmundo",
+	"backticks":     "usa `go run .` y ```bloques```",
+	"solo backtick": "`",
+	"crlf":          "línea 1\r\nlínea 2\r\n",
+	"nul":           "a\x00b",
+	"bom":           "\uFEFFtexto",
+	"controles":     "\x1b[31mrojo\x1b[0m\x7f",
+	"binario":       "\x89PNG\r\n\x1a\n\x00\xff\xfe",
+}
+
+// Test: goStringLiteral conserva exactamente el contenido
+func TestGoStringLiteral(t *testing.T) {
+	for name, content := range trickyContents {
+		t.Run(name, func(t *testing.T) {
+			literal := goStringLiteral(content)
+			pkg := typeCheckGo(t, "package p\n\nconst c = "+literal+"\n")
+			value := pkg.Scope().Lookup("c").(*types.Const).Val()
+			if got := constant.StringVal(value); got != content {
+				t.Errorf("goStringLiteral(%q) = %s evalúa a %q", content, literal, got)
+			}
+		})
+	}
+
+	if got := goStringLiteral("a`b"); got != "`a` + \"`\" + `b`" {
+		t.Errorf("goStringLiteral(\"a`b\") = %s", got)
+	}
+	if got := goStringLiteral("sin problemas"); got != "`sin problemas`" {
+		t.Errorf("goStringLiteral() = %s, want literal raw", got)
+	}
+	if got := goStringLiteral("a\r\nb\r\nc\r\nd\r\ne\r\n"); got != `"a\r\nb\r\nc\r\nd\r\ne\r\n"` {
+		t.Errorf("goStringLiteral() con muchos tramos = %s, want un literal interpretado", got)
+	}
+}
+
+// Test: un archivo CRLF grande produce código que go/parser acepta
+func TestConvertTextToGoLargeCRLF(t *testing.T) {
+	content := strings.Repeat("una línea de texto\r\n", 60000)
+	source := convertTextToGo(content, "main", "textContent", "variable", "grande.txt")
+	if _, _, err := formatGoSource(source, "grande.go"); err != nil {
+		t.Fatalf("formatGoSource() error = %v", err)
+	}
+}
+
+// Test: todos los modos generan código que compila, con cualquier contenido
+func TestConvertTextToGoCompiles(t *testing.T) {
+	for _, mode := range conversionModes {
+		if mode == "structs" || mode == "json" || mode == "json-typed" || mode == "csv" || mode == "xml" || mode == "xml-typed" {
+			continue // tienen su propio generador y sus propios tests
+		}
+		for name, content := range trickyContents {
+			t.Run(mode+"/"+name, func(t *testing.T) {
+				source := convertTextToGo(content, "datos", "contenido", mode, "archivo`\n\"raro\".md")
+				pkg := typeCheckGo(t, source)
+				if mode == "const" {
+					value := pkg.Scope().Lookup("contenido").(*types.Const).Val()
+					if got := constant.StringVal(value); got != content {
+						t.Errorf("const contenido = %q, want %q", got, content)
+					}
+				}
+			})
+		}
+	}
+}
+
+// Test: el contenido binario se embebe como []byte en modo variable
+func TestConvertTextToGoBinary(t *testing.T) {
+	source := convertTextToGo("\x00\x01\xff", "main", "datos", "variable", "datos.bin.txt")
+	if !strings.Contains(source, "var datos = []byte{\n\t0x00, 0x01, 0xff,\n}") {
+		t.Errorf("se esperaba un literal []byte:\n%s", source)
+	}
+	pkg := typeCheckGo(t, source)
+	if got := pkg.Scope().Lookup("datos").Type().String(); got != "[]byte" {
+		t.Errorf("tipo de datos = %s, want []byte", got)
+	}
+}
+
+// Test: formatGoSource aplica gofmt o informa los errores de sintaxis
+func TestFormatGoSource(t *testing.T) {
+	formatted, diagnostics, err := formatGoSource("package main\nvar   x=map[int]string{\n1: `a`,\n10: `b`,\n}\n", "x.go")
+	if err != nil || diagnostics != nil {
+		t.Fatalf("formatGoSource() error = %v, diagnostics = %v", err, diagnostics)
+	}
+	want := "package main\n\nvar x = map[int]string{\n\t1:  `a`,\n\t10: `b`,\n}\n"
+	if formatted != want {
+		t.Errorf("formatGoSource() =\n%s\nwant\n%s", formatted, want)
+	}
+
+	source := "package main\n\nvar x = `abierto\nfunc f( {\n"
+	got, diagnostics, err := formatGoSource(source, "roto.go")
+	if err == nil {
+		t.Fatal("se esperaba error de sintaxis")
+	}
+	if got != source {
+		t.Errorf("el código inválido debe devolverse sin cambios, got %q", got)
+	}
+	if len(diagnostics) == 0 || diagnostics[0].Line != 3 || diagnostics[0].Column != 9 {
+		t.Errorf("diagnostics = %+v, want primer error en 3:9", diagnostics)
+	}
+}
+
+// Test: formatGoSource rechaza el código que parsea pero no compila
+func TestFormatGoSourceTypeErrors(t *testing.T) {
+	tests := map[string]string{
+		"package main\n\nvar init = 1\n":                 "cannot declare init",
+		"package main\n\nvar main = 1\n":                 "cannot declare main",
+		"package p\n\ntype A struct{}\n\nvar A = 1\n":    "A redeclared",
+		"package p\n\nimport \"time\"\n\nvar time = 1\n": "time already declared through import",
+	}
+	for source, wantErr := range tests {
+		_, diagnostics, err := formatGoSource(source, "x.go")
+		if err == nil || !strings.Contains(err.Error(), wantErr) || len(diagnostics) == 0 {
+			t.Errorf("formatGoSource(%q) error = %v, diagnostics = %v, want %q", source, err, diagnostics, wantErr)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import "fmt"
+
+// MergePatch aplica un JSON Merge Patch (RFC 7386): los miembros del patch
+// reemplazan a los del documento, null elimina la clave y cualquier valor que
+// no sea un objeto (incluidos los arrays) reemplaza al destino completo. El
+// documento original no se modifica.
+func MergePatch(target, patch interface{}) interface{} {
+	patchObject := objectValues(patch)
+	if patchObject == nil {
+		return cloneValue(patch)
+	}
+
+	result := cloneValue(target)
+	if objectValues(result) == nil {
+		// Un destino que no es objeto se reemplaza por un objeto vacío
+		if _, ordered := patch.(*OrderedObject); ordered {
+			result = NewOrderedObject()
+		} else {
+			result = map[string]interface{}{}
+		}
+	}
+
+	for _, key := range orderedKeys(patch) {
+		value := patchObject[key]
+		if value == nil {
+			deleteMember(result, key)
+			continue
+		}
+		setMember(result, key, MergePatch(objectValues(result)[key], value))
+	}
+	return result
+}
+
+// CreateMergePatch genera el merge patch que transforma original en
+// modified. Falla si modified contiene un null que el patch no puede expresar
+// (en un merge patch null siempre significa eliminar la clave).
+func CreateMergePatch(original, modified interface{}) (interface{}, error) {
+	return createMergePatch(original, modified, nil)
+}
+
+func createMergePatch(original, modified interface{}, path []string) (interface{}, error) {
+	originalObject, modifiedObject := objectValues(original), objectValues(modified)
+	if originalObject == nil || modifiedObject == nil {
+		if err := checkMergeValue(modified, path); err != nil {
+			return nil, err
+		}
+		return cloneValue(modified), nil
+	}
+
+	patch := NewOrderedObject()
+	for _, key := range orderedKeys(original) {
+		modifiedValue, exists := modifiedObject[key]
+		switch {
+		case !exists:
+			patch.Set(key, nil)
+		case EqualValues(originalObject[key], modifiedValue):
+		default:
+			child, err := createMergePatch(originalObject[key], modifiedValue, appendToken(path, key))
+			if err != nil {
+				return nil, err
+			}
+			patch.Set(key, child)
+		}
+	}
+	for _, key := range orderedKeys(modified) {
+		if _, exists := originalObject[key]; exists {
+			continue
+		}
+		value := modifiedObject[key]
+		if err := checkMergeValue(value, appendToken(path, key)); err != nil {
+			return nil, err
+		}
+		patch.Set(key, cloneValue(value))
+	}
+	return patch, nil
+}
+
+// checkMergeValue verifica que un valor nuevo no contenga null como valor de
+// un miembro: al aplicar el patch se interpretaría como eliminación
+func checkMergeValue(value interface{}, path []string) error {
+	if value == nil && len(path) > 0 {
+		return fmt.Errorf("el valor null en '%s' no se puede expresar en un merge patch", FormatPointer(path))
+	}
+	for _, key := range orderedKeys(value) {
+		if err := checkMergeValue(objectValues(value)[key], appendToken(path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setMember asigna una clave en un objeto, ordenado o no
+func setMember(object interface{}, key string, value interface{}) {
+	if ordered, ok := object.(*OrderedObject); ok {
+		ordered.Set(key, value)
+		return
+	}
+	object.(map[string]interface{})[key] = value
+}
+
+// deleteMember elimina una clave de un objeto, ordenado o no
+func deleteMember(object interface{}, key string) {
+	if ordered, ok := object.(*OrderedObject); ok {
+		ordered.Delete(key)
+		return
+	}
+	delete(object.(map[string]interface{}), key)
+}
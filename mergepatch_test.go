@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test para los ejemplos del apéndice A de RFC 7386
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+" + "+tt.patch, func(t *testing.T) {
+			target := parseExact(t, tt.target)
+			before := string(mustMarshal(t, target))
+			got := string(mustMarshal(t, MergePatch(target, parseExact(t, tt.patch))))
+			if got != tt.want {
+				t.Errorf("MergePatch() = %s, want %s", got, tt.want)
+			}
+			if after := string(mustMarshal(t, target)); after != before {
+				t.Errorf("MergePatch() modificó el documento original: %s", after)
+			}
+		})
+	}
+}
+
+// Test para la generación de merge patches
+func TestCreateMergePatch(t *testing.T) {
+	tests := []struct {
+		name               string
+		original, modified string
+		want               string
+	}{
+		{"sin cambios", `{"a":1,"b":[1,2]}`, `{"b":[1,2],"a":1.0}`, `{}`},
+		{"cambio y eliminación", `{"a":"b","c":{"d":"e","f":"g"}}`, `{"a":"z","c":{"d":"e"}}`, `{"a":"z","c":{"f":null}}`},
+		{"clave nueva", `{"a":1}`, `{"a":1,"b":{"c":true}}`, `{"b":{"c":true}}`},
+		{"array completo", `{"tags":["x","y"]}`, `{"tags":["x"]}`, `{"tags":["x"]}`},
+		{"raíz distinta", `[1]`, `{"a":1}`, `{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, modified := parseExact(t, tt.original), parseExact(t, tt.modified)
+			patch, err := CreateMergePatch(original, modified)
+			if err != nil {
+				t.Fatalf("CreateMergePatch() error = %v", err)
+			}
+			if got := string(mustMarshal(t, patch)); got != tt.want {
+				t.Errorf("CreateMergePatch() = %s, want %s", got, tt.want)
+			}
+			if result := MergePatch(original, patch); !EqualValues(result, modified) {
+				t.Errorf("MergePatch(CreateMergePatch()) = %s, want %s", mustMarshal(t, result), tt.modified)
+			}
+		})
+	}
+}
+
+// Test para los null que un merge patch no puede representar
+func TestCreateMergePatchNull(t *testing.T) {
+	tests := []struct {
+		original, modified, want string
+	}{
+		{`{"a":1}`, `{"a":null}`, "/a"},
+		{`{}`, `{"a":{"b":null}}`, "/a/b"},
+		{`{"a":[1]}`, `{"a":{"x":{"y":null}}}`, "/a/x/y"},
+	}
+
+	for _, tt := range tests {
+		_, err := CreateMergePatch(parseExact(t, tt.original), parseExact(t, tt.modified))
+		if err == nil || !strings.Contains(err.Error(), "'"+tt.want+"'") {
+			t.Errorf("CreateMergePatch(%s, %s) error = %v, want ruta %s", tt.original, tt.modified, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// NumberMode define cómo se representan los números del documento parseado
+type NumberMode int
+
+const (
+	// NumberAsFloat64 convierte todos los números a float64 (comportamiento por defecto)
+	NumberAsFloat64 NumberMode = iota
+	// NumberAsNumber conserva el literal original como Number, sin pérdida de precisión
+	NumberAsNumber
+	// NumberAsInt64 usa int64 para literales enteros y float64 para el resto;
+	// los enteros fuera de rango se conservan como Number
+	NumberAsInt64
+)
+
+// numberModeNames nombres aceptados por la API para cada modo
+var numberModeNames = map[string]NumberMode{
+	"":        NumberAsFloat64,
+	"float64": NumberAsFloat64,
+	"number":  NumberAsNumber,
+	"string":  NumberAsNumber,
+	"int64":   NumberAsInt64,
+}
+
+// ParseNumberMode interpreta el nombre de un modo numérico ("float64", "number", "int64")
+func ParseNumberMode(name string) (NumberMode, error) {
+	mode, ok := numberModeNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return NumberAsFloat64, fmt.Errorf("modo numérico desconocido: %s (use float64, number o int64)", name)
+	}
+	return mode, nil
+}
+
+// Number literal numérico JSON tal como aparece en la entrada
+type Number string
+
+// String devuelve el literal original
+func (n Number) String() string {
+	return string(n)
+}
+
+// Int64 convierte el literal a int64; falla si no es entero o no cabe
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// Float64 convierte el literal a float64 (puede perder precisión)
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// BigInt convierte el literal a un entero de precisión arbitraria; acepta
+// notación científica mientras el valor sea entero (p. ej. 1.5e3)
+func (n Number) BigInt() (*big.Int, error) {
+	if value, ok := new(big.Int).SetString(string(n), 10); ok {
+		return value, nil
+	}
+	rat, ok := new(big.Rat).SetString(string(n))
+	if !ok || !rat.IsInt() {
+		return nil, fmt.Errorf("número no entero: %s", n)
+	}
+	return rat.Num(), nil
+}
+
+// BigFloat convierte el literal a un flotante de precisión arbitraria; la
+// precisión se ajusta a la cantidad de dígitos para no perder información
+func (n Number) BigFloat() (*big.Float, error) {
+	prec := uint(len(n))*4 + 64
+	value, _, err := big.ParseFloat(string(n), 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("número inválido: %s", n)
+	}
+	return value, nil
+}
+
+// MarshalJSON escribe el literal sin reinterpretarlo
+func (n Number) MarshalJSON() ([]byte, error) {
+	if n == "" {
+		return []byte("0"), nil
+	}
+	return []byte(n), nil
+}
+
+// isIntegerLiteral indica si un literal ya validado no tiene fracción ni exponente
+func isIntegerLiteral(literal string) bool {
+	return !strings.ContainsAny(literal, ".eE")
+}
+
+// convertNumberMode convierte un literal validado según el modo pedido
+func (p *Parser) convertNumberMode(literal string, mode NumberMode) (interface{}, error) {
+	switch mode {
+	case NumberAsNumber:
+		return Number(literal), nil
+	case NumberAsInt64:
+		if isIntegerLiteral(literal) {
+			if value, err := strconv.ParseInt(literal, 10, 64); err == nil {
+				return value, nil
+			}
+			return Number(literal), nil
+		}
+	}
+	return p.convertNumber(literal)
+}
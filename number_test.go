@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// Test para los modos de representación numérica
+func TestParseNumberModes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		mode  NumberMode
+		want  interface{}
+	}{
+		{"float64 por defecto", `42`, NumberAsFloat64, 42.0},
+		{"number conserva el literal", `9007199254740993`, NumberAsNumber, Number("9007199254740993")},
+		{"number con decimales", `3.14159265358979323846264338327950288`, NumberAsNumber, Number("3.14159265358979323846264338327950288")},
+		{"int64 entero", `9007199254740993`, NumberAsInt64, int64(9007199254740993)},
+		{"int64 negativo", `-12`, NumberAsInt64, int64(-12)},
+		{"int64 con decimales usa float64", `1.5`, NumberAsInt64, 1.5},
+		{"int64 con exponente usa float64", `1e3`, NumberAsInt64, 1000.0},
+		{"int64 fuera de rango usa Number", `99999999999999999999`, NumberAsInt64, Number("99999999999999999999")},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.ParseJSONWithOptions(tt.input, ParseOptions{NumberMode: tt.mode})
+			if err != nil {
+				t.Fatalf("ParseJSONWithOptions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseJSONWithOptions() = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+// Test para los accesores de Number
+func TestNumberAccessors(t *testing.T) {
+	n := Number("9007199254740993")
+
+	if got, err := n.Int64(); err != nil || got != 9007199254740993 {
+		t.Errorf("Int64() = %d, %v", got, err)
+	}
+	if got, err := n.Float64(); err != nil || got != 9007199254740992 {
+		t.Errorf("Float64() = %v, %v", got, err)
+	}
+	if got, err := n.BigInt(); err != nil || got.String() != "9007199254740993" {
+		t.Errorf("BigInt() = %v, %v", got, err)
+	}
+
+	if got, err := Number("1.5e3").BigInt(); err != nil || got.Int64() != 1500 {
+		t.Errorf("BigInt(1.5e3) = %v, %v", got, err)
+	}
+	if _, err := Number("1.5").BigInt(); err == nil {
+		t.Error("BigInt(1.5) debería fallar")
+	}
+	if _, err := Number("1.5").Int64(); err == nil {
+		t.Error("Int64(1.5) debería fallar")
+	}
+
+	decimal := Number("0.1000000000000000000000000001")
+	got, err := decimal.BigFloat()
+	if err != nil {
+		t.Fatalf("BigFloat() error = %v", err)
+	}
+	want, _, _ := big.ParseFloat("0.1", 10, got.Prec(), big.ToNearestEven)
+	if got.Cmp(want) <= 0 {
+		t.Errorf("BigFloat() = %s perdió precisión", got.Text('g', 30))
+	}
+}
+
+// Test para la serialización sin pérdida de Number
+func TestNumberRoundTrip(t *testing.T) {
+	input := `{"id":9007199254740993,"precio":0.30000000000000000001,"lista":[1E+2,-0]}`
+	result, err := NewParser().ParseJSONWithOptions(input, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	if err != nil {
+		t.Fatalf("ParseJSONWithOptions() error = %v", err)
+	}
+	got, err := FormatValue(result, "")
+	if err != nil {
+		t.Fatalf("FormatValue() error = %v", err)
+	}
+	if got != input {
+		t.Errorf("FormatValue() = %s, want %s", got, input)
+	}
+}
+
+// Test para los nombres de modo aceptados por la API
+func TestParseNumberModeNames(t *testing.T) {
+	for name, want := range map[string]NumberMode{"": NumberAsFloat64, "float64": NumberAsFloat64, "Number": NumberAsNumber, "int64": NumberAsInt64} {
+		if got, err := ParseNumberMode(name); err != nil || got != want {
+			t.Errorf("ParseNumberMode(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseNumberMode("decimal"); err == nil || !strings.Contains(err.Error(), "modo numérico desconocido") {
+		t.Errorf("ParseNumberMode(decimal) error = %v", err)
+	}
+}
+
+// Test para la comparación por valor entre representaciones
+func TestEqualValues(t *testing.T) {
+	ordered := NewOrderedObject()
+	ordered.Set("b", Number("2"))
+	ordered.Set("a", int64(1))
+
+	tests := []struct {
+		name string
+		a, b interface{}
+		want bool
+	}{
+		{"float64 y Number", 1.5, Number("1.5"), true},
+		{"int64 y json.Number", int64(9007199254740993), json.Number("9007199254740993"), true},
+		{"enteros grandes distintos", Number("9007199254740993"), json.Number("9007199254740992"), false},
+		{"float64 redondeado", 9007199254740992.0, Number("9007199254740993"), true},
+		{"exponente equivalente", Number("1e2"), json.Number("100.0"), true},
+		{"objeto ordenado y mapa", ordered, map[string]interface{}{"a": 1.0, "b": 2.0}, true},
+		{"claves distintas", ordered, map[string]interface{}{"a": 1.0, "c": 2.0}, false},
+		{"arrays", []interface{}{"x", nil, true}, []interface{}{"x", nil, true}, true},
+		{"arrays de distinto largo", []interface{}{"x"}, []interface{}{"x", "y"}, false},
+		{"tipos distintos", "1", 1.0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualValues(tt.a, tt.b); got != tt.want {
+				t.Errorf("EqualValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test para el modo numérico del Decoder
+func TestDecoderNumberMode(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`[9007199254740993, 2.5]`))
+	dec.SetNumberMode(NumberAsInt64)
+
+	value, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	items := value.([]interface{})
+	if items[0] != int64(9007199254740993) || items[1] != 2.5 {
+		t.Errorf("Decode() = %v", items)
+	}
+	if got := CountValueElements(value)["numbers"]; got != 2 {
+		t.Errorf("CountValueElements() numbers = %d, want 2", got)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+// ParseOptions opciones de parsing por llamada; el Parser global se comparte
+// entre peticiones, así que las opciones viajan con cada parseo
+type ParseOptions struct {
+	// PreserveOrder construye *OrderedObject en lugar de map[string]interface{}
+	// para conservar el orden de las claves del documento original
+	PreserveOrder bool
+
+	// NumberMode representación de los números (float64, Number o int64)
+	NumberMode NumberMode
+
+	// LenientStrings acepta caracteres de control sin escapar y reemplaza los
+	// surrogates sueltos y el UTF-8 inválido por U+FFFD en lugar de fallar
+	LenientStrings bool
+}
+
+// OrderedObject objeto JSON que conserva el orden de inserción de sus claves.
+// Keys guarda el orden y Values el valor de cada clave.
+type OrderedObject struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// NewOrderedObject crea un objeto ordenado vacío
+func NewOrderedObject() *OrderedObject {
+	return &OrderedObject{Values: make(map[string]interface{})}
+}
+
+// Get devuelve el valor de una clave y si existe
+func (o *OrderedObject) Get(key string) (interface{}, bool) {
+	value, ok := o.Values[key]
+	return value, ok
+}
+
+// Set asigna el valor de una clave; las claves nuevas se agregan al final
+func (o *OrderedObject) Set(key string, value interface{}) {
+	if _, exists := o.Values[key]; !exists {
+		o.Keys = append(o.Keys, key)
+	}
+	o.Values[key] = value
+}
+
+// Delete elimina una clave conservando el orden del resto
+func (o *OrderedObject) Delete(key string) {
+	if _, exists := o.Values[key]; !exists {
+		return
+	}
+	delete(o.Values, key)
+	for i, k := range o.Keys {
+		if k == key {
+			o.Keys = append(o.Keys[:i], o.Keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Len devuelve la cantidad de claves
+func (o *OrderedObject) Len() int {
+	return len(o.Keys)
+}
+
+// ToMap convierte el objeto (y sus descendientes) al árbol sin orden
+func (o *OrderedObject) ToMap() map[string]interface{} {
+	result := make(map[string]interface{}, len(o.Keys))
+	for _, key := range o.Keys {
+		result[key] = unorderValue(o.Values[key])
+	}
+	return result
+}
+
+// unorderValue reemplaza recursivamente los *OrderedObject por mapas
+func unorderValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *OrderedObject:
+		return v.ToMap()
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = unorderValue(item)
+		}
+		return result
+	}
+	return value
+}
+
+// MarshalJSON serializa las claves en su orden original, lo que permite usar
+// *OrderedObject también con encoding/json
+func (o *OrderedObject) MarshalJSON() ([]byte, error) {
+	return Marshal(o)
+}
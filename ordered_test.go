@@ -0,0 +1,135 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test para verificar que el orden de las claves sobrevive a parseo y serialización
+func TestOrderedObjectRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"claves desordenadas", `{"z":1,"a":2,"m":3}`},
+		{"claves numéricas", `{"10":"diez","2":"dos","1":"uno"}`},
+		{"anidado", `{"b":{"y":true,"x":null},"a":[{"k2":"v","k1":"w"}]}`},
+		{"vacío", `{}`},
+		{"html sin escapar", `{"tag":"<b>&</b>"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewParser().ParseJSONWithOptions(tt.input, ParseOptions{PreserveOrder: true})
+			if err != nil {
+				t.Fatalf("ParseJSONWithOptions() error = %v", err)
+			}
+			if _, ok := result.(*OrderedObject); !ok {
+				t.Fatalf("ParseJSONWithOptions() = %T, want *OrderedObject", result)
+			}
+
+			got, err := FormatValue(result, "")
+			if err != nil {
+				t.Fatalf("FormatValue() error = %v", err)
+			}
+			if got != tt.input {
+				t.Errorf("FormatValue() = %s, want %s", got, tt.input)
+			}
+		})
+	}
+}
+
+// Test para el formateo con sangría respetando el orden
+func TestFormatValueIndent(t *testing.T) {
+	result, err := NewParser().ParseJSONWithOptions(`{"nombre":"Ana","edad":30,"tags":["a"]}`, ParseOptions{PreserveOrder: true})
+	if err != nil {
+		t.Fatalf("ParseJSONWithOptions() error = %v", err)
+	}
+
+	got, err := FormatValue(result, "  ")
+	if err != nil {
+		t.Fatalf("FormatValue() error = %v", err)
+	}
+	want := "{\n  \"nombre\": \"Ana\",\n  \"edad\": 30,\n  \"tags\": [\n    \"a\"\n  ]\n}"
+	if got != want {
+		t.Errorf("FormatValue() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// Test para las operaciones del objeto ordenado
+func TestOrderedObjectOperations(t *testing.T) {
+	obj := NewOrderedObject()
+	obj.Set("b", 1.0)
+	obj.Set("a", 2.0)
+	obj.Set("c", 3.0)
+	obj.Set("b", 4.0) // reasignar no cambia la posición
+	obj.Delete("a")
+	obj.Delete("inexistente")
+
+	if !reflect.DeepEqual(obj.Keys, []string{"b", "c"}) {
+		t.Errorf("Keys = %v, want [b c]", obj.Keys)
+	}
+	if value, ok := obj.Get("b"); !ok || value != 4.0 {
+		t.Errorf("Get(b) = %v, %v, want 4, true", value, ok)
+	}
+	if obj.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", obj.Len())
+	}
+
+	nested := NewOrderedObject()
+	nested.Set("x", []interface{}{obj})
+	want := map[string]interface{}{
+		"x": []interface{}{map[string]interface{}{"b": 4.0, "c": 3.0}},
+	}
+	if got := nested.ToMap(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMap() = %v, want %v", got, want)
+	}
+}
+
+// Test para la paridad entre el modo ordenado y el modo por defecto
+func TestPreserveOrderParity(t *testing.T) {
+	input := generateLargeJSON(50)
+	parser := NewParser()
+
+	plain, err := parser.ParseJSON(input)
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+	ordered, err := parser.ParseJSONWithOptions(input, ParseOptions{PreserveOrder: true})
+	if err != nil {
+		t.Fatalf("ParseJSONWithOptions() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(unorderValue(ordered), plain) {
+		t.Error("el modo ordenado produce valores distintos al modo por defecto")
+	}
+	if !reflect.DeepEqual(CountValueElements(ordered), CountValueElements(plain)) {
+		t.Error("CountValueElements() difiere entre modos")
+	}
+	if JSONTypeOf(ordered) != "object" {
+		t.Errorf("JSONTypeOf() = %s, want object", JSONTypeOf(ordered))
+	}
+
+	if _, err := parser.ParseJSONWithOptions(`{"a":1,"a":2}`, ParseOptions{PreserveOrder: true}); err == nil || !strings.Contains(err.Error(), "clave duplicada") {
+		t.Errorf("ParseJSONWithOptions() error = %v, want clave duplicada", err)
+	}
+}
+
+// Test para el Decoder con orden de claves
+func TestDecoderPreserveOrder(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`{"z": {"b": 1, "a": 2}, "y": []}`))
+	dec.PreserveOrder()
+
+	value, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	got, err := FormatValue(value, "")
+	if err != nil {
+		t.Fatalf("FormatValue() error = %v", err)
+	}
+	if want := `{"z":{"b":1,"a":2},"y":[]}`; got != want {
+		t.Errorf("Decode() serializado = %s, want %s", got, want)
+	}
+}
@@ -1,26 +1,27 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
-// Parser estructura principal usando expresiones regulares optimizadas
+// maxNestingDepth límite de anidación para proteger la pila del parser recursivo
+const maxNestingDepth = 10000
+
+// Parser estructura principal: lexer de un solo recorrido + descenso recursivo.
+// Las regex precompiladas se conservan para la detección rápida de tipos.
 type Parser struct {
 	// Regex precompiladas para máximo rendimiento
-	objectRegex     *regexp.Regexp
-	arrayRegex      *regexp.Regexp
-	stringRegex     *regexp.Regexp
-	numberRegex     *regexp.Regexp
-	booleanRegex    *regexp.Regexp
-	nullRegex       *regexp.Regexp
-	keyValueRegex   *regexp.Regexp
-	escapeRegex     *regexp.Regexp
-	whitespaceRegex *regexp.Regexp
-	validationRegex *regexp.Regexp
-	structureRegex  *regexp.Regexp
+	objectRegex  *regexp.Regexp
+	arrayRegex   *regexp.Regexp
+	stringRegex  *regexp.Regexp
+	numberRegex  *regexp.Regexp
+	booleanRegex *regexp.Regexp
+	nullRegex    *regexp.Regexp
 }
 
 // NewParser crea un nuevo parser con todas las regex precompiladas
@@ -43,448 +44,362 @@ func NewParser() *Parser {
 
 		// Regex para null
 		nullRegex: regexp.MustCompile(`^\s*null\s*$`),
-
-		// Regex para pares clave-valor
-		keyValueRegex: regexp.MustCompile(`^\s*"((?:[^"\\]|\\.)*)"\s*:\s*`),
-
-		// Regex para secuencias de escape
-		escapeRegex: regexp.MustCompile(`\\(["\\\/bfnrt]|u[0-9a-fA-F]{4})`),
-
-		// Regex para espacios en blanco
-		whitespaceRegex: regexp.MustCompile(`\s+`),
-
-		// Regex para validación general
-		validationRegex: regexp.MustCompile(`^\s*(?:\{.*\}|\[.*\]|".*"|-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?|true|false|null)\s*$`),
-
-		// Regex para estructuras balanceadas
-		structureRegex: regexp.MustCompile(`[\{\}\[\]]`),
 	}
 }
 
 // ParseJSON función principal de parsing
 func (p *Parser) ParseJSON(input string) (interface{}, error) {
-	cleaned := strings.TrimSpace(input)
-	if cleaned == "" {
-		return nil, fmt.Errorf("entrada JSON vacía")
-	}
-
-	// Validar estructura general
-	if !p.validationRegex.MatchString(cleaned) {
-		return nil, fmt.Errorf("formato JSON inválido")
-	}
+	return p.ParseJSONWithOptions(input, ParseOptions{})
+}
 
-	// Validar balance de estructuras
-	if err := p.validateStructureBalance(cleaned); err != nil {
-		return nil, err
+// ParseJSONWithOptions parsea la entrada aplicando las opciones indicadas
+func (p *Parser) ParseJSONWithOptions(input string, opts ParseOptions) (interface{}, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, newSyntaxError([]byte(input), len(input), "valor JSON", "entrada JSON vacía")
 	}
 
-	// Parsear el valor
-	return p.parseValue(cleaned)
+	state := p.newParseState([]byte(input))
+	state.opts = opts
+	state.lex.lenient = opts.LenientStrings
+	return state.parseDocument()
 }
 
-// parseValue determina el tipo y parsea el valor
-func (p *Parser) parseValue(input string) (interface{}, error) {
-	input = strings.TrimSpace(input)
+// parseState estado de un parseo individual; el Parser se comparte entre
+// goroutines, por lo que todo lo mutable vive aquí
+type parseState struct {
+	parser *Parser
+	lex    *lexer
 
-	// Detectar objetos
-	if matches := p.objectRegex.FindStringSubmatch(input); matches != nil {
-		return p.parseObject(matches[1])
-	}
+	// Contadores de estructuras abiertas para reportar desbalances
+	braces   int
+	brackets int
+	depth    int
 
-	// Detectar arrays
-	if matches := p.arrayRegex.FindStringSubmatch(input); matches != nil {
-		return p.parseArray(matches[1])
-	}
+	// validateOnly recorre la entrada sin construir el árbol de valores
+	validateOnly bool
 
-	// Detectar strings
-	if matches := p.stringRegex.FindStringSubmatch(input); matches != nil {
-		return p.unescapeString(matches[1]), nil
-	}
-
-	// Detectar números
-	if matches := p.numberRegex.FindStringSubmatch(input); matches != nil {
-		return p.parseNumber(matches[1])
-	}
-
-	// Detectar booleanos
-	if matches := p.booleanRegex.FindStringSubmatch(input); matches != nil {
-		return matches[1] == "true", nil
-	}
+	// opts opciones de construcción del árbol (orden de claves, etc.)
+	opts ParseOptions
+}
 
-	// Detectar null
-	if p.nullRegex.MatchString(input) {
-		return nil, nil
-	}
+// newParseState prepara el estado de parsing sobre la entrada
+func (p *Parser) newParseState(data []byte) *parseState {
+	return &parseState{parser: p, lex: newLexer(data)}
+}
 
-	return nil, fmt.Errorf("valor JSON no reconocido: %s", input)
+// next lee el siguiente token indicando al lexer qué se espera en ese punto
+func (s *parseState) next(expected string) (token, error) {
+	s.lex.expected = expected
+	return s.lex.next()
 }
 
-// parseObject parsea objetos JSON
-func (p *Parser) parseObject(content string) (map[string]interface{}, error) {
-	result := make(map[string]interface{})
+// parseDocument parsea un único valor JSON y verifica que no sobre contenido
+func (s *parseState) parseDocument() (interface{}, error) {
+	tok, err := s.next("valor JSON")
+	if err != nil {
+		return nil, err
+	}
 
-	content = strings.TrimSpace(content)
-	if content == "" {
-		return result, nil
+	value, err := s.parseValue(tok)
+	if err != nil {
+		return nil, err
 	}
 
-	// VALIDAR COMAS EXTRA ANTES DE PARSEAR
-	if err := p.validateNoTrailingCommas(content, '}'); err != nil {
+	if err := s.expectEOF(); err != nil {
 		return nil, err
 	}
+	return value, nil
+}
 
-	// Separar pares clave-valor respetando estructuras anidadas
-	pairs, err := p.splitKeyValuePairs(content)
+// expectEOF verifica que no quede contenido después del valor principal
+func (s *parseState) expectEOF() error {
+	tok, err := s.next("fin de entrada")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	for _, pair := range pairs {
-		key, value, err := p.parseKeyValue(pair)
-		if err != nil {
-			return nil, fmt.Errorf("error parseando par clave-valor '%s': %v", pair, err)
-		}
+	switch tok.kind {
+	case tokenEOF:
+		return nil
+	case tokenEndObject:
+		return s.lex.errorf(tok.start, "fin de entrada", "llave de cierre '}' sin apertura correspondiente")
+	case tokenEndArray:
+		return s.lex.errorf(tok.start, "fin de entrada", "corchete de cierre ']' sin apertura correspondiente")
+	default:
+		return s.lex.errorf(tok.start, "fin de entrada", "formato JSON inválido: contenido extra después del valor")
+	}
+}
 
-		// Verificar claves duplicadas
-		if _, exists := result[key]; exists {
-			return nil, fmt.Errorf("clave duplicada: %s", key)
+// parseValue construye el valor que comienza en tok
+func (s *parseState) parseValue(tok token) (interface{}, error) {
+	switch tok.kind {
+	case tokenBeginObject:
+		return s.parseObject(tok)
+	case tokenBeginArray:
+		return s.parseArray(tok)
+	case tokenString:
+		return tok.text, nil
+	case tokenNumber:
+		if s.validateOnly {
+			return nil, nil
 		}
-
-		result[key] = value
+		return s.parser.convertNumberMode(tok.text, s.opts.NumberMode)
+	case tokenTrue:
+		return true, nil
+	case tokenFalse:
+		return false, nil
+	case tokenNull:
+		return nil, nil
+	case tokenEOF:
+		return nil, s.unexpectedEOF(tok)
+	default:
+		return nil, s.lex.errorf(tok.start, "valor JSON", "formato JSON inválido: se esperaba un valor y se encontró '%s'", tok.text)
 	}
-
-	return result, nil
 }
 
-// parseArray parsea arrays JSON
-func (p *Parser) parseArray(content string) ([]interface{}, error) {
-	content = strings.TrimSpace(content)
-	if content == "" {
-		return []interface{}{}, nil
+// enter controla la profundidad de anidación
+func (s *parseState) enter(tok token) error {
+	s.depth++
+	if s.depth > maxNestingDepth {
+		return s.lex.errorf(tok.start, "", "profundidad máxima de anidación excedida (%d)", maxNestingDepth)
 	}
+	return nil
+}
 
-	// VALIDAR COMAS EXTRA ANTES DE PARSEAR
-	if err := p.validateNoTrailingCommas(content, ']'); err != nil {
+// parseObject parsea objetos JSON; tok es la llave de apertura
+func (s *parseState) parseObject(open token) (interface{}, error) {
+	if err := s.enter(open); err != nil {
 		return nil, err
 	}
+	s.braces++
 
-	// Separar elementos respetando estructuras anidadas
-	elements, err := p.splitArrayElements(content)
+	var result map[string]interface{}
+	var ordered *OrderedObject
+	var seen map[string]bool // claves vistas en modo solo validación
+	if !s.validateOnly {
+		if s.opts.PreserveOrder {
+			ordered = NewOrderedObject()
+			result = ordered.Values
+		} else {
+			result = make(map[string]interface{})
+		}
+	}
+
+	tok, err := s.next("clave entre comillas o '}'")
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]interface{}, len(elements))
-	for i, element := range elements {
-		value, err := p.parseValue(strings.TrimSpace(element))
-		if err != nil {
-			return nil, fmt.Errorf("error parseando elemento del array '%s': %v", element, err)
-		}
-		result[i] = value
-	}
+	if tok.kind != tokenEndObject {
+		for {
+			if tok.kind != tokenString {
+				if tok.kind == tokenEOF {
+					return nil, s.unexpectedEOF(tok)
+				}
+				return nil, s.lex.errorf(tok.start, "clave entre comillas", "formato JSON inválido: se esperaba una clave entre comillas y se encontró '%s'", tok.text)
+			}
+			key := tok.text
 
-	return result, nil
-}
+			// Verificar claves duplicadas (también al solo validar)
+			if s.validateOnly {
+				if seen[key] {
+					return nil, s.lex.errorf(tok.start, "", "clave duplicada: %s", key)
+				}
+				if seen == nil {
+					seen = make(map[string]bool)
+				}
+				seen[key] = true
+			} else if _, exists := result[key]; exists {
+				return nil, s.lex.errorf(tok.start, "", "clave duplicada: %s", key)
+			}
 
-// splitKeyValuePairs separa pares clave-valor respetando anidación
-func (p *Parser) splitKeyValuePairs(content string) ([]string, error) {
-	var pairs []string
-	var current strings.Builder
-	var depth int
-	var inString bool
-	var lastWasEscape bool
+			tok, err = s.next("':'")
+			if err != nil {
+				return nil, err
+			}
+			if tok.kind != tokenColon {
+				if tok.kind == tokenEOF {
+					return nil, s.unexpectedEOF(tok)
+				}
+				return nil, s.lex.errorf(tok.start, "':'", "formato JSON inválido: se esperaba ':' después de la clave '%s'", key)
+			}
 
-	for i, char := range content {
-		if lastWasEscape {
-			lastWasEscape = false
-			current.WriteRune(char)
-			continue
-		}
+			tok, err = s.next("valor JSON")
+			if err != nil {
+				return nil, err
+			}
+			if tok.kind == tokenComma || tok.kind == tokenEndObject {
+				return nil, s.lex.errorf(tok.start, "valor JSON", "valor faltante para la clave '%s'", key)
+			}
 
-		if char == '\\' && inString {
-			lastWasEscape = true
-			current.WriteRune(char)
-			continue
-		}
+			value, err := s.parseValue(tok)
+			if err != nil {
+				return nil, err
+			}
 
-		if char == '"' {
-			inString = !inString
-			current.WriteRune(char)
-			continue
-		}
+			if !s.validateOnly {
+				if ordered != nil {
+					ordered.Keys = append(ordered.Keys, key)
+				}
+				result[key] = value
+			}
 
-		if !inString {
-			switch char {
-			case '{', '[':
-				depth++
-				current.WriteRune(char)
-			case '}', ']':
-				depth--
-				current.WriteRune(char)
-			case ',':
-				if depth == 0 {
-					pairStr := strings.TrimSpace(current.String())
-					if pairStr == "" {
-						return nil, fmt.Errorf("par clave-valor vacío en posición %d", i)
-					}
-					pairs = append(pairs, pairStr)
-					current.Reset()
-				} else {
-					current.WriteRune(char)
+			tok, err = s.next("',' o '}'")
+			if err != nil {
+				return nil, err
+			}
+			if tok.kind == tokenEndObject {
+				break
+			}
+			if tok.kind != tokenComma {
+				if tok.kind == tokenEOF {
+					return nil, s.unexpectedEOF(tok)
 				}
-			default:
-				current.WriteRune(char)
+				return nil, s.lex.errorf(tok.start, "',' o '}'", "formato JSON inválido: se esperaba ',' o '}' y se encontró '%s'", tok.text)
 			}
-		} else {
-			current.WriteRune(char)
-		}
-	}
 
-	// Agregar el último par
-	if current.Len() > 0 {
-		pairStr := strings.TrimSpace(current.String())
-		if pairStr != "" {
-			pairs = append(pairs, pairStr)
+			comma := tok
+			tok, err = s.next("clave entre comillas")
+			if err != nil {
+				return nil, err
+			}
+			if tok.kind == tokenEndObject {
+				return nil, s.lex.errorf(comma.start, "clave entre comillas", "coma extra antes de '}'")
+			}
 		}
 	}
 
-	return pairs, nil
+	s.braces--
+	s.depth--
+	if s.validateOnly {
+		return nil, nil
+	}
+	if ordered != nil {
+		return ordered, nil
+	}
+	return result, nil
 }
 
-// splitArrayElements separa elementos de array respetando anidación
-func (p *Parser) splitArrayElements(content string) ([]string, error) {
-	var elements []string
-	var current strings.Builder
-	var depth int
-	var inString bool
-	var lastWasEscape bool
+// parseArray parsea arrays JSON; tok es el corchete de apertura
+func (s *parseState) parseArray(open token) (interface{}, error) {
+	if err := s.enter(open); err != nil {
+		return nil, err
+	}
+	s.brackets++
 
-	for i, char := range content {
-		if lastWasEscape {
-			lastWasEscape = false
-			current.WriteRune(char)
-			continue
-		}
+	var result []interface{}
+	if !s.validateOnly {
+		result = []interface{}{}
+	}
 
-		if char == '\\' && inString {
-			lastWasEscape = true
-			current.WriteRune(char)
-			continue
-		}
+	tok, err := s.next("valor JSON o ']'")
+	if err != nil {
+		return nil, err
+	}
 
-		if char == '"' {
-			inString = !inString
-			current.WriteRune(char)
-			continue
-		}
+	if tok.kind != tokenEndArray {
+		for {
+			value, err := s.parseValue(tok)
+			if err != nil {
+				return nil, err
+			}
+			if !s.validateOnly {
+				result = append(result, value)
+			}
 
-		if !inString {
-			switch char {
-			case '{', '[':
-				depth++
-				current.WriteRune(char)
-			case '}', ']':
-				depth--
-				current.WriteRune(char)
-			case ',':
-				if depth == 0 {
-					elemStr := strings.TrimSpace(current.String())
-					if elemStr == "" {
-						return nil, fmt.Errorf("elemento vacío en posición %d", i)
-					}
-					elements = append(elements, elemStr)
-					current.Reset()
-				} else {
-					current.WriteRune(char)
+			tok, err = s.next("',' o ']'")
+			if err != nil {
+				return nil, err
+			}
+			if tok.kind == tokenEndArray {
+				break
+			}
+			if tok.kind != tokenComma {
+				if tok.kind == tokenEOF {
+					return nil, s.unexpectedEOF(tok)
 				}
-			default:
-				current.WriteRune(char)
+				return nil, s.lex.errorf(tok.start, "',' o ']'", "formato JSON inválido: se esperaba ',' o ']' y se encontró '%s'", tok.text)
 			}
-		} else {
-			current.WriteRune(char)
-		}
-	}
 
-	// Agregar el último elemento
-	if current.Len() > 0 {
-		elemStr := strings.TrimSpace(current.String())
-		if elemStr != "" {
-			elements = append(elements, elemStr)
+			comma := tok
+			tok, err = s.next("valor JSON")
+			if err != nil {
+				return nil, err
+			}
+			if tok.kind == tokenEndArray {
+				return nil, s.lex.errorf(comma.start, "valor JSON", "coma extra antes de ']'")
+			}
 		}
 	}
 
-	return elements, nil
-}
-
-// parseKeyValue parsea un par clave-valor
-func (p *Parser) parseKeyValue(pair string) (string, interface{}, error) {
-	pair = strings.TrimSpace(pair)
-
-	// Encontrar el separador ':'
-	matches := p.keyValueRegex.FindStringSubmatch(pair)
-	if matches == nil {
-		return "", nil, fmt.Errorf("formato de par clave-valor inválido: %s", pair)
-	}
-
-	key := p.unescapeString(matches[1])
-
-	// Extraer el valor después del ':'
-	keyEndIndex := p.keyValueRegex.FindStringIndex(pair)
-	if keyEndIndex == nil {
-		return "", nil, fmt.Errorf("no se pudo encontrar separador ':' en: %s", pair)
+	s.brackets--
+	s.depth--
+	if s.validateOnly {
+		return nil, nil
 	}
+	return result, nil
+}
 
-	valueStr := strings.TrimSpace(pair[keyEndIndex[1]:])
-	if valueStr == "" {
-		return "", nil, fmt.Errorf("valor faltante para la clave '%s'", key)
+// unexpectedEOF describe el fin de entrada prematuro según lo que quedó abierto
+func (s *parseState) unexpectedEOF(tok token) error {
+	if s.braces > 0 {
+		return s.lex.errorf(tok.start, "'}'", "llaves desbalanceadas: %d llaves sin cerrar", s.braces)
 	}
-
-	value, err := p.parseValue(valueStr)
-	if err != nil {
-		return "", nil, err
+	if s.brackets > 0 {
+		return s.lex.errorf(tok.start, "']'", "corchetes desbalanceados: %d corchetes sin cerrar", s.brackets)
 	}
-
-	return key, value, nil
+	return s.lex.errorf(tok.start, "valor JSON", "formato JSON inválido: fin de entrada inesperado")
 }
 
 // parseNumber parsea números con validaciones JSON estrictas
 func (p *Parser) parseNumber(numberStr string) (float64, error) {
-	// Validaciones JSON estrictas
-	if strings.HasPrefix(numberStr, "00") || strings.HasPrefix(numberStr, "-00") {
-		return 0, fmt.Errorf("números no pueden empezar con múltiples ceros: %s", numberStr)
+	lex := newLexer([]byte(numberStr))
+	tok, err := lex.scanNumber()
+	if err != nil {
+		return 0, err
 	}
-
-	if strings.HasSuffix(numberStr, ".") {
-		return 0, fmt.Errorf("número decimal mal formado: %s", numberStr)
+	if tok.end != len(numberStr) {
+		return 0, fmt.Errorf("número inválido: %s", numberStr)
 	}
 
-	// Verificar notación científica válida
-	if strings.Contains(numberStr, "e") || strings.Contains(numberStr, "E") {
-		parts := regexp.MustCompile(`[eE]`).Split(numberStr, 2)
-		if len(parts) == 2 && (parts[1] == "" || parts[1] == "+" || parts[1] == "-") {
-			return 0, fmt.Errorf("exponente inválido en notación científica: %s", numberStr)
-		}
+	value, err := p.convertNumber(tok.text)
+	if err != nil {
+		return 0, err
 	}
+	return value.(float64), nil
+}
 
-	number, err := strconv.ParseFloat(numberStr, 64)
+// convertNumber convierte un literal numérico ya validado por el lexer
+func (p *Parser) convertNumber(literal string) (interface{}, error) {
+	number, err := strconv.ParseFloat(literal, 64)
 	if err != nil {
-		return 0, fmt.Errorf("número inválido: %s", numberStr)
+		return nil, fmt.Errorf("número inválido: %s", literal)
 	}
-
 	return number, nil
 }
 
-// unescapeString procesa secuencias de escape
+// unescapeString procesa secuencias de escape; ante una secuencia inválida
+// devuelve el texto sin cambios
 func (p *Parser) unescapeString(s string) string {
-	return p.escapeRegex.ReplaceAllStringFunc(s, func(match string) string {
-		switch match {
-		case `\"`:
-			return `"`
-		case `\\`:
-			return `\`
-		case `\/`:
-			return `/`
-		case `\b`:
-			return "\b"
-		case `\f`:
-			return "\f"
-		case `\n`:
-			return "\n"
-		case `\r`:
-			return "\r"
-		case `\t`:
-			return "\t"
-		default:
-			// Secuencias unicode \uXXXX
-			if len(match) == 6 && strings.HasPrefix(match, `\u`) {
-				if codePoint, err := strconv.ParseUint(match[2:], 16, 16); err == nil {
-					return string(rune(codePoint))
-				}
-			}
-			return match
-		}
-	})
-}
-
-// validateStructureBalance valida el balance de estructuras
-func (p *Parser) validateStructureBalance(input string) error {
-	braceCount := 0
-	bracketCount := 0
-	inString := false
-	lastWasEscape := false
-
-	for i, char := range input {
-		if lastWasEscape {
-			lastWasEscape = false
-			continue
-		}
-
-		if char == '\\' && inString {
-			lastWasEscape = true
-			continue
-		}
-
-		if char == '"' {
-			inString = !inString
-			continue
-		}
-
-		if inString {
-			continue
-		}
-
-		switch char {
-		case '{':
-			braceCount++
-		case '}':
-			braceCount--
-			if braceCount < 0 {
-				return fmt.Errorf("llave de cierre '}' sin apertura correspondiente en posición %d", i)
-			}
-		case '[':
-			bracketCount++
-		case ']':
-			bracketCount--
-			if bracketCount < 0 {
-				return fmt.Errorf("corchete de cierre ']' sin apertura correspondiente en posición %d", i)
-			}
-		}
-	}
-
-	if braceCount != 0 {
-		return fmt.Errorf("llaves desbalanceadas: %d llaves sin cerrar", braceCount)
-	}
-
-	if bracketCount != 0 {
-		return fmt.Errorf("corchetes desbalanceados: %d corchetes sin cerrar", bracketCount)
+	text, _, err := unquoteContent([]byte(s), false)
+	if err != nil {
+		return s
 	}
-
-	return nil
+	return text
 }
 
-// FastValidateJSON validación rápida usando regex
+// FastValidateJSON validación rápida: recorre la entrada una sola vez sin
+// construir el árbol de valores
 func (p *Parser) FastValidateJSON(input string) error {
-	cleaned := strings.TrimSpace(input)
-	if cleaned == "" {
-		return fmt.Errorf("entrada JSON vacía")
-	}
-
-	// Validación rápida de formato
-	if !p.validationRegex.MatchString(cleaned) {
-		return fmt.Errorf("formato JSON inválido")
-	}
-
-	// Validación de balance de estructuras
-	if err := p.validateStructureBalance(cleaned); err != nil {
-		return err
-	}
-
-	// Validación específica de comas extra con regex
-	if err := p.validateNoTrailingCommasRegex(cleaned); err != nil {
-		return err
+	if strings.TrimSpace(input) == "" {
+		return newSyntaxError([]byte(input), len(input), "valor JSON", "entrada JSON vacía")
 	}
 
-	return nil
+	state := p.newParseState([]byte(input))
+	state.validateOnly = true
+	state.lex.discard = true
+	_, err := state.parseDocument()
+	return err
 }
 
 // ExtractJSONType detecta el tipo de valor JSON
@@ -551,137 +466,195 @@ func (p *Parser) CountJSONElements(input string) (map[string]int, error) {
 	return counts, nil
 }
 
-// Funciones de conveniencia
-
-// OptimizedParseJSON función de conveniencia
-func OptimizedParseJSON(input string) (interface{}, error) {
-	parser := NewParser()
-	return parser.ParseJSON(input)
+// JSONTypeOf detecta el tipo JSON de un valor ya parseado
+func JSONTypeOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, *OrderedObject:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64, int64, Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return "unknown"
 }
 
-// FastValidateJSON función de conveniencia para validación
-func FastValidateJSON(input string) error {
-	parser := NewParser()
-	return parser.FastValidateJSON(input)
+// CountValueElements cuenta los elementos recorriendo un valor ya parseado
+// (exacto, a diferencia del conteo aproximado con regex)
+func CountValueElements(value interface{}) map[string]int {
+	counts := map[string]int{
+		"objects":  0,
+		"arrays":   0,
+		"strings":  0,
+		"numbers":  0,
+		"booleans": 0,
+		"nulls":    0,
+	}
+	countValue(value, counts)
+	return counts
 }
 
-// IsValidJSON verifica si un string es JSON válido
-func IsValidJSON(input string) bool {
-	parser := NewParser()
-	err := parser.FastValidateJSON(input)
-	return err == nil
+func countValue(value interface{}, counts map[string]int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		counts["objects"]++
+		for _, item := range v {
+			countValue(item, counts)
+		}
+	case *OrderedObject:
+		counts["objects"]++
+		for _, item := range v.Values {
+			countValue(item, counts)
+		}
+	case []interface{}:
+		counts["arrays"]++
+		for _, item := range v {
+			countValue(item, counts)
+		}
+	case string:
+		counts["strings"]++
+	case float64, int64, Number:
+		counts["numbers"]++
+	case bool:
+		counts["booleans"]++
+	case nil:
+		counts["nulls"]++
+	}
 }
 
-// validateNoTrailingCommasRegex usa regex para detectar comas extra
-func (p *Parser) validateNoTrailingCommasRegex(input string) error {
-	// Regex para detectar comas seguidas solo de espacios y luego } o ]
-	trailingCommaRegex := regexp.MustCompile(`,\s*[}\]]`)
-
-	// Necesitamos verificar si la coma está fuera de strings
-	matches := trailingCommaRegex.FindAllStringIndex(input, -1)
-
-	for _, match := range matches {
-		pos := match[0] // Posición de la coma
-
-		// Verificar si esta coma está dentro de un string
-		beforeComma := input[:pos]
-
-		// Contar comillas no escapadas antes de la coma
-		quoteCount := 0
-		escaped := false
-
-		for _, char := range beforeComma {
-			if escaped {
-				escaped = false
-				continue
-			}
-
-			if char == '\\' {
-				escaped = true
-				continue
+// EqualValues compara dos árboles de valores por contenido: los objetos
+// ordenados equivalen a mapas y los números se comparan por valor sin
+// importar su representación (float64, int64, Number o json.Number)
+func EqualValues(a, b interface{}) bool {
+	// Atajo sin big.Rat para dos literales enteros que caben en int64
+	if aLiteral, ok := a.(Number); ok {
+		if bLiteral, ok := b.(Number); ok {
+			aInt, aErr := strconv.ParseInt(string(aLiteral), 10, 64)
+			bInt, bErr := strconv.ParseInt(string(bLiteral), 10, 64)
+			if aErr == nil && bErr == nil {
+				return aInt == bInt
 			}
+		}
+	}
+	if aNum, ok := numericLiteral(a); ok {
+		bNum, ok := numericLiteral(b)
+		return ok && numbersEqual(aNum, bNum)
+	}
 
-			if char == '"' {
-				quoteCount++
+	switch av := a.(type) {
+	case map[string]interface{}, *OrderedObject:
+		aMap, bMap := objectValues(av), objectValues(b)
+		if bMap == nil || len(aMap) != len(bMap) {
+			return false
+		}
+		for key, value := range aMap {
+			other, exists := bMap[key]
+			if !exists || !EqualValues(value, other) {
+				return false
 			}
 		}
-
-		// Si hay un número par de comillas, estamos fuera de un string
-		// Si hay un número impar, estamos dentro de un string
-		if quoteCount%2 == 0 {
-			// Estamos fuera de string, esta coma es inválida
-			closingChar := input[match[1]-1] // El carácter después de los espacios
-			return fmt.Errorf("coma extra antes de '%c' en posición %d", closingChar, pos)
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !EqualValues(av[i], bv[i]) {
+				return false
+			}
 		}
+		return true
+	case string, bool, nil:
+		return a == b
 	}
-
-	return nil
+	return false
 }
 
-// validateNoTrailingCommas valida que no haya comas extra antes de cerrar
-func (p *Parser) validateNoTrailingCommas(content string, closingChar rune) error {
-	content = strings.TrimSpace(content)
-	if content == "" {
-		return nil
+// objectValues devuelve el mapa de claves de un objeto (ordenado o no)
+func objectValues(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v
+	case *OrderedObject:
+		return v.Values
 	}
+	return nil
+}
 
-	// Buscar comas que no estén seguidas de contenido válido
-	var inString bool
-	var lastWasEscape bool
-	var depth int
-	var lastNonWhitespace rune
-	var lastNonWhitespacePos int
+// numericValue representación común para comparar números: exacta cuando
+// ambos lados conservan el literal, float64 cuando alguno ya lo redondeó
+type numericValue struct {
+	exact   *big.Rat
+	float   float64
+	rounded bool
+}
 
-	for i, char := range content {
-		if lastWasEscape {
-			lastWasEscape = false
-			continue
-		}
+// numericLiteral extrae el valor numérico de cualquier representación soportada
+func numericLiteral(value interface{}) (numericValue, bool) {
+	var literal string
+	switch v := value.(type) {
+	case float64:
+		return numericValue{float: v, rounded: true}, true
+	case int64:
+		return numericValue{exact: new(big.Rat).SetInt64(v), float: float64(v)}, true
+	case Number:
+		literal = string(v)
+	case json.Number:
+		literal = string(v)
+	default:
+		return numericValue{}, false
+	}
+
+	rat, ok := new(big.Rat).SetString(literal)
+	if !ok {
+		return numericValue{}, false
+	}
+	f, _ := strconv.ParseFloat(literal, 64)
+	return numericValue{exact: rat, float: f}, true
+}
 
-		if char == '\\' && inString {
-			lastWasEscape = true
-			continue
-		}
+func numbersEqual(a, b numericValue) bool {
+	return compareNumbers(a, b) == 0
+}
 
-		if char == '"' {
-			inString = !inString
-			if !inString {
-				lastNonWhitespace = char
-				lastNonWhitespacePos = i
-			}
-			continue
+// compareNumbers devuelve -1, 0 o 1 según a sea menor, igual o mayor que b
+func compareNumbers(a, b numericValue) int {
+	if a.rounded || b.rounded {
+		switch {
+		case a.float < b.float:
+			return -1
+		case a.float > b.float:
+			return 1
 		}
+		return 0
+	}
+	return a.exact.Cmp(b.exact)
+}
 
-		if inString {
-			continue
-		}
+// Funciones de conveniencia
 
-		switch char {
-		case '{', '[':
-			depth++
-			lastNonWhitespace = char
-			lastNonWhitespacePos = i
-		case '}', ']':
-			depth--
-			lastNonWhitespace = char
-			lastNonWhitespacePos = i
-		case ',':
-			if depth == 0 {
-				lastNonWhitespace = char
-				lastNonWhitespacePos = i
-			}
-		case ' ', '\t', '\n', '\r':
-			// Ignorar espacios en blanco
-		default:
-			lastNonWhitespace = char
-			lastNonWhitespacePos = i
-		}
-	}
+// OptimizedParseJSON función de conveniencia
+func OptimizedParseJSON(input string) (interface{}, error) {
+	parser := NewParser()
+	return parser.ParseJSON(input)
+}
 
-	// Si el último carácter no-espacio es una coma, es inválido
-	if lastNonWhitespace == ',' {
-		return fmt.Errorf("coma extra antes de '%c' en posición %d", closingChar, lastNonWhitespacePos)
-	}
+// FastValidateJSON función de conveniencia para validación
+func FastValidateJSON(input string) error {
+	parser := NewParser()
+	return parser.FastValidateJSON(input)
+}
 
-	return nil
+// IsValidJSON verifica si un string es JSON válido
+func IsValidJSON(input string) bool {
+	parser := NewParser()
+	err := parser.FastValidateJSON(input)
+	return err == nil
 }
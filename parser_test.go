@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
@@ -146,6 +147,8 @@ func TestFastValidateJSON(t *testing.T) {
 		{"JSON inválido - sin comillas", `{key: value}`, true},
 		{"JSON inválido - sin cerrar", `{"key": "value"`, true},
 		{"Entrada vacía", ``, true},
+		{"Clave duplicada", `{"a": 1, "b": {"a": 2}, "a": 3}`, true},
+		{"Misma clave en objetos distintos", `[{"a": 1}, {"a": 2}]`, false},
 	}
 
 	for _, tt := range tests {
@@ -224,6 +227,8 @@ func BenchmarkParseJSON(b *testing.B) {
 		{"Simple object", `{"name": "John", "age": 30}`},
 		{"Array", `[1, 2, 3, 4, 5]`},
 		{"Complex nested", `{"users": [{"name": "Ana", "data": {"score": 95.5, "active": true}}, {"name": "Carlos", "data": {"score": 87.2, "active": false}}]}`},
+		{"Deep nesting", strings.Repeat(`{"a": [`, 200) + "1" + strings.Repeat("]}", 200)},
+		{"Large payload", generateLargeJSON(5000)},
 	}
 
 	for _, tc := range testCases {
@@ -239,6 +244,79 @@ func BenchmarkParseJSON(b *testing.B) {
 	}
 }
 
+// generateLargeJSON genera un objeto con n elementos en el array "items"
+func generateLargeJSON(n int) string {
+	var sb strings.Builder
+	sb.WriteString(`{"items": [`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(fmt.Sprintf(`{"id": %d, "name": "item%d", "tags": ["a", "b\\n"], "score": %d.5, "ok": true}`, i, i, i))
+	}
+	sb.WriteString(`]}`)
+	return sb.String()
+}
+
+// Test de paridad contra encoding/json sobre documentos variados
+func TestParseJSONParity(t *testing.T) {
+	inputs := []string{
+		`{"name": "John", "age": 30, "tags": ["a", "b"], "meta": {"x": null, "y": false}}`,
+		`[1, -2, 3.5, 1e10, -1.5E-3, 0, "\u00e9\t\"", [], {}]`,
+		"{\n\t\"deep\": [[[[{\"k\": [1, 2, {\"z\": \"fin\"}]}]]]]\n}",
+		`"texto con \/ barra"`,
+		generateLargeJSON(50),
+	}
+
+	for i, input := range inputs {
+		p := NewParser()
+		got, err := p.ParseJSON(input)
+		if err != nil {
+			t.Fatalf("entrada %d: ParseJSON() error = %v", i, err)
+		}
+
+		var want interface{}
+		if err := json.Unmarshal([]byte(input), &want); err != nil {
+			t.Fatalf("entrada %d: json.Unmarshal() error = %v", i, err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("entrada %d: ParseJSON() = %v, want %v", i, got, want)
+		}
+	}
+}
+
+// Test para errores estructurales detectados por el parser recursivo
+func TestParseJSONStructuralErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		errorContains string
+	}{
+		{"Llave extra al final", `{"a": 1}}`, "llave de cierre '}' sin apertura correspondiente en línea 1, columna 9"},
+		{"Corchete extra al final", `[1]]`, "corchete de cierre ']' sin apertura correspondiente"},
+		{"Cierre cruzado", `{"a": [1, 2}`, "se esperaba ',' o ']'"},
+		{"Valor faltante", `{"a": }`, "valor faltante para la clave 'a'"},
+		{"Dos puntos faltantes", `{"a" 1}`, "se esperaba ':'"},
+		{"Contenido extra", `1 2`, "contenido extra después del valor"},
+		{"Clave duplicada", `{"a":1,"a":2}`, "clave duplicada: a en línea 1, columna 8"},
+		{"Coma inicial en array", `[,1]`, "se esperaba un valor"},
+		{"Anidación excesiva", strings.Repeat("[", maxNestingDepth+1), "profundidad máxima de anidación"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser().ParseJSON(tt.input)
+			if err == nil {
+				t.Fatalf("ParseJSON() esperaba error para %q", tt.input)
+			}
+			if !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("ParseJSON() error = %q, want to contain %q", err.Error(), tt.errorContains)
+			}
+		})
+	}
+}
+
 // Test de robustez con JSON grandes
 func TestParseJSONLarge(t *testing.T) {
 	// Generar un JSON grande
@@ -282,7 +360,7 @@ func TestParseJSONLarge(t *testing.T) {
 	}
 }
 
-// Test para verificar balance de estructuras
+// Test para verificar balance de estructuras con la validación rápida
 func TestValidateStructureBalance(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -302,10 +380,10 @@ func TestValidateStructureBalance(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := NewParser()
-			err := p.validateStructureBalance(tt.input)
+			err := p.FastValidateJSON(tt.input)
 
 			if (err != nil) != tt.wantError {
-				t.Errorf("validateStructureBalance() error = %v, wantErr %v", err, tt.wantError)
+				t.Errorf("FastValidateJSON() error = %v, wantErr %v", err, tt.wantError)
 			}
 		})
 	}
@@ -382,6 +460,133 @@ func TestUnescapeString(t *testing.T) {
 	}
 }
 
+// Test para la decodificación RFC 8259 de strings, incluidos los surrogates UTF-16
+func TestUnescapeStringUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Par surrogate (emoji)", `\ud83d\ude00`, "😀"},
+		{"Par surrogate en mayúsculas", `\uD834\uDD1E`, "𝄞"},
+		{"Par entre texto", `a\ud83d\ude00b`, "a😀b"},
+		{"UTF-8 literal", "ñandú 😀", "ñandú 😀"},
+		{"Escape nulo", `\u0000`, "\x00"},
+		{"Último BMP", `\uffff`, "\uffff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			result := p.unescapeString(tt.input)
+
+			if result != tt.expected {
+				t.Errorf("unescapeString() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// Test para las secuencias que RFC 8259 no admite en modo estricto
+func TestUnquoteContentErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		offset    int
+		errSubstr string
+	}{
+		{"Surrogate alto suelto", `ab\ud83d`, 2, "surrogate UTF-16 sin pareja"},
+		{"Surrogate alto seguido de texto", `\ud83dx`, 0, "surrogate UTF-16 sin pareja"},
+		{"Surrogate bajo suelto", `\ude00`, 0, "surrogate UTF-16 sin pareja"},
+		{"Dos surrogates altos", `\ud83d\ud83d`, 0, "surrogate UTF-16 sin pareja"},
+		{"Tab sin escapar", "a\tb", 1, "carácter de control U+0009 sin escapar"},
+		{"Salto de línea sin escapar", "a\nb", 1, "carácter de control U+000A"},
+		{"Escape inválido", `a\xb`, 1, "secuencia de escape inválida '\\x'"},
+		{"Unicode inválido", `\u12G4`, 0, "secuencia unicode inválida"},
+		{"Unicode incompleto", `\u12`, 0, "secuencia unicode incompleta"},
+		{"UTF-8 inválido", "a\xffb", 1, "secuencia UTF-8 inválida"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, offset, err := unquoteContent([]byte(tt.input), false)
+			if err == nil {
+				t.Fatalf("unquoteContent(%q) no devolvió error", tt.input)
+			}
+			if !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("unquoteContent() error = %v, want %q", err, tt.errSubstr)
+			}
+			if offset != tt.offset {
+				t.Errorf("unquoteContent() offset = %d, want %d", offset, tt.offset)
+			}
+		})
+	}
+}
+
+// Test para el modo tolerante: reemplazo por U+FFFD en lugar de error
+func TestUnquoteContentLenient(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Surrogate alto suelto", `a\ud83db`, "a\ufffdb"},
+		{"Surrogate bajo suelto", `\ude00`, "\ufffd"},
+		{"Surrogate alto antes de un par", `\ud83d\ud83d\ude00`, "\ufffd😀"},
+		{"Control sin escapar se conserva", "a\tb", "a\tb"},
+		{"UTF-8 inválido", "a\xffb", "a\ufffdb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, err := unquoteContent([]byte(tt.input), true)
+			if err != nil {
+				t.Fatalf("unquoteContent() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("unquoteContent() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// Test para la validación de strings dentro de documentos completos
+func TestParseJSONStringValidation(t *testing.T) {
+	parser := NewParser()
+
+	strict := []string{
+		"{\"a\": \"línea1\nlínea2\"}",
+		`["\udc00"]`,
+		"\"\xc3\x28\"",
+	}
+	for _, input := range strict {
+		if _, err := parser.ParseJSON(input); err == nil {
+			t.Errorf("ParseJSON(%q) debería fallar", input)
+		}
+		if err := parser.FastValidateJSON(input); err == nil {
+			t.Errorf("FastValidateJSON(%q) debería fallar", input)
+		}
+		if _, err := parser.ParseJSONWithOptions(input, ParseOptions{LenientStrings: true}); err != nil {
+			t.Errorf("ParseJSONWithOptions(%q, lenient) error = %v", input, err)
+		}
+	}
+
+	result, err := parser.ParseJSON(`{"emoji": "\ud83d\ude00", "texto": "ñ"}`)
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+	expected := map[string]interface{}{"emoji": "😀", "texto": "ñ"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ParseJSON() = %v, want %v", result, expected)
+	}
+
+	_, err = parser.ParseJSON("{\"a\": \"x\ty\"}")
+	syntaxErr := AsSyntaxError(err)
+	if syntaxErr == nil || syntaxErr.Offset != 8 {
+		t.Errorf("ParseJSON() error = %v, want SyntaxError en offset 8", err)
+	}
+}
+
 // Test para funciones de conveniencia
 func TestConvenienceFunctions(t *testing.T) {
 	// Test OptimizedParseJSON
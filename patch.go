@@ -0,0 +1,491 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation operación de un documento JSON Patch (RFC 6902)
+type PatchOperation struct {
+	Op    string      `json:"op"`             // add, remove, replace, move, copy o test
+	Path  string      `json:"path"`           // JSON Pointer destino
+	From  string      `json:"from,omitempty"` // origen de move y copy
+	Value interface{} `json:"value,omitempty"`
+}
+
+// PatchError error al aplicar (o interpretar) una operación del patch
+type PatchError struct {
+	Index   int           `json:"index"` // posición de la operación en el patch
+	Op      string        `json:"op"`
+	Path    string        `json:"path"`
+	Msg     string        `json:"message"`
+	Pointer *PointerError `json:"pointer_error,omitempty"` // error de ruta subyacente
+}
+
+// Error implementa la interfaz error
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("operación %d (%s): %s", e.Index, e.Op, e.Msg)
+}
+
+// Unwrap expone el PointerError subyacente a errors.As
+func (e *PatchError) Unwrap() error {
+	if e.Pointer == nil {
+		return nil
+	}
+	return e.Pointer
+}
+
+// AsPatchError extrae un *PatchError de la cadena de errores, si existe
+func AsPatchError(err error) *PatchError {
+	var patchErr *PatchError
+	if errors.As(err, &patchErr) {
+		return patchErr
+	}
+	return nil
+}
+
+// ParsePatch interpreta un documento JSON Patch ya parseado (un array de
+// objetos) y valida los miembros que exige cada operación
+func ParsePatch(value interface{}) ([]PatchOperation, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, &PatchError{Index: -1, Msg: fmt.Sprintf("el patch debe ser un array de operaciones, no %s", JSONTypeOf(value))}
+	}
+
+	ops := make([]PatchOperation, len(items))
+	for i, item := range items {
+		members := objectValues(item)
+		if members == nil {
+			return nil, &PatchError{Index: i, Msg: fmt.Sprintf("cada operación debe ser un objeto, no %s", JSONTypeOf(item))}
+		}
+
+		op := PatchOperation{}
+		field := func(name string, required bool) (string, error) {
+			raw, exists := members[name]
+			if !exists {
+				if required {
+					return "", &PatchError{Index: i, Op: op.Op, Path: op.Path, Msg: fmt.Sprintf("falta el miembro '%s'", name)}
+				}
+				return "", nil
+			}
+			text, isString := raw.(string)
+			if !isString {
+				return "", &PatchError{Index: i, Op: op.Op, Path: op.Path, Msg: fmt.Sprintf("el miembro '%s' debe ser un string", name)}
+			}
+			return text, nil
+		}
+
+		var err error
+		if op.Op, err = field("op", true); err != nil {
+			return nil, err
+		}
+		if op.Path, err = field("path", true); err != nil {
+			return nil, err
+		}
+
+		switch op.Op {
+		case "add", "replace", "test":
+			value, exists := members["value"]
+			if !exists {
+				return nil, &PatchError{Index: i, Op: op.Op, Path: op.Path, Msg: "falta el miembro 'value'"}
+			}
+			op.Value = value
+		case "move", "copy":
+			if op.From, err = field("from", true); err != nil {
+				return nil, err
+			}
+		case "remove":
+		default:
+			return nil, &PatchError{Index: i, Op: op.Op, Path: op.Path, Msg: fmt.Sprintf("operación desconocida '%s'", op.Op)}
+		}
+		ops[i] = op
+	}
+	return ops, nil
+}
+
+// ApplyPatch aplica las operaciones en orden sobre una copia del documento:
+// si alguna falla se devuelve el error y el original queda intacto
+func ApplyPatch(doc interface{}, patch []PatchOperation) (interface{}, error) {
+	result := cloneValue(doc)
+	for i, op := range patch {
+		var err error
+		if result, err = applyOperation(result, op); err != nil {
+			patchErr := &PatchError{Index: i, Op: op.Op, Path: op.Path, Msg: err.Error(), Pointer: AsPointerError(err)}
+			if patchErr.Pointer != nil {
+				patchErr.Msg = patchErr.Pointer.Msg
+				patchErr.Path = patchErr.Pointer.Pointer
+			}
+			return nil, patchErr
+		}
+	}
+	return result, nil
+}
+
+// applyOperation aplica una sola operación y devuelve la raíz resultante
+func applyOperation(doc interface{}, op PatchOperation) (interface{}, error) {
+	path, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		return patchAdd(doc, path, cloneValue(op.Value))
+	case "remove":
+		return path.Delete(doc)
+	case "replace":
+		if _, err := path.Get(doc); err != nil {
+			return nil, err
+		}
+		return path.Set(doc, cloneValue(op.Value))
+	case "move", "copy":
+		from, err := ParsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := from.Get(doc)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return patchAdd(doc, path, cloneValue(value))
+		}
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("no se puede mover '%s' dentro de sí mismo ('%s')", op.From, op.Path)
+		}
+		if doc, err = from.Delete(doc); err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, path, value)
+	case "test":
+		value, err := path.Get(doc)
+		if err != nil {
+			return nil, err
+		}
+		if !EqualValues(value, op.Value) {
+			return nil, fmt.Errorf("el valor en '%s' no coincide con el esperado", op.Path)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("operación desconocida '%s'", op.Op)
+}
+
+// patchAdd implementa "add": en objetos crea o reemplaza la clave y en arrays
+// inserta en el índice (desplazando el resto) o agrega al final con "-"
+func patchAdd(doc interface{}, path Pointer, value interface{}) (interface{}, error) {
+	return path.update(doc, func(container interface{}, depth int) (interface{}, error) {
+		token := path.tokens[depth]
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case *OrderedObject:
+			c.Set(token, value)
+			return c, nil
+		case []interface{}:
+			index, err := path.arrayIndex(c, depth, true)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]interface{}, 0, len(c)+1)
+			result = append(result, c[:index]...)
+			result = append(result, value)
+			return append(result, c[index:]...), nil
+		}
+		return nil, path.errorf(PointerNotContainer, depth, "no se puede agregar '%s' dentro de un valor de tipo %s", token, JSONTypeOf(container))
+	}, value)
+}
+
+// cloneValue copia en profundidad objetos y arrays; los escalares son inmutables
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			clone[key] = cloneValue(item)
+		}
+		return clone
+	case *OrderedObject:
+		clone := NewOrderedObject()
+		for _, key := range v.Keys {
+			clone.Set(key, cloneValue(v.Values[key]))
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	}
+	return value
+}
+
+// Diff genera un patch que transforma a en b: recorre objetos clave por clave
+// y alinea los arrays con la subsecuencia común más larga, de modo que solo
+// se emiten operaciones para lo que realmente cambió. Arrays demasiado
+// grandes y distintos entre sí se reemplazan completos
+func Diff(a, b interface{}) []PatchOperation {
+	return appendDiff(nil, nil, a, b)
+}
+
+// appendDiff agrega las operaciones que llevan a en b, situados en path
+func appendDiff(ops []PatchOperation, path []string, a, b interface{}) []PatchOperation {
+	if EqualValues(a, b) {
+		return ops
+	}
+
+	aObject, bObject := objectValues(a), objectValues(b)
+	if aObject != nil && bObject != nil {
+		for _, key := range orderedKeys(a) {
+			child := appendToken(path, key)
+			if bValue, exists := bObject[key]; exists {
+				ops = appendDiff(ops, child, aObject[key], bValue)
+			} else {
+				ops = append(ops, PatchOperation{Op: "remove", Path: FormatPointer(child)})
+			}
+		}
+		for _, key := range orderedKeys(b) {
+			if _, exists := aObject[key]; !exists {
+				ops = append(ops, PatchOperation{Op: "add", Path: FormatPointer(appendToken(path, key)), Value: cloneValue(bObject[key])})
+			}
+		}
+		return ops
+	}
+
+	aArray, aIsArray := a.([]interface{})
+	bArray, bIsArray := b.([]interface{})
+	if aIsArray && bIsArray {
+		return appendArrayDiff(ops, path, aArray, bArray)
+	}
+
+	return append(ops, PatchOperation{Op: "replace", Path: FormatPointer(path), Value: cloneValue(b)})
+}
+
+// appendArrayDiff compara arrays elemento a elemento sobre la subsecuencia
+// común más larga; en cada tramo distinto, los elementos eliminados e
+// insertados se emparejan como modificaciones y se comparan en profundidad.
+// Si los arrays son demasiado grandes para alinearlos se reemplazan completos
+func appendArrayDiff(ops []PatchOperation, path []string, a, b []interface{}) []PatchOperation {
+	hunks, ok := alignArrays(len(a), len(b), maxArrayAlignWork, func(i, j int) bool { return EqualValues(a[i], b[j]) })
+	if !ok {
+		return append(ops, PatchOperation{Op: "replace", Path: FormatPointer(path), Value: cloneValue(b)})
+	}
+
+	// index es la posición en el array a medida que se aplican las operaciones
+	index, i := 0, 0
+	for _, h := range hunks {
+		index += h.aStart - i
+		removed, added := h.aEnd-h.aStart, h.bEnd-h.bStart
+		paired := min(removed, added)
+		for k := 0; k < paired; k++ {
+			ops = appendDiff(ops, appendToken(path, strconv.Itoa(index)), a[h.aStart+k], b[h.bStart+k])
+			index++
+		}
+		for k := paired; k < removed; k++ {
+			ops = append(ops, PatchOperation{Op: "remove", Path: FormatPointer(appendToken(path, strconv.Itoa(index)))})
+		}
+		for k := paired; k < added; k++ {
+			token := strconv.Itoa(index)
+			if h.aEnd == len(a) {
+				token = "-" // al final del array
+			}
+			ops = append(ops, PatchOperation{Op: "add", Path: FormatPointer(appendToken(path, token)), Value: cloneValue(b[h.bStart+k])})
+			index++
+		}
+		i = h.aEnd
+	}
+	return ops
+}
+
+// maxArrayAlignWork presupuesto de comparaciones para alinear dos arrays; el
+// algoritmo de Myers cuesta O((n+m)·d), con d la cantidad de diferencias
+const maxArrayAlignWork = 1 << 23
+
+// arrayHunk tramo en que a[aStart:aEnd] se sustituye por b[bStart:bEnd]; lo
+// que queda entre tramos es común a ambos arrays
+type arrayHunk struct {
+	aStart, aEnd int
+	bStart, bEnd int
+}
+
+// alignArrays alinea dos arrays de n y m elementos sobre su subsecuencia
+// común más larga según equal(i, j) y devuelve los tramos distintos en orden.
+// Recorta prefijo y sufijo comunes y aplica el algoritmo de Myers en espacio
+// lineal; devuelve false si la alineación agota el presupuesto de comparaciones
+func alignArrays(n, m, budget int, equal func(i, j int) bool) ([]arrayHunk, bool) {
+	prefix := 0
+	for prefix < n && prefix < m && equal(prefix, prefix) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && equal(n-1-suffix, m-1-suffix) {
+		suffix++
+	}
+
+	al := &arrayAligner{same: equal, budget: budget}
+	al.compare(prefix, n-suffix, prefix, m-suffix)
+	if al.budget < 0 {
+		return nil, false
+	}
+	return al.hunks, true
+}
+
+// arrayAligner acumula los tramos distintos del algoritmo de Myers
+type arrayAligner struct {
+	same   func(i, j int) bool // igualdad entre a[i] y b[j]
+	hunks  []arrayHunk
+	budget int // comparaciones restantes; negativo si se agotó
+}
+
+// equal compara a[i] con b[j] descontando del presupuesto
+func (al *arrayAligner) equal(i, j int) bool {
+	al.budget--
+	return al.budget >= 0 && al.same(i, j)
+}
+
+// compare alinea a[aLo:aHi] con b[bLo:bHi]; los tramos se agregan en orden
+func (al *arrayAligner) compare(aLo, aHi, bLo, bHi int) {
+	if al.budget < 0 {
+		return
+	}
+	for aLo < aHi && bLo < bHi && al.same(aLo, bLo) {
+		aLo, bLo = aLo+1, bLo+1
+	}
+	for aLo < aHi && bLo < bHi && al.same(aHi-1, bHi-1) {
+		aHi, bHi = aHi-1, bHi-1
+	}
+	if aLo == aHi || bLo == bHi {
+		al.addHunk(aLo, aHi, bLo, bHi)
+		return
+	}
+
+	x, y, found := al.middleSnake(aLo, aHi, bLo, bHi)
+	if al.budget < 0 {
+		return
+	}
+	if !found {
+		al.addHunk(aLo, aHi, bLo, bHi)
+		return
+	}
+	al.compare(aLo, x, bLo, y)
+	al.compare(x, aHi, y, bHi)
+}
+
+// addHunk agrega un tramo distinto, fusionándolo con el anterior si son contiguos
+func (al *arrayAligner) addHunk(aLo, aHi, bLo, bHi int) {
+	if aLo == aHi && bLo == bHi {
+		return
+	}
+	if last := len(al.hunks) - 1; last >= 0 && al.hunks[last].aEnd == aLo && al.hunks[last].bEnd == bLo {
+		al.hunks[last].aEnd, al.hunks[last].bEnd = aHi, bHi
+		return
+	}
+	al.hunks = append(al.hunks, arrayHunk{aStart: aLo, aEnd: aHi, bStart: bLo, bEnd: bHi})
+}
+
+// middleSnake busca un punto de un camino de edición mínimo avanzando a la
+// vez desde el inicio y desde el final (Myers, 1986); usa memoria O(n+m)
+func (al *arrayAligner) middleSnake(aLo, aHi, bLo, bHi int) (int, int, bool) {
+	n, m := aHi-aLo, bHi-bLo
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for k := range forward {
+		forward[k], backward[k] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	odd := delta%2 != 0
+	kStart, kEnd, rStart, rEnd := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		if al.budget -= 2*d + 1; al.budget < 0 {
+			return 0, 0, false
+		}
+		for k := -d + kStart; k <= d-kEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && al.equal(aLo+x, bLo+y) {
+				x, y = x+1, y+1
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				kEnd += 2
+			case y > m:
+				kStart += 2
+			case odd:
+				if r := offset + delta - k; r >= 0 && r < len(backward) && backward[r] != -1 && x >= n-backward[r] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+
+		for k := -d + rStart; k <= d-rEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && al.equal(aHi-1-x, bHi-1-y) {
+				x, y = x+1, y+1
+			}
+			backward[offset+k] = x
+			switch {
+			case x > n:
+				rEnd += 2
+			case y > m:
+				rStart += 2
+			case !odd:
+				if f := offset + delta - k; f >= 0 && f < len(forward) && forward[f] != -1 {
+					fx := forward[f]
+					fy := offset + fx - f
+					if fx >= n-x {
+						return aLo + fx, bLo + fy, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// appendToken devuelve una copia de path con un segmento más
+func appendToken(path []string, token string) []string {
+	result := make([]string, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, token)
+}
+
+// PatchValue convierte un patch al árbol de valores para serializarlo con
+// el Encoder, con los miembros en el orden habitual de RFC 6902
+func PatchValue(patch []PatchOperation) []interface{} {
+	items := make([]interface{}, len(patch))
+	for i, op := range patch {
+		item := NewOrderedObject()
+		item.Set("op", op.Op)
+		if op.Op == "move" || op.Op == "copy" {
+			item.Set("from", op.From)
+		}
+		item.Set("path", op.Path)
+		if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+			item.Set("value", op.Value)
+		}
+		items[i] = item
+	}
+	return items
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// parseExact parsea conservando orden y números exactos, como los endpoints
+func parseExact(t *testing.T, input string) interface{} {
+	t.Helper()
+	value, err := NewParser().ParseJSONWithOptions(input, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	if err != nil {
+		t.Fatalf("ParseJSONWithOptions(%s) error = %v", input, err)
+	}
+	return value
+}
+
+// Test para los ejemplos del apéndice A de RFC 6902
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"add a objeto", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`},
+		{"add en array", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"remove de objeto", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove de array", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"move", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move en array", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"copy", `{"a":{"b":[1]}}`, `[{"op":"copy","from":"/a/b","path":"/c"}]`, `{"a":{"b":[1]},"c":[1]}`},
+		{"test exitoso", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{"add objeto anidado", `{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{"add al final con -", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{"add reemplaza la raíz", `{"foo":"bar"}`, `[{"op":"add","path":"","value":[1]}]`, `[1]`},
+		{"clave ~1 escapada", `{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := ParsePatch(parseExact(t, tt.patch))
+			if err != nil {
+				t.Fatalf("ParsePatch() error = %v", err)
+			}
+			result, err := ApplyPatch(parseExact(t, tt.doc), patch)
+			if err != nil {
+				t.Fatalf("ApplyPatch() error = %v", err)
+			}
+			if got, _ := Marshal(result); string(got) != tt.want {
+				t.Errorf("ApplyPatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test para los errores al aplicar un patch
+func TestApplyPatchErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		index int
+		want  string
+	}{
+		{"test fallido", `{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, 0, "no coincide"},
+		{"clave inexistente", `{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, 0, "no existe"},
+		{"replace inexistente", `{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, 0, "no existe"},
+		{"índice fuera de rango", `{"foo":[1]}`, `[{"op":"test","path":"/foo/0","value":1},{"op":"add","path":"/foo/5","value":2}]`, 1, "fuera de rango"},
+		{"move dentro de sí mismo", `{"a":{"b":{}}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`, 0, "dentro de sí mismo"},
+		{"remove inexistente", `{"a":1}`, `[{"op":"remove","path":"/b"}]`, 0, "no existe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseExact(t, tt.doc)
+			patch, err := ParsePatch(parseExact(t, tt.patch))
+			if err != nil {
+				t.Fatalf("ParsePatch() error = %v", err)
+			}
+			_, err = ApplyPatch(doc, patch)
+			patchErr := AsPatchError(err)
+			if patchErr == nil || patchErr.Index != tt.index || !strings.Contains(patchErr.Msg, tt.want) {
+				t.Fatalf("ApplyPatch() error = %v, want índice %d con %q", err, tt.index, tt.want)
+			}
+
+			// El documento original no se modifica aunque el patch falle
+			if got, _ := Marshal(doc); string(got) != string(mustMarshal(t, parseExact(t, tt.doc))) {
+				t.Errorf("el documento original cambió: %s", got)
+			}
+		})
+	}
+}
+
+// mustMarshal serializa en forma compacta o termina el test
+func mustMarshal(t *testing.T, value interface{}) []byte {
+	t.Helper()
+	data, err := Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	return data
+}
+
+// Test para la validación de documentos de patch
+func TestParsePatchErrors(t *testing.T) {
+	tests := []struct {
+		patch string
+		want  string
+	}{
+		{`{"op":"add"}`, "debe ser un array"},
+		{`[1]`, "debe ser un objeto"},
+		{`[{"path":"/a"}]`, "falta el miembro 'op'"},
+		{`[{"op":"add","path":"/a"}]`, "falta el miembro 'value'"},
+		{`[{"op":"move","path":"/a"}]`, "falta el miembro 'from'"},
+		{`[{"op":"remove","path":1}]`, "debe ser un string"},
+		{`[{"op":"merge","path":"/a"}]`, "operación desconocida"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.patch, func(t *testing.T) {
+			_, err := ParsePatch(parseExact(t, tt.patch))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParsePatch() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// Test para la generación de patches: aplicar Diff(a, b) sobre a produce b
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"iguales", `{"a":[1,2],"b":{"c":1}}`, `{"b":{"c":1.0},"a":[1,2]}`, `[]`},
+		{"escalar", `{"a":1}`, `{"a":2}`, `[{"op":"replace","path":"/a","value":2}]`},
+		{"claves", `{"a":1,"b":2}`, `{"b":2,"c":3}`, `[{"op":"remove","path":"/a"},{"op":"add","path":"/c","value":3}]`},
+		{"anidado", `{"x":{"y":{"z":1,"w":2}}}`, `{"x":{"y":{"z":1,"w":3}}}`, `[{"op":"replace","path":"/x/y/w","value":3}]`},
+		{"inserción en array", `[1,2,3]`, `[1,9,2,3]`, `[{"op":"add","path":"/1","value":9}]`},
+		{"agregar al final", `[1,2]`, `[1,2,3]`, `[{"op":"add","path":"/-","value":3}]`},
+		{"eliminación en array", `[1,2,3,4]`, `[1,3]`, `[{"op":"remove","path":"/1"},{"op":"remove","path":"/2"}]`},
+		{"modificación en array", `[{"id":1,"v":"a"},{"id":2,"v":"b"}]`, `[{"id":1,"v":"a"},{"id":2,"v":"c"}]`, `[{"op":"replace","path":"/1/v","value":"c"}]`},
+		{"cambio de tipo", `{"a":[1]}`, `{"a":{"0":1}}`, `[{"op":"replace","path":"/a","value":{"0":1}}]`},
+		{"raíz", `1`, `"x"`, `[{"op":"replace","path":"","value":"x"}]`},
+		{"clave con /", `{"a/b":1}`, `{"a/b":2}`, `[{"op":"replace","path":"/a~1b","value":2}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := parseExact(t, tt.a), parseExact(t, tt.b)
+			patch := Diff(a, b)
+			if got := string(mustMarshal(t, PatchValue(patch))); got != tt.want {
+				t.Errorf("Diff() = %s, want %s", got, tt.want)
+			}
+
+			result, err := ApplyPatch(a, patch)
+			if err != nil {
+				t.Fatalf("ApplyPatch(Diff()) error = %v", err)
+			}
+			if !EqualValues(result, b) {
+				t.Errorf("ApplyPatch(Diff()) = %s, want %s", mustMarshal(t, result), tt.b)
+			}
+		})
+	}
+}
+
+// Test de ida y vuelta sobre arrays reordenados
+func TestDiffArrayRoundTrip(t *testing.T) {
+	pairs := [][2]string{
+		{`[1,2,3,4,5]`, `[5,4,3,2,1]`},
+		{`["a","b","c"]`, `[]`},
+		{`[]`, `["a","b"]`},
+		{`[1,[2,3],{"k":[4]}]`, `[[2,3,5],{"k":[4,6]},7]`},
+		{`{"list":[{"a":1},{"b":2},{"c":3}]}`, `{"list":[{"b":2},{"c":4},{"d":5}]}`},
+	}
+
+	for _, pair := range pairs {
+		a, b := parseExact(t, pair[0]), parseExact(t, pair[1])
+		result, err := ApplyPatch(a, Diff(a, b))
+		if err != nil {
+			t.Fatalf("ApplyPatch(Diff(%s, %s)) error = %v", pair[0], pair[1], err)
+		}
+		if !EqualValues(result, b) {
+			t.Errorf("ApplyPatch(Diff(%s, %s)) = %s", pair[0], pair[1], mustMarshal(t, result))
+		}
+	}
+}
+
+// Test de la alineación de arrays: la parte común coincide con la subsecuencia
+// común más larga calculada con programación dinámica
+func TestAlignArraysIsMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 300; round++ {
+		a := make([]interface{}, rng.Intn(12))
+		b := make([]interface{}, rng.Intn(12))
+		for i := range a {
+			a[i] = float64(rng.Intn(4))
+		}
+		for i := range b {
+			b[i] = float64(rng.Intn(4))
+		}
+
+		hunks, ok := alignArrays(len(a), len(b), maxArrayAlignWork, func(i, j int) bool { return EqualValues(a[i], b[j]) })
+		if !ok {
+			t.Fatalf("alignArrays(%v, %v) no alineó", a, b)
+		}
+		changed := 0
+		for _, h := range hunks {
+			changed += h.aEnd - h.aStart
+		}
+		if common, want := len(a)-changed, lcsLength(a, b); common != want {
+			t.Errorf("alignArrays(%v, %v) = %v: %d comunes, want %d", a, b, hunks, common, want)
+		}
+
+		result, err := ApplyPatch(a, Diff(a, b))
+		if err != nil || !EqualValues(result, b) {
+			t.Errorf("ApplyPatch(Diff(%v, %v)) = %v, %v", a, b, result, err)
+		}
+	}
+}
+
+// lcsLength largo de la subsecuencia común más larga, en tiempo cuadrático
+func lcsLength(a, b []interface{}) int {
+	row := make([]int, len(b)+1)
+	for i := range a {
+		diagonal := 0
+		for j := range b {
+			next := row[j+1]
+			if EqualValues(a[i], b[j]) {
+				row[j+1] = diagonal + 1
+			} else if row[j] > row[j+1] {
+				row[j+1] = row[j]
+			}
+			diagonal = next
+		}
+	}
+	return row[len(b)]
+}
+
+// Test para arrays grandes: pocos cambios se alinean y arrays totalmente
+// distintos se reemplazan completos
+func TestDiffLargeArrays(t *testing.T) {
+	const size = 5000
+	a := make([]interface{}, size)
+	b := make([]interface{}, size)
+	for i := range a {
+		a[i] = float64(i)
+		b[i] = float64(i + size)
+	}
+
+	edited := append([]interface{}{}, a...)
+	edited = append(edited[:100], edited[101:]...)
+	edited = append(edited[:2500], append([]interface{}{"x"}, edited[2500:]...)...)
+	patch := Diff(a, edited)
+	if got := string(mustMarshal(t, PatchValue(patch))); got != `[{"op":"remove","path":"/100"},{"op":"add","path":"/2500","value":"x"}]` {
+		t.Errorf("Diff() = %s", got)
+	}
+	if result, err := ApplyPatch(a, patch); err != nil || !EqualValues(result, edited) {
+		t.Errorf("ApplyPatch(Diff()) error = %v", err)
+	}
+
+	patch = Diff(a, b)
+	if len(patch) != 1 || patch[0].Op != "replace" || patch[0].Path != "" {
+		t.Errorf("Diff() de arrays distintos = %d operaciones, want un replace de la raíz", len(patch))
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tipos de error de JSON Pointer
+const (
+	PointerSyntax       = "syntax"             // el puntero no cumple RFC 6901
+	PointerNotFound     = "not_found"          // la clave no existe en el objeto
+	PointerInvalidIndex = "invalid_index"      // el segmento no es un índice de array válido
+	PointerOutOfRange   = "index_out_of_range" // el índice no existe en el array
+	PointerNotContainer = "not_container"      // se intentó descender dentro de un escalar
+)
+
+// PointerError error al resolver un JSON Pointer, con el segmento que falló
+type PointerError struct {
+	Kind    string `json:"kind"`              // uno de los tipos Pointer*
+	Pointer string `json:"pointer"`           // puntero completo
+	Segment string `json:"segment,omitempty"` // segmento (ya decodificado) que falló
+	Depth   int    `json:"depth"`             // posición del segmento, comenzando en 0
+	Msg     string `json:"message"`           // descripción del problema
+}
+
+// Error implementa la interfaz error
+func (e *PointerError) Error() string {
+	return fmt.Sprintf("puntero %q: %s", e.Pointer, e.Msg)
+}
+
+// AsPointerError extrae un *PointerError de la cadena de errores, si existe
+func AsPointerError(err error) *PointerError {
+	var pointerErr *PointerError
+	if errors.As(err, &pointerErr) {
+		return pointerErr
+	}
+	return nil
+}
+
+// Escapes de los segmentos de un JSON Pointer (~0 para '~', ~1 para '/')
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// Pointer JSON Pointer (RFC 6901) ya dividido en segmentos decodificados
+type Pointer struct {
+	raw    string
+	tokens []string
+}
+
+// ParsePointer interpreta un JSON Pointer: "" es la raíz y cada segmento
+// comienza con '/', con ~1 para '/' y ~0 para '~'
+func ParsePointer(pointer string) (Pointer, error) {
+	if pointer == "" {
+		return Pointer{}, nil
+	}
+	if pointer[0] != '/' {
+		return Pointer{}, &PointerError{Kind: PointerSyntax, Pointer: pointer, Msg: "debe comenzar con '/' (o ser vacío para la raíz)"}
+	}
+
+	parts := strings.Split(pointer[1:], "/")
+	tokens := make([]string, len(parts))
+	for i, part := range parts {
+		for j := 0; j < len(part); j++ {
+			if part[j] == '~' && (j+1 >= len(part) || (part[j+1] != '0' && part[j+1] != '1')) {
+				return Pointer{}, &PointerError{Kind: PointerSyntax, Pointer: pointer, Segment: part, Depth: i, Msg: fmt.Sprintf("escape inválido en el segmento '%s' (use ~0 o ~1)", part)}
+			}
+		}
+		tokens[i] = pointerUnescaper.Replace(part)
+	}
+	return Pointer{raw: pointer, tokens: tokens}, nil
+}
+
+// NewPointer construye un puntero a partir de segmentos sin escapar
+func NewPointer(tokens ...string) Pointer {
+	return Pointer{raw: FormatPointer(tokens), tokens: tokens}
+}
+
+// FormatPointer compone la representación textual de una lista de segmentos
+func FormatPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(token))
+	}
+	return sb.String()
+}
+
+// String devuelve el puntero en su forma textual
+func (p Pointer) String() string {
+	return p.raw
+}
+
+// Tokens devuelve los segmentos decodificados
+func (p Pointer) Tokens() []string {
+	return p.tokens
+}
+
+// Append devuelve un puntero hijo con un segmento más
+func (p Pointer) Append(token string) Pointer {
+	tokens := make([]string, len(p.tokens), len(p.tokens)+1)
+	copy(tokens, p.tokens)
+	return NewPointer(append(tokens, token)...)
+}
+
+// errorf construye un PointerError para el segmento en la posición depth
+func (p Pointer) errorf(kind string, depth int, format string, args ...interface{}) error {
+	segment := ""
+	if depth < len(p.tokens) {
+		segment = p.tokens[depth]
+	}
+	return &PointerError{Kind: kind, Pointer: p.raw, Segment: segment, Depth: depth, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Get devuelve el valor al que apunta el puntero
+func (p Pointer) Get(doc interface{}) (interface{}, error) {
+	node := doc
+	for depth, token := range p.tokens {
+		switch container := node.(type) {
+		case map[string]interface{}, *OrderedObject:
+			value, exists := objectValues(container)[token]
+			if !exists {
+				return nil, p.errorf(PointerNotFound, depth, "la clave '%s' no existe", token)
+			}
+			node = value
+		case []interface{}:
+			index, err := p.arrayIndex(container, depth, false)
+			if err != nil {
+				return nil, err
+			}
+			node = container[index]
+		default:
+			return nil, p.errorf(PointerNotContainer, depth, "no se puede acceder a '%s' dentro de un valor de tipo %s", token, JSONTypeOf(node))
+		}
+	}
+	return node, nil
+}
+
+// Set asigna value en la posición del puntero: en objetos crea o reemplaza la
+// clave y en arrays reemplaza el elemento, o agrega al final con el índice
+// len o "-". Los objetos se modifican en el lugar; devuelve la raíz resultante.
+func (p Pointer) Set(doc, value interface{}) (interface{}, error) {
+	return p.update(doc, func(container interface{}, depth int) (interface{}, error) {
+		token := p.tokens[depth]
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case *OrderedObject:
+			c.Set(token, value)
+			return c, nil
+		case []interface{}:
+			index, err := p.arrayIndex(c, depth, true)
+			if err != nil {
+				return nil, err
+			}
+			if index == len(c) {
+				return append(c, value), nil
+			}
+			c[index] = value
+			return c, nil
+		}
+		return nil, p.errorf(PointerNotContainer, depth, "no se puede asignar '%s' dentro de un valor de tipo %s", token, JSONTypeOf(container))
+	}, value)
+}
+
+// Delete elimina el valor al que apunta el puntero y devuelve la raíz
+// resultante (nil si se elimina la raíz)
+func (p Pointer) Delete(doc interface{}) (interface{}, error) {
+	return p.update(doc, func(container interface{}, depth int) (interface{}, error) {
+		token := p.tokens[depth]
+		switch c := container.(type) {
+		case map[string]interface{}, *OrderedObject:
+			if _, exists := objectValues(c)[token]; !exists {
+				return nil, p.errorf(PointerNotFound, depth, "la clave '%s' no existe", token)
+			}
+			if ordered, ok := c.(*OrderedObject); ok {
+				ordered.Delete(token)
+			} else {
+				delete(c.(map[string]interface{}), token)
+			}
+			return c, nil
+		case []interface{}:
+			index, err := p.arrayIndex(c, depth, false)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]interface{}, 0, len(c)-1)
+			result = append(result, c[:index]...)
+			return append(result, c[index+1:]...), nil
+		}
+		return nil, p.errorf(PointerNotContainer, depth, "no se puede eliminar '%s' dentro de un valor de tipo %s", token, JSONTypeOf(container))
+	}, nil)
+}
+
+// update recorre hasta el contenedor padre del último segmento, aplica op y
+// reconstruye el camino (los arrays pueden cambiar de tamaño). Con el puntero
+// raíz devuelve rootValue.
+func (p Pointer) update(doc interface{}, op func(container interface{}, depth int) (interface{}, error), rootValue interface{}) (interface{}, error) {
+	if len(p.tokens) == 0 {
+		return rootValue, nil
+	}
+	return p.updateAt(doc, 0, op)
+}
+
+func (p Pointer) updateAt(node interface{}, depth int, op func(container interface{}, depth int) (interface{}, error)) (interface{}, error) {
+	if depth == len(p.tokens)-1 {
+		return op(node, depth)
+	}
+
+	token := p.tokens[depth]
+	switch container := node.(type) {
+	case map[string]interface{}, *OrderedObject:
+		child, exists := objectValues(container)[token]
+		if !exists {
+			return nil, p.errorf(PointerNotFound, depth, "la clave '%s' no existe", token)
+		}
+		updated, err := p.updateAt(child, depth+1, op)
+		if err != nil {
+			return nil, err
+		}
+		objectValues(container)[token] = updated
+		return container, nil
+	case []interface{}:
+		index, err := p.arrayIndex(container, depth, false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := p.updateAt(container[index], depth+1, op)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = updated
+		return container, nil
+	}
+	return nil, p.errorf(PointerNotContainer, depth, "no se puede acceder a '%s' dentro de un valor de tipo %s", token, JSONTypeOf(node))
+}
+
+// arrayIndex valida el segmento como índice de array según RFC 6901: dígitos
+// sin ceros a la izquierda, o "-" (el elemento después del último). Con
+// allowEnd se acepta len(array) para agregar al final.
+func (p Pointer) arrayIndex(array []interface{}, depth int, allowEnd bool) (int, error) {
+	token := p.tokens[depth]
+	if token == "-" {
+		if allowEnd {
+			return len(array), nil
+		}
+		return 0, p.errorf(PointerOutOfRange, depth, "'-' apunta después del último elemento (el array tiene %d)", len(array))
+	}
+
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, p.errorf(PointerInvalidIndex, depth, "'%s' no es un índice de array válido", token)
+	}
+	index, err := strconv.Atoi(token)
+	limit := len(array)
+	if allowEnd {
+		limit++
+	}
+	if err != nil || index >= limit {
+		return 0, p.errorf(PointerOutOfRange, depth, "índice %s fuera de rango (el array tiene %d elementos)", token, len(array))
+	}
+	return index, nil
+}
+
+// PointerGet función de conveniencia: resuelve un puntero textual
+func PointerGet(doc interface{}, pointer string) (interface{}, error) {
+	p, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get(doc)
+}
+
+// PointerSet función de conveniencia: asigna un valor en un puntero textual
+func PointerSet(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	p, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return p.Set(doc, value)
+}
+
+// PointerDelete función de conveniencia: elimina el valor de un puntero textual
+func PointerDelete(doc interface{}, pointer string) (interface{}, error) {
+	p, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return p.Delete(doc)
+}
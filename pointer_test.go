@@ -0,0 +1,157 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// rfc6901Document documento de ejemplo de la sección 5 de RFC 6901
+const rfc6901Document = `{
+  "foo": ["bar", "baz"],
+  "": 0,
+  "a/b": 1,
+  "c%d": 2,
+  "e^f": 3,
+  "g|h": 4,
+  "i\\j": 5,
+  "k\"l": 6,
+  " ": 7,
+  "m~n": 8
+}`
+
+// Test con los ejemplos de RFC 6901
+func TestPointerGet(t *testing.T) {
+	doc, err := NewParser().ParseJSON(rfc6901Document)
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+
+	tests := []struct {
+		pointer string
+		want    interface{}
+	}{
+		{"/foo", []interface{}{"bar", "baz"}},
+		{"/foo/0", "bar"},
+		{"/", 0.0},
+		{"/a~1b", 1.0},
+		{"/c%d", 2.0},
+		{"/e^f", 3.0},
+		{"/g|h", 4.0},
+		{"/i\\j", 5.0},
+		{"/k\"l", 6.0},
+		{"/ ", 7.0},
+		{"/m~0n", 8.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			got, err := PointerGet(doc, tt.pointer)
+			if err != nil {
+				t.Fatalf("PointerGet() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PointerGet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if root, err := PointerGet(doc, ""); err != nil || !reflect.DeepEqual(root, doc) {
+		t.Errorf("PointerGet(\"\") = %v, %v, want el documento completo", root, err)
+	}
+}
+
+// Test para los errores tipados
+func TestPointerErrors(t *testing.T) {
+	doc, _ := NewParser().ParseJSON(`{"a": {"b": [10, 20]}, "s": "texto"}`)
+
+	tests := []struct {
+		pointer string
+		kind    string
+		segment string
+		depth   int
+	}{
+		{"a", PointerSyntax, "", 0},
+		{"/a/b~2", PointerSyntax, "b~2", 1},
+		{"/x", PointerNotFound, "x", 0},
+		{"/a/c/d", PointerNotFound, "c", 1},
+		{"/a/b/01", PointerInvalidIndex, "01", 2},
+		{"/a/b/uno", PointerInvalidIndex, "uno", 2},
+		{"/a/b/-1", PointerInvalidIndex, "-1", 2},
+		{"/a/b/2", PointerOutOfRange, "2", 2},
+		{"/a/b/-", PointerOutOfRange, "-", 2},
+		{"/s/0", PointerNotContainer, "0", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			_, err := PointerGet(doc, tt.pointer)
+			pointerErr := AsPointerError(err)
+			if pointerErr == nil {
+				t.Fatalf("PointerGet() error = %v, want *PointerError", err)
+			}
+			if pointerErr.Kind != tt.kind || pointerErr.Segment != tt.segment || pointerErr.Depth != tt.depth {
+				t.Errorf("PointerGet() error = %+v, want kind=%s segment=%q depth=%d", pointerErr, tt.kind, tt.segment, tt.depth)
+			}
+		})
+	}
+}
+
+// Test para Set y Delete sobre mapas, objetos ordenados y arrays
+func TestPointerSetDelete(t *testing.T) {
+	parser := NewParser()
+	for _, opts := range []ParseOptions{{}, {PreserveOrder: true}} {
+		doc, _ := parser.ParseJSONWithOptions(`{"a": {"b": [1, 2]}, "c": true}`, opts)
+
+		steps := []struct {
+			op      string
+			pointer string
+			value   interface{}
+		}{
+			{"set", "/a/b/0", "uno"},
+			{"set", "/a/b/-", 3.0},
+			{"set", "/a/b/3", 4.0},
+			{"set", "/d", nil},
+			{"delete", "/c", nil},
+			{"delete", "/a/b/1", nil},
+		}
+
+		var err error
+		for _, step := range steps {
+			if step.op == "set" {
+				doc, err = PointerSet(doc, step.pointer, step.value)
+			} else {
+				doc, err = PointerDelete(doc, step.pointer)
+			}
+			if err != nil {
+				t.Fatalf("%s %s error = %v", step.op, step.pointer, err)
+			}
+		}
+
+		want, _ := parser.ParseJSON(`{"a": {"b": ["uno", 3, 4]}, "d": null}`)
+		if !EqualValues(doc, want) {
+			t.Errorf("resultado (%+v) = %v, want %v", opts, unorderValue(doc), want)
+		}
+
+		if _, err := PointerSet(doc, "/a/b/9", 0.0); AsPointerError(err) == nil || AsPointerError(err).Kind != PointerOutOfRange {
+			t.Errorf("PointerSet() fuera de rango error = %v", err)
+		}
+		if _, err := PointerDelete(doc, "/a/x"); AsPointerError(err) == nil || AsPointerError(err).Kind != PointerNotFound {
+			t.Errorf("PointerDelete() clave inexistente error = %v", err)
+		}
+		if root, err := PointerSet(doc, "", "nuevo"); err != nil || root != "nuevo" {
+			t.Errorf("PointerSet(\"\") = %v, %v", root, err)
+		}
+	}
+}
+
+// Test para la composición de punteros con escapes
+func TestFormatPointer(t *testing.T) {
+	p := NewPointer("a/b", "m~n").Append("0")
+	if p.String() != "/a~1b/m~0n/0" {
+		t.Errorf("String() = %s, want /a~1b/m~0n/0", p)
+	}
+	parsed, err := ParsePointer(p.String())
+	if err != nil || !reflect.DeepEqual(parsed.Tokens(), []string{"a/b", "m~n", "0"}) {
+		t.Errorf("ParsePointer() = %v, %v", parsed.Tokens(), err)
+	}
+}
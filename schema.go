@@ -0,0 +1,632 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSchemaDepth límite de anidamiento al validar; protege la pila ante
+// esquemas recursivos que sí avanzan sobre la instancia
+const maxSchemaDepth = 512
+
+// maxSchemaEvaluations presupuesto de (sub)esquemas evaluados por llamada a
+// Validate; acota esquemas cuyas $ref se ramifican exponencialmente
+const maxSchemaEvaluations = 1 << 20
+
+// SchemaError error al compilar un esquema
+type SchemaError struct {
+	Path string `json:"path"` // ubicación en el esquema (JSON Pointer)
+	Msg  string `json:"message"`
+}
+
+// Error implementa la interfaz error
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("esquema inválido en '%s': %s", e.Path, e.Msg)
+}
+
+// AsSchemaError extrae un *SchemaError de la cadena de errores, si existe
+func AsSchemaError(err error) *SchemaError {
+	var schemaErr *SchemaError
+	if errors.As(err, &schemaErr) {
+		return schemaErr
+	}
+	return nil
+}
+
+// SchemaViolation una regla del esquema que la instancia no cumple
+type SchemaViolation struct {
+	InstancePath string `json:"instance_path"` // JSON Pointer del valor que falla
+	SchemaPath   string `json:"schema_path"`   // JSON Pointer de la palabra clave
+	Keyword      string `json:"keyword"`
+	Msg          string `json:"message"`
+}
+
+// Schema JSON Schema (draft 2020-12) compilado; es de solo lectura y puede
+// validar varias instancias
+type Schema struct {
+	root *schemaNode
+}
+
+// schemaNode palabras clave compiladas de un (sub)esquema
+type schemaNode struct {
+	path    string // ubicación absoluta dentro del esquema
+	boolean *bool  // esquemas true / false
+
+	types      []string
+	enum       []interface{}
+	hasEnum    bool
+	constValue interface{}
+	hasConst   bool
+
+	properties           map[string]*schemaNode
+	additionalProperties *schemaNode
+	required             []string
+
+	pattern   *regexp.Regexp
+	minLength int
+	maxLength int
+
+	minimum          *numericValue
+	maximum          *numericValue
+	exclusiveMinimum *numericValue
+	exclusiveMaximum *numericValue
+	multipleOf       *numericValue
+
+	prefixItems []*schemaNode
+	items       *schemaNode
+	minItems    int
+	maxItems    int
+	uniqueItems bool
+
+	ref     string // puntero local destino de $ref
+	refNode *schemaNode
+
+	allOf []*schemaNode
+	anyOf []*schemaNode
+	oneOf []*schemaNode
+	not   *schemaNode
+}
+
+// schemaVisit un (sub)esquema evaluándose sobre una ubicación de la instancia.
+// Las evaluaciones en curso forman una sola cadena de llamadas, así que sus
+// ubicaciones son prefijos unas de otras y basta con la longitud de la ruta
+type schemaVisit struct {
+	node  *schemaNode
+	level int
+}
+
+// schemaRun estado de una llamada a Validate: evaluaciones en curso (para
+// detectar ciclos de $ref que no avanzan sobre la instancia) y presupuesto
+type schemaRun struct {
+	active      map[schemaVisit]bool
+	depth       int
+	evaluations int
+	exhausted   bool
+}
+
+// schemaCompiler compila un esquema y resuelve sus $ref locales
+type schemaCompiler struct {
+	root    interface{}
+	nodes   map[string]*schemaNode
+	pending []*schemaNode
+}
+
+// CompileSchema compila un esquema ya parseado (por ejemplo con ParseJSON)
+func CompileSchema(schema interface{}) (*Schema, error) {
+	c := &schemaCompiler{root: schema, nodes: map[string]*schemaNode{}}
+	root, err := c.compile(schema, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Las referencias se resuelven al final: pueden apuntar a cualquier parte
+	for len(c.pending) > 0 {
+		node := c.pending[0]
+		c.pending = c.pending[1:]
+		target, err := c.resolve(node)
+		if err != nil {
+			return nil, err
+		}
+		node.refNode = target
+	}
+	return &Schema{root: root}, nil
+}
+
+// Validate devuelve todas las violaciones; una lista vacía indica instancia válida
+func (s *Schema) Validate(instance interface{}) []SchemaViolation {
+	violations := []SchemaViolation{}
+	run := &schemaRun{active: map[schemaVisit]bool{}}
+	s.root.validate(instance, nil, &violations, run)
+	if run.exhausted {
+		violations = append(violations, SchemaViolation{
+			InstancePath: "",
+			SchemaPath:   s.root.path,
+			Keyword:      "$ref",
+			Msg:          fmt.Sprintf("se agotó el presupuesto de %d evaluaciones; la validación quedó incompleta", maxSchemaEvaluations),
+		})
+	}
+	return violations
+}
+
+// ValidateSchema función de conveniencia: compila y valida en un paso
+func ValidateSchema(schema, instance interface{}) ([]SchemaViolation, error) {
+	compiled, err := CompileSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Validate(instance), nil
+}
+
+// ===== Compilación =====
+
+func (c *schemaCompiler) errorf(path, format string, args ...interface{}) error {
+	return &SchemaError{Path: path, Msg: fmt.Sprintf(format, args...)}
+}
+
+// compile traduce un (sub)esquema ubicado en path
+func (c *schemaCompiler) compile(value interface{}, path string) (*schemaNode, error) {
+	node := &schemaNode{path: path, minLength: -1, maxLength: -1, minItems: -1, maxItems: -1}
+	c.nodes[path] = node
+
+	if b, ok := value.(bool); ok {
+		node.boolean = &b
+		return node, nil
+	}
+	members := objectValues(value)
+	if members == nil {
+		return nil, c.errorf(path, "un esquema debe ser un objeto o un booleano, no %s", JSONTypeOf(value))
+	}
+
+	for _, keyword := range orderedKeys(value) {
+		if err := c.compileKeyword(node, keyword, members[keyword]); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// compileKeyword interpreta una palabra clave; las desconocidas se ignoran
+// (en 2020-12 son anotaciones)
+func (c *schemaCompiler) compileKeyword(node *schemaNode, keyword string, value interface{}) error {
+	path := node.path + "/" + escapePointerToken(keyword)
+	var err error
+
+	switch keyword {
+	case "type":
+		node.types, err = c.typeList(value, path)
+	case "enum":
+		list, ok := value.([]interface{})
+		if !ok {
+			return c.errorf(path, "enum debe ser un array")
+		}
+		node.enum, node.hasEnum = list, true
+	case "const":
+		node.constValue, node.hasConst = value, true
+	case "properties":
+		members := objectValues(value)
+		if members == nil {
+			return c.errorf(path, "properties debe ser un objeto")
+		}
+		node.properties = map[string]*schemaNode{}
+		for _, name := range orderedKeys(value) {
+			if node.properties[name], err = c.compile(members[name], path+"/"+escapePointerToken(name)); err != nil {
+				return err
+			}
+		}
+	case "additionalProperties":
+		node.additionalProperties, err = c.compile(value, path)
+	case "required":
+		node.required, err = c.stringList(value, path)
+	case "pattern":
+		source, ok := value.(string)
+		if !ok {
+			return c.errorf(path, "pattern debe ser un string")
+		}
+		if node.pattern, err = regexp.Compile(source); err != nil {
+			return c.errorf(path, "expresión regular inválida: %v", err)
+		}
+	case "minLength":
+		node.minLength, err = c.nonNegative(value, path)
+	case "maxLength":
+		node.maxLength, err = c.nonNegative(value, path)
+	case "minItems":
+		node.minItems, err = c.nonNegative(value, path)
+	case "maxItems":
+		node.maxItems, err = c.nonNegative(value, path)
+	case "minimum":
+		node.minimum, err = c.number(value, path)
+	case "maximum":
+		node.maximum, err = c.number(value, path)
+	case "exclusiveMinimum":
+		node.exclusiveMinimum, err = c.number(value, path)
+	case "exclusiveMaximum":
+		node.exclusiveMaximum, err = c.number(value, path)
+	case "multipleOf":
+		if node.multipleOf, err = c.number(value, path); err == nil && node.multipleOf.float <= 0 {
+			return c.errorf(path, "multipleOf debe ser mayor que 0")
+		}
+	case "items":
+		node.items, err = c.compile(value, path)
+	case "prefixItems":
+		node.prefixItems, err = c.schemaList(value, path)
+	case "uniqueItems":
+		unique, ok := value.(bool)
+		if !ok {
+			return c.errorf(path, "uniqueItems debe ser un booleano")
+		}
+		node.uniqueItems = unique
+	case "$ref":
+		ref, ok := value.(string)
+		if !ok {
+			return c.errorf(path, "$ref debe ser un string")
+		}
+		if !strings.HasPrefix(ref, "#") {
+			return c.errorf(path, "solo se admiten referencias locales ('#...'): %s", ref)
+		}
+		fragment, err := url.PathUnescape(ref[1:])
+		if err != nil {
+			return c.errorf(path, "referencia inválida: %s", ref)
+		}
+		node.ref = fragment
+		c.pending = append(c.pending, node)
+	case "$defs", "definitions":
+		members := objectValues(value)
+		if members == nil {
+			return c.errorf(path, "%s debe ser un objeto", keyword)
+		}
+		for _, name := range orderedKeys(value) {
+			defPath := path + "/" + escapePointerToken(name)
+			if _, compiled := c.nodes[defPath]; compiled {
+				continue
+			}
+			if _, err := c.compile(members[name], defPath); err != nil {
+				return err
+			}
+		}
+	case "allOf":
+		node.allOf, err = c.schemaList(value, path)
+	case "anyOf":
+		node.anyOf, err = c.schemaList(value, path)
+	case "oneOf":
+		node.oneOf, err = c.schemaList(value, path)
+	case "not":
+		node.not, err = c.compile(value, path)
+	}
+	return err
+}
+
+// resolve devuelve el nodo al que apunta un $ref, compilándolo si hace falta
+func (c *schemaCompiler) resolve(node *schemaNode) (*schemaNode, error) {
+	if target, compiled := c.nodes[node.ref]; compiled {
+		return target, nil
+	}
+	value, err := PointerGet(c.root, node.ref)
+	if err != nil {
+		return nil, c.errorf(node.path+"/$ref", "referencia '#%s' no encontrada", node.ref)
+	}
+	return c.compile(value, node.ref)
+}
+
+// typeList acepta un nombre de tipo o un array de nombres
+func (c *schemaCompiler) typeList(value interface{}, path string) ([]string, error) {
+	var names []string
+	if name, ok := value.(string); ok {
+		names = []string{name}
+	} else {
+		var err error
+		if names, err = c.stringList(value, path); err != nil {
+			return nil, c.errorf(path, "type debe ser un string o un array de strings")
+		}
+	}
+	for _, name := range names {
+		switch name {
+		case "null", "boolean", "object", "array", "number", "string", "integer":
+		default:
+			return nil, c.errorf(path, "tipo desconocido '%s'", name)
+		}
+	}
+	return names, nil
+}
+
+func (c *schemaCompiler) stringList(value interface{}, path string) ([]string, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, c.errorf(path, "se esperaba un array de strings")
+	}
+	list := make([]string, len(items))
+	for i, item := range items {
+		text, isString := item.(string)
+		if !isString {
+			return nil, c.errorf(path, "se esperaba un array de strings")
+		}
+		list[i] = text
+	}
+	return list, nil
+}
+
+func (c *schemaCompiler) schemaList(value interface{}, path string) ([]*schemaNode, error) {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, c.errorf(path, "se esperaba un array no vacío de esquemas")
+	}
+	nodes := make([]*schemaNode, len(items))
+	for i, item := range items {
+		var err error
+		if nodes[i], err = c.compile(item, path+"/"+strconv.Itoa(i)); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (c *schemaCompiler) number(value interface{}, path string) (*numericValue, error) {
+	n, ok := numericLiteral(value)
+	if !ok {
+		return nil, c.errorf(path, "se esperaba un número")
+	}
+	return &n, nil
+}
+
+// nonNegative entero no negativo (se admite 2.0 como en la especificación)
+func (c *schemaCompiler) nonNegative(value interface{}, path string) (int, error) {
+	n, ok := numericLiteral(value)
+	if !ok || n.float < 0 || n.float != math.Trunc(n.float) || n.float > math.MaxInt32 {
+		return 0, c.errorf(path, "se esperaba un entero no negativo")
+	}
+	return int(n.float), nil
+}
+
+// escapePointerToken escapa un segmento de JSON Pointer (~0 y ~1)
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// ===== Validación =====
+
+// validate agrega a out las violaciones de instance contra el nodo
+func (n *schemaNode) validate(instance interface{}, path []string, out *[]SchemaViolation, run *schemaRun) {
+	fail := func(keyword, format string, args ...interface{}) {
+		*out = append(*out, SchemaViolation{
+			InstancePath: FormatPointer(path),
+			SchemaPath:   n.path + "/" + escapePointerToken(keyword),
+			Keyword:      keyword,
+			Msg:          fmt.Sprintf(format, args...),
+		})
+	}
+
+	if run.exhausted {
+		return
+	}
+	if run.evaluations++; run.evaluations > maxSchemaEvaluations {
+		run.exhausted = true
+		return
+	}
+	if run.depth >= maxSchemaDepth {
+		fail("$ref", "se superó la profundidad máxima de validación")
+		return
+	}
+	visit := schemaVisit{node: n, level: len(path)}
+	if run.active[visit] {
+		fail("$ref", "referencia circular: el esquema vuelve a evaluarse sobre el mismo valor")
+		return
+	}
+	run.active[visit] = true
+	run.depth++
+	defer func() {
+		delete(run.active, visit)
+		run.depth--
+	}()
+
+	if n.boolean != nil {
+		if !*n.boolean {
+			*out = append(*out, SchemaViolation{InstancePath: FormatPointer(path), SchemaPath: n.path, Keyword: "false", Msg: "el esquema false no admite ningún valor"})
+		}
+		return
+	}
+
+	if n.refNode != nil {
+		n.refNode.validate(instance, path, out, run)
+	}
+
+	if len(n.types) > 0 && !matchesAnyType(instance, n.types) {
+		fail("type", "se esperaba %s pero el valor es %s", strings.Join(n.types, " o "), schemaTypeOf(instance))
+	}
+	if n.hasEnum && !containsValue(n.enum, instance) {
+		fail("enum", "el valor no está entre los permitidos")
+	}
+	if n.hasConst && !EqualValues(n.constValue, instance) {
+		fail("const", "el valor no es igual a la constante")
+	}
+
+	switch v := instance.(type) {
+	case string:
+		n.validateString(v, fail)
+	case []interface{}:
+		n.validateArray(v, path, out, run, fail)
+	case map[string]interface{}, *OrderedObject:
+		n.validateObject(instance, path, out, run, fail)
+	default:
+		if !n.hasNumberKeywords() {
+			break
+		}
+		if number, ok := numericLiteral(instance); ok {
+			n.validateNumber(number, fail)
+		}
+	}
+
+	for _, sub := range n.allOf {
+		sub.validate(instance, path, out, run)
+	}
+	if len(n.anyOf) > 0 && n.countMatches(n.anyOf, instance, path, run) == 0 {
+		fail("anyOf", "el valor no cumple ninguno de los esquemas de anyOf")
+	}
+	if len(n.oneOf) > 0 {
+		if matches := n.countMatches(n.oneOf, instance, path, run); matches != 1 {
+			fail("oneOf", "el valor cumple %d esquemas de oneOf (debe cumplir exactamente uno)", matches)
+		}
+	}
+	if n.not != nil && n.countMatches([]*schemaNode{n.not}, instance, path, run) == 1 {
+		fail("not", "el valor no debe cumplir el esquema de not")
+	}
+}
+
+func (n *schemaNode) validateString(s string, fail func(string, string, ...interface{})) {
+	length := utf8.RuneCountInString(s)
+	if n.minLength >= 0 && length < n.minLength {
+		fail("minLength", "el string tiene %d caracteres (mínimo %d)", length, n.minLength)
+	}
+	if n.maxLength >= 0 && length > n.maxLength {
+		fail("maxLength", "el string tiene %d caracteres (máximo %d)", length, n.maxLength)
+	}
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		fail("pattern", "el string no coincide con el patrón '%s'", n.pattern.String())
+	}
+}
+
+// hasNumberKeywords indica si vale la pena convertir la instancia a número
+func (n *schemaNode) hasNumberKeywords() bool {
+	return n.minimum != nil || n.maximum != nil || n.exclusiveMinimum != nil || n.exclusiveMaximum != nil || n.multipleOf != nil
+}
+
+func (n *schemaNode) validateNumber(number numericValue, fail func(string, string, ...interface{})) {
+	if n.minimum != nil && compareNumbers(number, *n.minimum) < 0 {
+		fail("minimum", "el valor es menor que el mínimo %s", formatNumeric(*n.minimum))
+	}
+	if n.maximum != nil && compareNumbers(number, *n.maximum) > 0 {
+		fail("maximum", "el valor es mayor que el máximo %s", formatNumeric(*n.maximum))
+	}
+	if n.exclusiveMinimum != nil && compareNumbers(number, *n.exclusiveMinimum) <= 0 {
+		fail("exclusiveMinimum", "el valor debe ser mayor que %s", formatNumeric(*n.exclusiveMinimum))
+	}
+	if n.exclusiveMaximum != nil && compareNumbers(number, *n.exclusiveMaximum) >= 0 {
+		fail("exclusiveMaximum", "el valor debe ser menor que %s", formatNumeric(*n.exclusiveMaximum))
+	}
+	if n.multipleOf != nil && !isMultipleOf(number, *n.multipleOf) {
+		fail("multipleOf", "el valor no es múltiplo de %s", formatNumeric(*n.multipleOf))
+	}
+}
+
+func (n *schemaNode) validateArray(items []interface{}, path []string, out *[]SchemaViolation, run *schemaRun, fail func(string, string, ...interface{})) {
+	if n.minItems >= 0 && len(items) < n.minItems {
+		fail("minItems", "el array tiene %d elementos (mínimo %d)", len(items), n.minItems)
+	}
+	if n.maxItems >= 0 && len(items) > n.maxItems {
+		fail("maxItems", "el array tiene %d elementos (máximo %d)", len(items), n.maxItems)
+	}
+	if n.uniqueItems {
+		for i := 1; i < len(items); i++ {
+			if containsValue(items[:i], items[i]) {
+				fail("uniqueItems", "el elemento %d está repetido", i)
+				break
+			}
+		}
+	}
+
+	for i, item := range items {
+		itemPath := appendToken(path, strconv.Itoa(i))
+		switch {
+		case i < len(n.prefixItems):
+			n.prefixItems[i].validate(item, itemPath, out, run)
+		case n.items != nil:
+			n.items.validate(item, itemPath, out, run)
+		}
+	}
+}
+
+func (n *schemaNode) validateObject(object interface{}, path []string, out *[]SchemaViolation, run *schemaRun, fail func(string, string, ...interface{})) {
+	members := objectValues(object)
+	for _, name := range n.required {
+		if _, exists := members[name]; !exists {
+			fail("required", "falta la propiedad requerida '%s'", name)
+		}
+	}
+
+	for _, key := range orderedKeys(object) {
+		memberPath := appendToken(path, key)
+		if sub, declared := n.properties[key]; declared {
+			sub.validate(members[key], memberPath, out, run)
+		} else if n.additionalProperties != nil {
+			n.additionalProperties.validate(members[key], memberPath, out, run)
+		}
+	}
+}
+
+// countMatches cuántos de los esquemas acepta la instancia sin violaciones
+func (n *schemaNode) countMatches(schemas []*schemaNode, instance interface{}, path []string, run *schemaRun) int {
+	matches := 0
+	for _, sub := range schemas {
+		var violations []SchemaViolation
+		sub.validate(instance, path, &violations, run)
+		if len(violations) == 0 && !run.exhausted {
+			matches++
+		}
+	}
+	return matches
+}
+
+// schemaTypeOf nombre del tipo de JSON Schema de un valor (integer para
+// números sin parte fraccionaria)
+func schemaTypeOf(value interface{}) string {
+	if number, ok := numericLiteral(value); ok {
+		if isIntegerValue(number) {
+			return "integer"
+		}
+		return "number"
+	}
+	if value == nil {
+		return "null"
+	}
+	return JSONTypeOf(value)
+}
+
+// matchesAnyType verifica el tipo; un integer también es number
+func matchesAnyType(value interface{}, types []string) bool {
+	actual := schemaTypeOf(value)
+	for _, name := range types {
+		if name == actual || (name == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func isIntegerValue(number numericValue) bool {
+	if number.rounded {
+		return number.float == math.Trunc(number.float) && !math.IsInf(number.float, 0)
+	}
+	return number.exact.IsInt()
+}
+
+// isMultipleOf exacto cuando ambos números conservan su literal
+func isMultipleOf(number, divisor numericValue) bool {
+	if number.rounded || divisor.rounded {
+		quotient := number.float / divisor.float
+		return math.Abs(quotient-math.Round(quotient)) < 1e-9
+	}
+	return new(big.Rat).Quo(number.exact, divisor.exact).IsInt()
+}
+
+// formatNumeric representación breve de un número para los mensajes
+func formatNumeric(number numericValue) string {
+	if !number.rounded && number.exact.IsInt() {
+		return number.exact.Num().String()
+	}
+	return strconv.FormatFloat(number.float, 'g', -1, 64)
+}
+
+// containsValue indica si la lista contiene un valor igual (EqualValues)
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if EqualValues(item, value) {
+			return true
+		}
+	}
+	return false
+}
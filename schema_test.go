@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// personSchema esquema de ejemplo con referencias locales y combinadores
+const personSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["name", "age"],
+  "properties": {
+    "name": {"type": "string", "minLength": 2, "pattern": "^[A-Z]"},
+    "age": {"$ref": "#/$defs/age"},
+    "email": {"type": "string", "pattern": "@"},
+    "role": {"enum": ["admin", "dev", "ops"]},
+    "tags": {"type": "array", "items": {"type": "string"}, "maxItems": 3, "uniqueItems": true},
+    "score": {"type": "number", "exclusiveMinimum": 0, "maximum": 10, "multipleOf": 0.5},
+    "contact": {"oneOf": [{"required": ["email"]}, {"required": ["phone"]}]},
+    "id": {"anyOf": [{"type": "integer"}, {"type": "string", "pattern": "^[0-9a-f-]{36}$"}]},
+    "extra": {"allOf": [{"type": "object"}, {"required": ["kind"]}]}
+  },
+  "additionalProperties": false,
+  "$defs": {
+    "age": {"type": "integer", "minimum": 0, "maximum": 150}
+  }
+}`
+
+// validateExact compila el esquema y valida la instancia, ambos parseados como en el endpoint
+func validateExact(t *testing.T, schema, instance string) []SchemaViolation {
+	t.Helper()
+	violations, err := ValidateSchema(parseExact(t, schema), parseExact(t, instance))
+	if err != nil {
+		t.Fatalf("ValidateSchema() error = %v", err)
+	}
+	return violations
+}
+
+// Test para instancias válidas e inválidas con su ruta de instancia y de esquema
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance string
+		want     []string // "instance_path schema_path"
+	}{
+		{"válida", `{"name":"Ana","age":30,"role":"dev","tags":["a","b"],"score":9.5,"id":7}`, nil},
+		{"entero con decimales cero", `{"name":"Ana","age":30.0}`, nil},
+		{"faltan requeridas", `{}`, []string{" /required", " /required"}},
+		{"tipo incorrecto", `[]`, []string{" /type"}},
+		{"referencia local", `{"name":"Ana","age":-1}`, []string{"/age /$defs/age/minimum"}},
+		{"entero requerido", `{"name":"Ana","age":1.5}`, []string{"/age /$defs/age/type"}},
+		{"string", `{"name":"a","age":1}`, []string{"/name /properties/name/minLength", "/name /properties/name/pattern"}},
+		{"enum", `{"name":"Ana","age":1,"role":"root"}`, []string{"/role /properties/role/enum"}},
+		{"items", `{"name":"Ana","age":1,"tags":["a",2,"a","b"]}`, []string{"/tags /properties/tags/maxItems", "/tags /properties/tags/uniqueItems", "/tags/1 /properties/tags/items/type"}},
+		{"números", `{"name":"Ana","age":1,"score":0}`, []string{"/score /properties/score/exclusiveMinimum"}},
+		{"múltiplo", `{"name":"Ana","age":1,"score":10.25}`, []string{"/score /properties/score/maximum", "/score /properties/score/multipleOf"}},
+		{"oneOf ninguno", `{"name":"Ana","age":1,"contact":{}}`, []string{"/contact /properties/contact/oneOf"}},
+		{"oneOf ambos", `{"name":"Ana","age":1,"contact":{"email":"x","phone":"y"}}`, []string{"/contact /properties/contact/oneOf"}},
+		{"anyOf", `{"name":"Ana","age":1,"id":"xyz"}`, []string{"/id /properties/id/anyOf"}},
+		{"allOf", `{"name":"Ana","age":1,"extra":{}}`, []string{"/extra /properties/extra/allOf/1/required"}},
+		{"adicionales", `{"name":"Ana","age":1,"x":1}`, []string{"/x /additionalProperties"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := validateExact(t, personSchema, tt.instance)
+			got := []string{}
+			for _, v := range violations {
+				got = append(got, v.InstancePath+" "+v.SchemaPath)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Validate() = %v, want %v (%v)", got, tt.want, violations)
+			}
+		})
+	}
+}
+
+// Test para esquemas booleanos, const, not, prefixItems y referencias recursivas
+func TestSchemaKeywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		valid    bool
+	}{
+		{"true", `true`, `{"a":1}`, true},
+		{"false", `false`, `1`, false},
+		{"const", `{"const":{"a":[1]}}`, `{"a":[1.0]}`, true},
+		{"const distinto", `{"const":"x"}`, `"y"`, false},
+		{"not", `{"not":{"type":"string"}}`, `"x"`, false},
+		{"prefixItems", `{"prefixItems":[{"type":"string"},{"type":"integer"}],"items":false}`, `["a",1]`, true},
+		{"prefixItems con extra", `{"prefixItems":[{"type":"string"}],"items":false}`, `["a",1]`, false},
+		{"tipos múltiples", `{"type":["string","null"]}`, `null`, true},
+		{"longitud en code points", `{"maxLength":2}`, `"ñé"`, true},
+		{"número exacto", `{"maximum":9007199254740992}`, `9007199254740993`, false},
+		{"árbol recursivo", `{"$defs":{"node":{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#/$defs/node"}}},"required":["v"]}},"$ref":"#/$defs/node"}`, `{"v":1,"children":[{"v":2,"children":[{"children":[]}]}]}`, false},
+		{"referencia a la raíz", `{"type":"object","properties":{"next":{"$ref":"#"}}}`, `{"next":{"next":{}}}`, true},
+		{"referencia con escape", `{"$defs":{"a/b":{"type":"string"}},"$ref":"#/$defs/a~1b"}`, `1`, false},
+		{"palabras clave desconocidas", `{"title":"x","x-custom":1}`, `1`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := validateExact(t, tt.schema, tt.instance)
+			if valid := len(violations) == 0; valid != tt.valid {
+				t.Errorf("Validate() válido = %v, want %v (%v)", valid, tt.valid, violations)
+			}
+		})
+	}
+}
+
+// Test para referencias circulares que no avanzan sobre la instancia
+func TestSchemaCircularRef(t *testing.T) {
+	for _, schema := range []string{`{"$ref":"#"}`, `{"allOf":[{"$ref":"#"},{"$ref":"#"}]}`} {
+		t.Run(schema, func(t *testing.T) {
+			violations := validateExact(t, schema, `1`)
+			if len(violations) == 0 || !strings.Contains(violations[0].Msg, "referencia circular") {
+				t.Errorf("Validate() = %v", violations)
+			}
+		})
+	}
+}
+
+// Test para referencias sin ciclos que se ramifican exponencialmente
+func TestSchemaEvaluationBudget(t *testing.T) {
+	var defs []string
+	for i := 0; i < 40; i++ {
+		defs = append(defs, fmt.Sprintf(`"d%d":{"allOf":[{"$ref":"#/$defs/d%d"},{"$ref":"#/$defs/d%d"}]}`, i, i+1, i+1))
+	}
+	defs = append(defs, `"d40":{"type":"integer"}`)
+	schema := `{"$defs":{` + strings.Join(defs, ",") + `},"$ref":"#/$defs/d0"}`
+
+	violations := validateExact(t, schema, `1`)
+	if len(violations) == 0 || !strings.Contains(violations[len(violations)-1].Msg, "presupuesto") {
+		t.Errorf("Validate() = %v", violations)
+	}
+}
+
+// Test para esquemas inválidos
+func TestCompileSchemaErrors(t *testing.T) {
+	tests := []struct {
+		schema string
+		path   string
+		want   string
+	}{
+		{`1`, "", "debe ser un objeto o un booleano"},
+		{`{"type":"text"}`, "/type", "tipo desconocido"},
+		{`{"properties":{"a":{"minLength":-1}}}`, "/properties/a/minLength", "entero no negativo"},
+		{`{"pattern":"("}`, "/pattern", "expresión regular inválida"},
+		{`{"$ref":"#/$defs/missing"}`, "/$ref", "no encontrada"},
+		{`{"$ref":"other.json"}`, "/$ref", "referencias locales"},
+		{`{"anyOf":[]}`, "/anyOf", "array no vacío"},
+		{`{"required":[1]}`, "/required", "array de strings"},
+		{`{"multipleOf":0}`, "/multipleOf", "mayor que 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			_, err := CompileSchema(parseExact(t, tt.schema))
+			schemaErr := AsSchemaError(err)
+			if schemaErr == nil || schemaErr.Path != tt.path || !strings.Contains(schemaErr.Msg, tt.want) {
+				t.Errorf("CompileSchema() error = %v, want %q en %q", err, tt.want, tt.path)
+			}
+		})
+	}
+}
@@ -49,6 +49,7 @@ window.convertFile = function() {
     // Crear FormData y enviar
     const formData = new FormData();
     formData.append('txtFile', file);
+    appendConversionOptions(formData);
     
     fetch('/api/convert-to-go', {
         method: 'POST',
@@ -76,7 +77,9 @@ window.convertFile = function() {
             // Mostrar error del servidor
             if (statusElement) {
                 statusElement.className = 'alert alert-danger mb-3';
-                statusElement.innerHTML = '<i class="fas fa-times me-2"></i>' + (result.error || 'Error en la conversión');
+                const diagnostic = (result.diagnostics || [])[0];
+                const where = diagnostic ? ` (línea ${diagnostic.line}, columna ${diagnostic.column}: ${diagnostic.message})` : '';
+                statusElement.innerHTML = '<i class="fas fa-times me-2"></i>' + escapeHtml((result.error || 'Error en la conversión') + where);
             }
         }
     })
@@ -90,12 +93,29 @@ window.convertFile = function() {
 };
 
 // ===== FUNCIÓN PARA MOSTRAR CÓDIGO GENERADO =====
+// appendConversionOptions agrega los campos opcionales del conversor
+// (package, identifier, mode, el dialecto CSV y la convención XML) cuando el usuario los completó
+function appendConversionOptions(formData) {
+    const fields = {
+        mode: 'convertMode', package: 'convertPackage', identifier: 'convertIdentifier',
+        delimiter: 'csvDelimiter', quote: 'csvQuote', xml_convention: 'xmlConvention'
+    };
+    for (const [name, id] of Object.entries(fields)) {
+        const element = document.getElementById(id);
+        const value = element ? element.value.trim() : '';
+        if (value) {
+            formData.append(name, value);
+        }
+    }
+}
+
 function showGeneratedCode(result) {
     const contentElement = document.getElementById('generatedCodeContent');
     if (!contentElement) return;
     
     const goCode = result.go_code || 'Error: No se generó código';
     const filename = result.download_filename || 'generated_code.go';
+    const params = result.parameters || {};
     
     contentElement.innerHTML = `
         <div class="alert alert-success mb-3">
@@ -131,8 +151,9 @@ function showGeneratedCode(result) {
                     <div class="card-body">
                         <small><strong>Archivo:</strong> ${result.original_file || 'archivo'}</small><br>
                         <small><strong>Tamaño:</strong> ${formatBytes(result.file_size || 0)}</small><br>
-                        <small><strong>Package:</strong> main (automático)</small><br>
-                        <small><strong>Variable:</strong> textContent (automático)</small>
+                        <small><strong>Package:</strong> ${escapeHtml(params.package_name || 'main')}</small><br>
+                        <small><strong>Identificador:</strong> ${escapeHtml(params.variable_name || 'textContent')}</small><br>
+                        <small><strong>Modo:</strong> ${escapeHtml(params.conversion_type || 'variable')}</small>
                     </div>
                 </div>
             </div>
@@ -223,7 +244,7 @@ window.parseJSON = function() {
     fetch('/api/parse', {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ json: jsonText })
+        body: JSON.stringify({ json: input.value, preserve_order: preserveOrderEnabled(), number_mode: selectedNumberMode(), format: true })
     })
     .then(response => response.json())
     .then(result => {
@@ -237,7 +258,14 @@ window.parseJSON = function() {
                     <div class="card">
                         <div class="card-header">Resultado</div>
                         <div class="card-body">
-                            <pre style="background: #f8f9fa; padding: 10px; border-radius: 5px;">${JSON.stringify(result.result, null, 2)}</pre>
+                            <pre style="background: #f8f9fa; padding: 10px; border-radius: 5px;">${escapeHtml(result.formatted || JSON.stringify(result.result, null, 2))}</pre>
+                        </div>
+                    </div>
+                    <div class="card mt-3">
+                        <div class="card-header">Explorador (clic para ver el valor y su JSON Pointer)</div>
+                        <div class="card-body">
+                            <ul class="pointer-tree mb-2">${renderPointerTree(result.result)}</ul>
+                            <div id="pointerValue"></div>
                         </div>
                     </div>
                 `;
@@ -245,9 +273,11 @@ window.parseJSON = function() {
                 resultElement.innerHTML = `
                     <div class="alert alert-danger">
                         <h6>❌ Error de parseo</h6>
-                        <p class="mb-0">${result.error}</p>
+                        <p class="mb-0">${escapeHtml(result.error)}</p>
+                        ${renderErrorDetails(result.error_details)}
                     </div>
                 `;
+                highlightErrorPosition(result.error_details);
             }
         }
     })
@@ -283,18 +313,50 @@ window.clearInput = function() {
 };
 
 window.formatJSON = function() {
+    serializeJSON('/api/format', 'JSON formateado correctamente');
+};
+
+window.minifyJSON = function() {
+    serializeJSON('/api/minify', 'JSON minificado correctamente');
+};
+
+// serializeJSON reescribe el editor con la salida del servidor, que conserva
+// el orden original de las claves y los números sin redondeo
+function serializeJSON(endpoint, message) {
     const input = document.getElementById('jsonInput');
     if (!input) return;
-    
-    try {
-        const parsed = JSON.parse(input.value);
-        input.value = JSON.stringify(parsed, null, 2);
-        updateStats();
-        alert('JSON formateado correctamente');
-    } catch (error) {
-        alert('JSON inválido, no se puede formatear');
+
+    if (!input.value.trim()) {
+        alert('Por favor ingresa un JSON');
+        return;
     }
-};
+
+    fetch(endpoint, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ json: input.value, indent_width: 2, sort_keys: sortKeysEnabled(), max_inline_width: 80 })
+    })
+    .then(response => response.json())
+    .then(result => {
+        if (result.success) {
+            input.value = result.output;
+            updateStats();
+            const saved = result.bytes_saved;
+            const detail = saved >= 0 ? `${formatBytes(saved)} ahorrados` : `${formatBytes(-saved)} agregados`;
+            alert(`${message} (${detail}, ${result.savings_percent})`);
+        } else {
+            alert('JSON inválido, no se puede formatear: ' + result.error);
+            highlightErrorPosition(result.error_details);
+        }
+    })
+    .catch(error => alert('Error de conexión: ' + error.message));
+}
+
+// sortKeysEnabled indica si el usuario pidió ordenar las claves alfabéticamente
+function sortKeysEnabled() {
+    const checkbox = document.getElementById('sortKeys');
+    return !!(checkbox && checkbox.checked);
+}
 
 window.toggleTheme = function() {
     const currentTheme = document.documentElement.getAttribute('data-theme');
@@ -305,6 +367,274 @@ window.toggleTheme = function() {
 };
 
 // ===== FUNCIONES AUXILIARES =====
+function renderErrorDetails(details) {
+    if (!details) return '';
+    const expected = details.expected
+        ? `<small class="d-block">Se esperaba: <code>${escapeHtml(details.expected)}</code></small>`
+        : '';
+    return `
+        <hr>
+        <small class="d-block"><strong>Línea ${details.line}, columna ${details.column}</strong> (byte ${details.offset})</small>
+        ${expected}
+        <pre class="mt-2 mb-0 p-2 bg-light text-dark rounded">${escapeHtml(details.snippet)}</pre>
+    `;
+}
+
+// Selecciona en el textarea el carácter donde se detectó el error
+function highlightErrorPosition(details) {
+    const input = document.getElementById('jsonInput');
+    if (!input || !details) return;
+
+    const lines = input.value.split('\n');
+    let index = 0;
+    for (let i = 0; i < details.line - 1 && i < lines.length; i++) {
+        index += lines[i].length + 1;
+    }
+    // La columna se cuenta en caracteres Unicode; convertir a unidades UTF-16
+    const line = lines[details.line - 1] || '';
+    index += Array.from(line).slice(0, details.column - 1).join('').length;
+
+    input.focus();
+    input.setSelectionRange(index, Math.min(index + 1, input.value.length));
+}
+
+// pointerTreePaths JSON Pointer de cada nodo del explorador, por índice
+let pointerTreePaths = [];
+
+// renderPointerTree dibuja el resultado como árbol navegable; cada nodo guarda
+// su JSON Pointer y al hacer clic se consulta el valor exacto en /api/pointer
+function renderPointerTree(value, pointer = '', label = '(raíz)', reset = true) {
+    if (reset) pointerTreePaths = [];
+    const index = pointerTreePaths.push(pointer) - 1;
+    let html = `<li><a href="#" onclick="showPointerValue(${index}); return false;">${escapeHtml(String(label))}</a>`;
+
+    if (value !== null && typeof value === 'object') {
+        html += '<ul>';
+        for (const key of Object.keys(value)) {
+            const token = key.replace(/~/g, '~0').replace(/\//g, '~1');
+            const childLabel = Array.isArray(value) ? `[${key}]` : key;
+            html += renderPointerTree(value[key], `${pointer}/${token}`, childLabel, false);
+        }
+        html += '</ul>';
+    } else {
+        html += `: <code>${escapeHtml(JSON.stringify(value))}</code>`;
+    }
+    return html + '</li>';
+}
+
+window.showPointerValue = function(index) {
+    const input = document.getElementById('jsonInput');
+    const panel = document.getElementById('pointerValue');
+    if (!input || !panel) return;
+
+    const pointer = pointerTreePaths[index];
+    fetch('/api/pointer', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ json: input.value, pointer: pointer })
+    })
+    .then(response => response.json())
+    .then(result => {
+        if (result.success) {
+            panel.innerHTML = `
+                <div class="alert alert-info mb-0">
+                    <div><strong>Pointer:</strong> <code>${escapeHtml(pointer || '""')}</code>
+                    <span class="badge bg-secondary ms-2">${escapeHtml(result.value_type)}</span></div>
+                    <pre class="mb-0 mt-2">${escapeHtml(result.formatted)}</pre>
+                </div>
+            `;
+        } else {
+            panel.innerHTML = `<div class="alert alert-warning mb-0">${escapeHtml(result.error)}</div>`;
+        }
+    })
+    .catch(error => {
+        panel.innerHTML = `<div class="alert alert-danger mb-0">Error de conexión: ${escapeHtml(error.message)}</div>`;
+    });
+};
+
+window.queryJSON = function() {
+    const input = document.getElementById('jsonInput');
+    const queryInput = document.getElementById('jsonPathQuery');
+    const resultElement = document.getElementById('resultContent');
+    if (!input || !queryInput || !resultElement) return;
+
+    if (!input.value.trim() || !queryInput.value.trim()) {
+        alert('Ingresa un JSON y una consulta JSONPath');
+        return;
+    }
+
+    fetch('/api/query', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ json: input.value, query: queryInput.value })
+    })
+    .then(response => response.json())
+    .then(result => {
+        if (result.success) {
+            const rows = result.results.map(node => `
+                <tr>
+                    <td><code>${escapeHtml(node.path)}</code></td>
+                    <td><code>${escapeHtml(JSON.stringify(node.value))}</code></td>
+                </tr>
+            `).join('');
+            resultElement.innerHTML = `
+                <div class="alert alert-success">
+                    <h6 class="mb-0">🔎 ${result.count} nodo(s) para <code>${escapeHtml(result.query)}</code></h6>
+                </div>
+                <table class="table table-sm">
+                    <thead><tr><th>Ruta normalizada</th><th>Valor</th></tr></thead>
+                    <tbody>${rows}</tbody>
+                </table>
+            `;
+        } else {
+            const position = result.query_error ? ` (posición ${result.query_error.offset})` : '';
+            resultElement.innerHTML = `
+                <div class="alert alert-danger">
+                    <h6>❌ Error en la consulta</h6>
+                    <p class="mb-0">${escapeHtml(result.error)}${position}</p>
+                    ${renderErrorDetails(result.error_details)}
+                </div>
+            `;
+            highlightErrorPosition(result.error_details);
+        }
+    })
+    .catch(error => {
+        resultElement.innerHTML = `<div class="alert alert-danger mb-0">Error de conexión: ${escapeHtml(error.message)}</div>`;
+    });
+};
+
+window.compareJSON = function() {
+    const original = document.getElementById('diffOriginal');
+    const modified = document.getElementById('diffModified');
+    const resultElement = document.getElementById('diffResult');
+    if (!original || !modified || !resultElement) return;
+
+    if (!original.value.trim() || !modified.value.trim()) {
+        alert('Ingresa ambos documentos para comparar');
+        return;
+    }
+
+    const ignoreOrder = document.getElementById('ignoreArrayOrder');
+    const ignorePaths = document.getElementById('ignorePaths');
+    fetch('/api/diff', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+            original: original.value,
+            modified: modified.value,
+            ignore_array_order: !!(ignoreOrder && ignoreOrder.checked),
+            ignore_paths: ignorePaths ? ignorePaths.value.split(',').map(path => path.trim()).filter(path => path) : []
+        })
+    })
+    .then(response => response.json())
+    .then(result => {
+        if (!result.success) {
+            resultElement.innerHTML = `
+                <div class="alert alert-danger">
+                    <h6>❌ No se pudo comparar</h6>
+                    <p class="mb-0">${escapeHtml(result.error)}</p>
+                    ${renderErrorDetails(result.error_details)}
+                </div>
+            `;
+            return;
+        }
+        resultElement.innerHTML = renderDiff(result);
+    })
+    .catch(error => {
+        resultElement.innerHTML = `<div class="alert alert-danger">Error de conexión: ${escapeHtml(error.message)}</div>`;
+    });
+};
+
+window.generatePatch = function() {
+    const original = document.getElementById('diffOriginal');
+    const modified = document.getElementById('diffModified');
+    const resultElement = document.getElementById('diffResult');
+    if (!original || !modified || !resultElement) return;
+
+    if (!original.value.trim() || !modified.value.trim()) {
+        alert('Ingresa ambos documentos para generar el patch');
+        return;
+    }
+
+    fetch('/api/diff-patch', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ original: original.value, modified: modified.value })
+    })
+    .then(response => response.json())
+    .then(result => {
+        if (result.success) {
+            resultElement.innerHTML = `
+                <div class="card">
+                    <div class="card-header">JSON Patch (RFC 6902) · ${result.operations} operación(es)</div>
+                    <div class="card-body">
+                        <pre class="mb-0">${escapeHtml(result.patch)}</pre>
+                    </div>
+                </div>
+            `;
+        } else {
+            resultElement.innerHTML = `<div class="alert alert-danger">${escapeHtml(result.error)}</div>`;
+        }
+    })
+    .catch(error => {
+        resultElement.innerHTML = `<div class="alert alert-danger">Error de conexión: ${escapeHtml(error.message)}</div>`;
+    });
+};
+
+// renderDiff muestra las diferencias en una tabla original/modificado por ruta
+// y, debajo, ambos documentos formateados lado a lado
+function renderDiff(result) {
+    const styles = { added: 'table-success', removed: 'table-danger', changed: 'table-warning' };
+    const labels = { added: 'agregado', removed: 'eliminado', changed: 'modificado' };
+    const cell = (value, present) => present ? `<code>${escapeHtml(JSON.stringify(value))}</code>` : '<span class="text-muted">—</span>';
+    const rows = result.entries.map(entry => `
+        <tr class="${styles[entry.kind]}">
+            <td><code>${escapeHtml(entry.path || '""')}</code><br><small>${labels[entry.kind]}</small></td>
+            <td>${cell(entry.old, entry.kind !== 'added')}</td>
+            <td>${cell(entry.new, entry.kind !== 'removed')}</td>
+        </tr>
+    `).join('');
+
+    const summary = result.equal
+        ? '<div class="alert alert-success">✅ Los documentos son equivalentes</div>'
+        : `<div class="alert alert-info">
+                <span class="badge bg-success me-1">${result.summary.added} agregados</span>
+                <span class="badge bg-danger me-1">${result.summary.removed} eliminados</span>
+                <span class="badge bg-warning text-dark">${result.summary.changed} modificados</span>
+           </div>`;
+
+    return `
+        ${summary}
+        ${result.equal ? '' : `
+        <table class="table table-sm table-bordered">
+            <thead><tr><th>Ruta</th><th>Original</th><th>Modificado</th></tr></thead>
+            <tbody>${rows}</tbody>
+        </table>`}
+        <div class="row g-3">
+            <div class="col-lg-6">
+                <div class="card"><div class="card-header">Original</div>
+                <div class="card-body"><pre class="mb-0">${escapeHtml(result.original)}</pre></div></div>
+            </div>
+            <div class="col-lg-6">
+                <div class="card"><div class="card-header">Modificado</div>
+                <div class="card-body"><pre class="mb-0">${escapeHtml(result.modified)}</pre></div></div>
+            </div>
+        </div>
+    `;
+}
+
+// preserveOrderEnabled indica si el usuario pidió conservar el orden de las claves
+function preserveOrderEnabled() {
+    const checkbox = document.getElementById('preserveOrder');
+    return !!(checkbox && checkbox.checked);
+}
+
+// selectedNumberMode modo numérico elegido: float64, number o int64
+function selectedNumberMode() {
+    const select = document.getElementById('numberMode');
+    return select ? select.value : 'float64';
+}
+
 function escapeHtml(text) {
     const div = document.createElement('div');
     div.textContent = text;
@@ -0,0 +1,553 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Convenciones de mapeo entre XML y el árbol de valores
+const (
+	// XMLConventionAttributes atributos con prefijo ("@id") y texto en una
+	// clave propia ("#text"); los elementos con solo texto quedan como string
+	XMLConventionAttributes = "attributes"
+	// XMLConventionBadgerFish cada elemento es un objeto: atributos "@id",
+	// texto "$" y los espacios de nombres en "@xmlns"
+	XMLConventionBadgerFish = "badgerfish"
+	// XMLConventionParker descarta el elemento raíz y los atributos, y
+	// convierte el texto en número o booleano cuando corresponde
+	XMLConventionParker = "parker"
+)
+
+// xmlConventions convenciones aceptadas, la primera es la predeterminada
+var xmlConventions = []string{XMLConventionAttributes, XMLConventionBadgerFish, XMLConventionParker}
+
+// XMLOptions convención de mapeo; los valores cero usan "attributes", "@",
+// "#text" y "root"
+type XMLOptions struct {
+	Convention      string
+	AttributePrefix string // prefijo de las claves de atributos (convención attributes)
+	TextKey         string // clave del texto de un elemento con atributos o hijos (convención attributes)
+	RootName        string // elemento raíz al escribir XML si el JSON no lo determina
+}
+
+// withDefaults completa los valores no indicados
+func (opts XMLOptions) withDefaults() XMLOptions {
+	if opts.Convention == "" {
+		opts.Convention = XMLConventionAttributes
+	}
+	if opts.AttributePrefix == "" {
+		opts.AttributePrefix = "@"
+	}
+	if opts.TextKey == "" {
+		opts.TextKey = "#text"
+	}
+	if opts.RootName == "" {
+		opts.RootName = "root"
+	}
+	switch opts.Convention {
+	case XMLConventionBadgerFish:
+		opts.AttributePrefix, opts.TextKey = "@", "$"
+	case XMLConventionParker:
+		opts.AttributePrefix, opts.TextKey = "", ""
+	}
+	return opts
+}
+
+// validate verifica la convención y que las claves especiales no se confundan
+func (opts XMLOptions) validate() error {
+	opts = opts.withDefaults()
+	known := false
+	for _, convention := range xmlConventions {
+		known = known || opts.Convention == convention
+	}
+	if !known {
+		return fmt.Errorf("convención XML no soportada '%s' (convenciones: %s)", opts.Convention, strings.Join(xmlConventions, ", "))
+	}
+	if opts.Convention == XMLConventionAttributes {
+		if strings.HasPrefix(opts.TextKey, opts.AttributePrefix) {
+			return fmt.Errorf("la clave de texto '%s' no puede empezar con el prefijo de atributos '%s'", opts.TextKey, opts.AttributePrefix)
+		}
+		// Las claves de atributos y la de texto comparten el objeto con los
+		// elementos hijos: no pueden coincidir con un nombre de elemento
+		if isXMLName(opts.AttributePrefix) {
+			return fmt.Errorf("el prefijo de atributos '%s' puede formar un nombre de elemento ('%sid'); use uno que no sea un nombre XML, como '@'", opts.AttributePrefix, opts.AttributePrefix)
+		}
+		if isXMLName(opts.TextKey) {
+			return fmt.Errorf("la clave de texto '%s' es un nombre de elemento XML válido; use una que no lo sea, como '#text'", opts.TextKey)
+		}
+	}
+	if !isXMLName(opts.RootName) {
+		return fmt.Errorf("'%s' no es un nombre de elemento XML válido", opts.RootName)
+	}
+	return nil
+}
+
+// xmlAttr atributo con su nombre calificado (prefijo:local)
+type xmlAttr struct {
+	name, value string
+}
+
+// xmlElement elemento leído del documento
+type xmlElement struct {
+	name     string
+	attrs    []xmlAttr
+	children []*xmlElement
+	text     strings.Builder
+	hasText  bool // tiene texto además de espacios
+}
+
+// ParseXML convierte un documento XML en el árbol de valores de ParseJSON
+// según la convención indicada. Los elementos repetidos bajo un mismo padre
+// forman un array, el texto se recorta y se ignoran comentarios,
+// instrucciones de procesamiento y DOCTYPE. Los nombres conservan su prefijo
+// de espacio de nombres ("soap:Body"). La convención attributes no admite
+// contenido mixto (texto junto a elementos hijos, "<a>1<b/>2</a>") porque un
+// único "#text" perdería el orden entre texto e hijos: devuelve un error con
+// la posición. BadgerFish une el texto en "$" y Parker lo descarta.
+func (p *Parser) ParseXML(input string, opts XMLOptions, parseOpts ParseOptions) (interface{}, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	root, err := readXMLTree(input, opts.withDefaults().Convention == XMLConventionAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &xmlConverter{parser: p, opts: opts.withDefaults(), parseOpts: parseOpts}
+	switch c.opts.Convention {
+	case XMLConventionBadgerFish:
+		object := c.newObject()
+		setMember(object, root.name, c.badgerFish(root))
+		return object, nil
+	case XMLConventionParker:
+		return c.parker(root)
+	}
+	object := c.newObject()
+	setMember(object, root.name, c.attributes(root))
+	return object, nil
+}
+
+// readXMLTree lee el único elemento raíz del documento con sus descendientes;
+// con rejectMixed el texto junto a elementos hijos es un error
+func readXMLTree(input string, rejectMixed bool) (*xmlElement, error) {
+	decoder := xml.NewDecoder(strings.NewReader(input))
+	var root *xmlElement
+	var stack []*xmlElement
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				err = errors.New(syntaxErr.Msg)
+			}
+			return nil, xmlSyntaxError(input, int(decoder.InputOffset()), err.Error())
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, xmlSyntaxError(input, offset, "el documento tiene más de un elemento raíz")
+			}
+			if len(stack) >= maxNestingDepth {
+				return nil, xmlSyntaxError(input, offset, fmt.Sprintf("profundidad máxima de anidación excedida (%d)", maxNestingDepth))
+			}
+			element := &xmlElement{name: xmlQualifiedName(t.Name)}
+			for _, attr := range t.Attr {
+				element.attrs = append(element.attrs, xmlAttr{name: xmlQualifiedName(attr.Name), value: attr.Value})
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				if rejectMixed && parent.hasText {
+					return nil, xmlMixedContentError(input, offset, parent.name)
+				}
+				parent.children = append(parent.children, element)
+			} else {
+				root = element
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			// RawToken no verifica que las etiquetas coincidan
+			name := xmlQualifiedName(t.Name)
+			if len(stack) == 0 {
+				return nil, xmlSyntaxError(input, offset, fmt.Sprintf("etiqueta de cierre </%s> sin apertura", name))
+			}
+			if open := stack[len(stack)-1].name; open != name {
+				return nil, xmlSyntaxError(input, offset, fmt.Sprintf("la etiqueta de cierre </%s> no coincide con <%s>", name, open))
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) == 0 {
+				if strings.TrimSpace(string(t)) != "" {
+					return nil, xmlSyntaxError(input, offset, "texto fuera del elemento raíz")
+				}
+				continue
+			}
+			element := stack[len(stack)-1]
+			if strings.TrimSpace(string(t)) != "" {
+				if rejectMixed && len(element.children) > 0 {
+					// La posición apunta al texto, no a los espacios que lo preceden
+					offset += len(t) - len(strings.TrimLeftFunc(string(t), unicode.IsSpace))
+					return nil, xmlMixedContentError(input, offset, element.name)
+				}
+				element.hasText = true
+			}
+			element.text.Write(t)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, xmlSyntaxError(input, len(input), fmt.Sprintf("el elemento <%s> no está cerrado", stack[len(stack)-1].name))
+	}
+	if root == nil {
+		return nil, xmlSyntaxError(input, len(input), "el documento no tiene elemento raíz")
+	}
+	return root, nil
+}
+
+// xmlSyntaxError crea un SyntaxError en offset
+func xmlSyntaxError(input string, offset int, msg string) error {
+	if offset > len(input) {
+		offset = len(input)
+	}
+	return newSyntaxError([]byte(input), offset, "", "XML inválido: "+msg)
+}
+
+// xmlMixedContentError error para texto junto a elementos hijos en name
+func xmlMixedContentError(input string, offset int, name string) error {
+	return xmlSyntaxError(input, offset, fmt.Sprintf("el elemento <%s> mezcla texto y elementos hijos (contenido mixto no soportado en la convención attributes)", name))
+}
+
+// xmlQualifiedName nombre con su prefijo tal como aparece en el documento
+func xmlQualifiedName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+// xmlConverter convierte elementos al árbol de valores
+type xmlConverter struct {
+	parser    *Parser
+	opts      XMLOptions
+	parseOpts ParseOptions
+}
+
+// newObject crea un objeto según las opciones de parseo
+func (c *xmlConverter) newObject() interface{} {
+	if c.parseOpts.PreserveOrder {
+		return NewOrderedObject()
+	}
+	return map[string]interface{}{}
+}
+
+// addChild agrega un valor bajo key; a partir de la segunda aparición la
+// clave pasa a ser un array con todos los valores
+func addChild(object interface{}, key string, value interface{}) {
+	existing, ok := objectValues(object)[key]
+	if !ok {
+		setMember(object, key, value)
+		return
+	}
+	if items, isArray := existing.([]interface{}); isArray {
+		setMember(object, key, append(items, value))
+		return
+	}
+	setMember(object, key, []interface{}{existing, value})
+}
+
+// attributes aplica la convención attributes a un elemento
+func (c *xmlConverter) attributes(element *xmlElement) interface{} {
+	text := strings.TrimSpace(element.text.String())
+	if len(element.attrs) == 0 && len(element.children) == 0 {
+		if text == "" {
+			return nil
+		}
+		return text
+	}
+
+	object := c.newObject()
+	for _, attr := range element.attrs {
+		setMember(object, c.opts.AttributePrefix+attr.name, attr.value)
+	}
+	for _, child := range element.children {
+		addChild(object, child.name, c.attributes(child))
+	}
+	if text != "" {
+		setMember(object, c.opts.TextKey, text)
+	}
+	return object
+}
+
+// badgerFish aplica la convención BadgerFish a un elemento
+func (c *xmlConverter) badgerFish(element *xmlElement) interface{} {
+	object := c.newObject()
+	var namespaces interface{}
+	for _, attr := range element.attrs {
+		prefix, isNamespace := "", attr.name == "xmlns"
+		if strings.HasPrefix(attr.name, "xmlns:") {
+			prefix, isNamespace = strings.TrimPrefix(attr.name, "xmlns:"), true
+		}
+		if !isNamespace {
+			setMember(object, "@"+attr.name, attr.value)
+			continue
+		}
+		if namespaces == nil {
+			namespaces = c.newObject()
+			setMember(object, "@xmlns", namespaces)
+		}
+		if prefix == "" {
+			prefix = "$"
+		}
+		setMember(namespaces, prefix, attr.value)
+	}
+	if text := strings.TrimSpace(element.text.String()); text != "" {
+		setMember(object, "$", text)
+	}
+	for _, child := range element.children {
+		addChild(object, child.name, c.badgerFish(child))
+	}
+	return object
+}
+
+// parker aplica la convención Parker a un elemento
+func (c *xmlConverter) parker(element *xmlElement) (interface{}, error) {
+	if len(element.children) == 0 {
+		text := strings.TrimSpace(element.text.String())
+		if text == "" {
+			return nil, nil
+		}
+		return c.parkerScalar(text)
+	}
+
+	object := c.newObject()
+	for _, child := range element.children {
+		value, err := c.parker(child)
+		if err != nil {
+			return nil, err
+		}
+		addChild(object, child.name, value)
+	}
+	return object, nil
+}
+
+// parkerScalar convierte el texto de un elemento en booleano o número si
+// tiene esa forma exacta
+func (c *xmlConverter) parkerScalar(text string) (interface{}, error) {
+	switch text {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if csvFloatPattern.MatchString(text) {
+		return c.parser.convertNumberMode(text, c.parseOpts.NumberMode)
+	}
+	return text, nil
+}
+
+// MarshalXML escribe value como documento XML (con declaración y sangría de
+// dos espacios) siguiendo la convención indicada, de modo que ParseXML lo lea
+// de vuelta. Con attributes y BadgerFish un objeto con una sola clave da
+// nombre al elemento raíz; en otro caso se usa opts.RootName. Los arrays se
+// escriben como elementos repetidos y null como elemento vacío. En
+// attributes, un objeto con "#text" y elementos hijos es un error porque no
+// se lee de vuelta (ver ParseXML).
+func MarshalXML(value interface{}, opts XMLOptions) (string, error) {
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+	opts = opts.withDefaults()
+
+	w := &xmlWriter{opts: opts}
+	w.builder.WriteString(xml.Header)
+	name, content := opts.RootName, value
+	if opts.Convention != XMLConventionParker {
+		if keys := orderedKeys(value); len(keys) == 1 && !w.isSpecialKey(keys[0]) {
+			if _, isArray := objectValues(value)[keys[0]].([]interface{}); !isArray {
+				name, content = keys[0], objectValues(value)[keys[0]]
+			}
+		}
+	}
+	if items, isArray := content.([]interface{}); isArray {
+		// Un array en la raíz se escribe como elementos <item> dentro de la raíz
+		wrapper := NewOrderedObject()
+		wrapper.Set("item", items)
+		content = wrapper
+	}
+	if err := w.element(name, content, 0); err != nil {
+		return "", err
+	}
+	return w.builder.String(), nil
+}
+
+// xmlWriter escribe el árbol de valores como XML
+type xmlWriter struct {
+	builder strings.Builder
+	opts    XMLOptions
+}
+
+// isSpecialKey indica si key es un atributo o el texto del elemento
+func (w *xmlWriter) isSpecialKey(key string) bool {
+	if w.opts.Convention == XMLConventionParker {
+		return false
+	}
+	return key == w.opts.TextKey || strings.HasPrefix(key, w.opts.AttributePrefix)
+}
+
+// element escribe value como el elemento name (o uno por elemento si es array)
+func (w *xmlWriter) element(name string, value interface{}, depth int) error {
+	if !isXMLName(name) {
+		return fmt.Errorf("la clave '%s' no es un nombre de elemento XML válido", name)
+	}
+	if depth > maxNestingDepth {
+		return fmt.Errorf("profundidad máxima de anidación excedida (%d)", maxNestingDepth)
+	}
+	if items, isArray := value.([]interface{}); isArray {
+		for _, item := range items {
+			if err := w.element(name, item, depth); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	pad := strings.Repeat("  ", depth)
+	w.builder.WriteString(pad + "<" + name)
+	members := objectValues(value)
+	if members == nil {
+		// Escalar o null: el elemento contiene solo texto
+		if value == nil {
+			w.builder.WriteString("/>\n")
+			return nil
+		}
+		text, err := xmlScalarText(value, name)
+		if err != nil {
+			return err
+		}
+		w.builder.WriteString(">" + xmlEscape(text) + "</" + name + ">\n")
+		return nil
+	}
+
+	var text string
+	var children []string
+	for _, key := range orderedKeys(value) {
+		member := members[key]
+		switch {
+		case !w.isSpecialKey(key):
+			children = append(children, key)
+		case key == w.opts.TextKey:
+			var err error
+			if text, err = xmlScalarText(member, key); err != nil {
+				return err
+			}
+		case w.opts.Convention == XMLConventionBadgerFish && key == "@xmlns":
+			if err := w.namespaces(member); err != nil {
+				return err
+			}
+		default:
+			attrName := strings.TrimPrefix(key, w.opts.AttributePrefix)
+			if !isXMLName(attrName) {
+				return fmt.Errorf("la clave '%s' no es un nombre de atributo XML válido", key)
+			}
+			attrValue, err := xmlScalarText(member, key)
+			if err != nil {
+				return err
+			}
+			w.builder.WriteString(" " + attrName + "=\"" + xmlEscape(attrValue) + "\"")
+		}
+	}
+
+	if text != "" && len(children) > 0 && w.opts.Convention == XMLConventionAttributes {
+		return fmt.Errorf("el elemento <%s> no puede tener '%s' y elementos hijos en la convención attributes", name, w.opts.TextKey)
+	}
+	switch {
+	case len(children) == 0 && text == "":
+		w.builder.WriteString("/>\n")
+	case len(children) == 0:
+		w.builder.WriteString(">" + xmlEscape(text) + "</" + name + ">\n")
+	default:
+		w.builder.WriteString(">\n")
+		if text != "" {
+			w.builder.WriteString(pad + "  " + xmlEscape(text) + "\n")
+		}
+		for _, key := range children {
+			if err := w.element(key, members[key], depth+1); err != nil {
+				return err
+			}
+		}
+		w.builder.WriteString(pad + "</" + name + ">\n")
+	}
+	return nil
+}
+
+// namespaces escribe el objeto "@xmlns" de BadgerFish como declaraciones
+func (w *xmlWriter) namespaces(value interface{}) error {
+	members := objectValues(value)
+	if members == nil {
+		return fmt.Errorf("'@xmlns' debe ser un objeto de prefijos")
+	}
+	for _, prefix := range orderedKeys(value) {
+		uri, err := xmlScalarText(members[prefix], "@xmlns")
+		if err != nil {
+			return err
+		}
+		attrName := "xmlns"
+		if prefix != "$" {
+			attrName += ":" + prefix
+		}
+		w.builder.WriteString(" " + attrName + "=\"" + xmlEscape(uri) + "\"")
+	}
+	return nil
+}
+
+// xmlScalarText texto de un atributo o elemento; solo se aceptan escalares
+func xmlScalarText(value interface{}, key string) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	if objectValues(value) != nil {
+		return "", fmt.Errorf("el valor de '%s' debe ser un escalar, no un objeto", key)
+	}
+	if _, isArray := value.([]interface{}); isArray {
+		return "", fmt.Errorf("el valor de '%s' debe ser un escalar, no un array", key)
+	}
+	data, err := Marshal(value)
+	return string(data), err
+}
+
+// xmlEscape escapa texto para contenido y valores de atributos
+func xmlEscape(text string) string {
+	var builder strings.Builder
+	xml.EscapeText(&builder, []byte(text))
+	return builder.String()
+}
+
+// isXMLName indica si name es un nombre XML válido (con prefijo opcional)
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_' || r == ':':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.' || unicode.Is(unicode.Mn, r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
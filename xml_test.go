@@ -0,0 +1,194 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const xmlSample = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- catálogo -->
+<catalogo xmlns:ex="urn:ejemplo" version="2">
+  <libro id="1" disponible="true">
+    <titulo>Go &amp; XML</titulo>
+    <precio>19.90</precio>
+    <ex:tag>a</ex:tag>
+  </libro>
+  <libro id="2">
+    <titulo><![CDATA[<Avanzado>]]></titulo>
+    <precio>25</precio>
+    <nota/>
+  </libro>
+  <resumen tipo="breve">Dos libros</resumen>
+</catalogo>`
+
+// Test para las convenciones de XML → JSON
+func TestParseXML(t *testing.T) {
+	tests := []struct {
+		name string
+		opts XMLOptions
+		want string
+	}{
+		{"attributes", XMLOptions{}, `{"catalogo":{"@xmlns:ex":"urn:ejemplo","@version":"2","libro":[` +
+			`{"@id":"1","@disponible":"true","titulo":"Go & XML","precio":"19.90","ex:tag":"a"},` +
+			`{"@id":"2","titulo":"<Avanzado>","precio":"25","nota":null}],` +
+			`"resumen":{"@tipo":"breve","#text":"Dos libros"}}}`},
+		{"prefijos propios", XMLOptions{AttributePrefix: "-", TextKey: "=valor"}, `{"catalogo":{"-xmlns:ex":"urn:ejemplo","-version":"2","libro":[` +
+			`{"-id":"1","-disponible":"true","titulo":"Go & XML","precio":"19.90","ex:tag":"a"},` +
+			`{"-id":"2","titulo":"<Avanzado>","precio":"25","nota":null}],` +
+			`"resumen":{"-tipo":"breve","=valor":"Dos libros"}}}`},
+		{"badgerfish", XMLOptions{Convention: XMLConventionBadgerFish}, `{"catalogo":{"@xmlns":{"ex":"urn:ejemplo"},"@version":"2","libro":[` +
+			`{"@id":"1","@disponible":"true","titulo":{"$":"Go & XML"},"precio":{"$":"19.90"},"ex:tag":{"$":"a"}},` +
+			`{"@id":"2","titulo":{"$":"<Avanzado>"},"precio":{"$":"25"},"nota":{}}],` +
+			`"resumen":{"@tipo":"breve","$":"Dos libros"}}}`},
+		{"parker", XMLOptions{Convention: XMLConventionParker}, `{"libro":[` +
+			`{"titulo":"Go & XML","precio":19.90,"ex:tag":"a"},` +
+			`{"titulo":"<Avanzado>","precio":25,"nota":null}],` +
+			`"resumen":"Dos libros"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := NewParser().ParseXML(xmlSample, tt.opts, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+			if err != nil {
+				t.Fatalf("ParseXML() error = %v", err)
+			}
+			if got := string(mustMarshal(t, value)); got != tt.want {
+				t.Errorf("ParseXML() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test para los errores de XML con posición
+func TestParseXMLErrors(t *testing.T) {
+	tests := []struct {
+		name, input, wantErr string
+		line                 int
+	}{
+		{"vacío", "  ", "no tiene elemento raíz", 1},
+		{"sin cerrar", "<a>\n  <b>x</b>\n", "el elemento <a> no está cerrado", 3},
+		{"cierre distinto", "<a>\n<b></a>", "</a> no coincide con <b>", 2},
+		{"dos raíces", "<a/>\n<b/>", "más de un elemento raíz", 2},
+		{"texto suelto", "<a/>texto", "texto fuera del elemento raíz", 1},
+		{"sintaxis", "<a>\n<b =\"x\"/></a>", "XML inválido", 2},
+		{"entidad desconocida", "<a>&nada;</a>", "XML inválido", 1},
+		{"texto antes de un hijo", "<a>1<b/>2</a>", "el elemento <a> mezcla texto y elementos hijos", 1},
+		{"texto después de un hijo", "<a>\n  <b/>\n  fin\n</a>", "el elemento <a> mezcla texto y elementos hijos", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser().ParseXML(tt.input, XMLOptions{}, ParseOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseXML() error = %v, want %q", err, tt.wantErr)
+			}
+			if syntaxErr := AsSyntaxError(err); syntaxErr == nil || syntaxErr.Line != tt.line {
+				t.Errorf("posición = %+v, want línea %d", syntaxErr, tt.line)
+			}
+		})
+	}
+
+	for _, convention := range []string{XMLConventionBadgerFish, XMLConventionParker} {
+		if _, err := NewParser().ParseXML("<a>1<b/>2</a>", XMLOptions{Convention: convention}, ParseOptions{}); err != nil {
+			t.Errorf("ParseXML(%s) con contenido mixto error = %v", convention, err)
+		}
+	}
+	if _, err := NewParser().ParseXML("<a/>", XMLOptions{Convention: "gdata"}, ParseOptions{}); err == nil || !strings.Contains(err.Error(), "convención XML no soportada 'gdata'") {
+		t.Errorf("ParseXML() con convención desconocida error = %v", err)
+	}
+	if _, err := NewParser().ParseXML("<a/>", XMLOptions{AttributePrefix: "#"}, ParseOptions{}); err == nil {
+		t.Error("se esperaba error: la clave de texto empieza con el prefijo de atributos")
+	}
+	for opts, wantErr := range map[XMLOptions]string{
+		{AttributePrefix: "_"}:    "el prefijo de atributos '_' puede formar un nombre de elemento",
+		{AttributePrefix: "attr"}: "el prefijo de atributos 'attr' puede formar un nombre de elemento",
+		{TextKey: "text"}:         "la clave de texto 'text' es un nombre de elemento XML válido",
+	} {
+		if _, err := NewParser().ParseXML(`<r id="1"><_id>3</_id><text/></r>`, opts, ParseOptions{}); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseXML(%+v) error = %v, want %q", opts, err, wantErr)
+		}
+		if _, err := MarshalXML(map[string]interface{}{"r": nil}, opts); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("MarshalXML(%+v) error = %v, want %q", opts, err, wantErr)
+		}
+	}
+}
+
+// Test para JSON → XML y su lectura de vuelta con la misma convención
+func TestMarshalXML(t *testing.T) {
+	tests := []struct {
+		name      string
+		json      string
+		opts      XMLOptions
+		want      string
+		roundTrip bool // ParseXML devuelve el mismo valor
+	}{
+		{"attributes", `{"pedido":{"@id":"7","cliente":"Ana & Co","items":[{"@sku":"A1","#text":"lápiz"},{"@sku":"B2","#text":"goma"}],"nota":null}}`, XMLOptions{},
+			"<pedido id=\"7\">\n  <cliente>Ana &amp; Co</cliente>\n  <items sku=\"A1\">lápiz</items>\n  <items sku=\"B2\">goma</items>\n  <nota/>\n</pedido>\n", true},
+		{"raíz por defecto", `{"a":1,"b":[true,false]}`, XMLOptions{RootName: "datos"},
+			"<datos>\n  <a>1</a>\n  <b>true</b>\n  <b>false</b>\n</datos>\n", false},
+		{"badgerfish", `{"x:doc":{"@xmlns":{"$":"urn:d","x":"urn:x"},"$":"hola","p":[{"$":"1"},{"$":"2"}]}}`, XMLOptions{Convention: XMLConventionBadgerFish},
+			"<x:doc xmlns=\"urn:d\" xmlns:x=\"urn:x\">\n  hola\n  <p>1</p>\n  <p>2</p>\n</x:doc>\n", true},
+		{"parker", `{"nombre":"Ana","edad":30,"tags":["a","b"]}`, XMLOptions{Convention: XMLConventionParker},
+			"<root>\n  <nombre>Ana</nombre>\n  <edad>30</edad>\n  <tags>a</tags>\n  <tags>b</tags>\n</root>\n", true},
+		{"array en la raíz", `[{"a":1},{"a":2}]`, XMLOptions{Convention: XMLConventionParker},
+			"<root>\n  <item>\n    <a>1</a>\n  </item>\n  <item>\n    <a>2</a>\n  </item>\n</root>\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := parseExact(t, tt.json)
+			got, err := MarshalXML(value, tt.opts)
+			if err != nil {
+				t.Fatalf("MarshalXML() error = %v", err)
+			}
+			if want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + tt.want; got != want {
+				t.Errorf("MarshalXML() =\n%s\nwant\n%s", got, want)
+			}
+
+			back, err := NewParser().ParseXML(got, tt.opts, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+			if err != nil {
+				t.Fatalf("ParseXML(MarshalXML()) error = %v", err)
+			}
+			if tt.roundTrip && !EqualValues(back, value) {
+				t.Errorf("ParseXML(MarshalXML()) = %s, want %s", mustMarshal(t, back), tt.json)
+			}
+		})
+	}
+}
+
+// Test para los valores que no se pueden escribir como XML
+func TestMarshalXMLErrors(t *testing.T) {
+	tests := map[string]string{
+		`{"1abc":"x"}`:                "no es un nombre de elemento XML válido",
+		`{"a b":"x"}`:                 "no es un nombre de elemento XML válido",
+		`{"a":{"@id":{"x":1}}}`:       "debe ser un escalar, no un objeto",
+		`{"a":{"@bad name":"x"}}`:     "no es un nombre de atributo XML válido",
+		`{"a":{"#text":[1],"b":"c"}}`: "debe ser un escalar, no un array",
+		`{"a":{"#text":"1","b":"c"}}`: "no puede tener '#text' y elementos hijos",
+	}
+	for input, wantErr := range tests {
+		if _, err := MarshalXML(parseExact(t, input), XMLOptions{}); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("MarshalXML(%s) error = %v, want %q", input, err, wantErr)
+		}
+	}
+}
+
+// Test: el XML embebido compila en todas las convenciones
+func TestConvertXMLToGoLiteral(t *testing.T) {
+	for _, convention := range xmlConventions {
+		for _, typed := range []bool{false, true} {
+			source, err := convertXMLToGoLiteral(xmlSample, "datos", "catalogo", "catalogo.xml", typed, XMLOptions{Convention: convention})
+			if err != nil {
+				t.Fatalf("convertXMLToGoLiteral(%s, typed=%v) error = %v", convention, typed, err)
+			}
+			formatted, _, err := formatGoSource(source, "catalogo_data.go")
+			if err != nil {
+				t.Fatalf("formatGoSource() error = %v\n%s", err, source)
+			}
+			typeCheckGo(t, formatted)
+			if !strings.Contains(formatted, "convención "+convention) {
+				t.Errorf("falta la convención en el encabezado:\n%s", formatted)
+			}
+		}
+	}
+}
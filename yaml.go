@@ -0,0 +1,1389 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxYAMLAliasNodes límite de nodos generados por la expansión de alias,
+// contra documentos del tipo "billion laughs"
+const maxYAMLAliasNodes = 1000000
+
+var (
+	yamlIntPattern   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlOctalPattern = regexp.MustCompile(`^0o[0-7]+$`)
+	yamlHexPattern   = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+	yamlFloatPattern = regexp.MustCompile(`^([-+]?)(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+	yamlSpecialFloat = regexp.MustCompile(`^([-+]?\.(inf|Inf|INF)|\.(nan|NaN|NAN))$`)
+)
+
+// yamlScalar escalar leído del documento antes de resolver su tipo
+type yamlScalar struct {
+	text  string
+	plain bool // sin comillas ni bloque: se resuelve con el esquema core
+}
+
+// yamlParser estado de un parseo YAML
+type yamlParser struct {
+	parser *Parser
+	src    string
+	pos    int
+	opts   ParseOptions
+
+	depth      int
+	nodes      int                    // nodos construidos (incluye expansiones de alias)
+	anchors    map[string]interface{} // valor de cada ancla
+	anchorSize map[string]int         // nodos que agrega expandir cada ancla
+}
+
+// ParseYAML parsea un documento YAML 1.2 (esquema core) y devuelve el mismo
+// árbol de valores que ParseJSONWithOptions: mapeos como objetos (las claves
+// escalares se usan como texto), secuencias como arrays y escalares resueltos
+// a null, bool, número o string. Una entrada vacía es null; si hay varios
+// documentos use ParseYAMLDocuments.
+func (p *Parser) ParseYAML(input string, opts ParseOptions) (interface{}, error) {
+	docs, err := p.ParseYAMLDocuments(input, opts)
+	if err != nil {
+		return nil, err
+	}
+	switch len(docs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return docs[0], nil
+	}
+	return nil, fmt.Errorf("el YAML contiene %d documentos; se esperaba uno solo", len(docs))
+}
+
+// ParseYAMLDocuments parsea un flujo YAML con uno o más documentos separados
+// por '---' (y opcionalmente terminados en '...'). Soporta mapeos y secuencias
+// de bloque y flow, escalares planos, entre comillas y de bloque (| y >),
+// comentarios, anclas (&), alias (*) y las etiquetas estándar (!!str, !!int...).
+func (p *Parser) ParseYAMLDocuments(input string, opts ParseOptions) ([]interface{}, error) {
+	y := &yamlParser{parser: p, src: strings.TrimPrefix(input, "\uFEFF"), opts: opts}
+	var docs []interface{}
+	for {
+		y.skipDirectives()
+		if y.eof() {
+			return docs, nil
+		}
+
+		explicit := y.atMarker("---")
+		if explicit {
+			y.pos += 3
+		}
+		y.anchors, y.anchorSize = map[string]interface{}{}, map[string]int{}
+		value, err := y.parseBlockNode(-1, false)
+		if err != nil {
+			return nil, err
+		}
+
+		y.skipBlank()
+		if y.atMarker("...") {
+			y.pos += 3
+			if err := y.expectLineEnd(); err != nil {
+				return nil, err
+			}
+			y.skipBlank()
+		}
+		if !y.eof() && !y.atMarker("---") && !y.atDirective() {
+			return nil, y.errorf(y.pos, "contenido inesperado después del documento")
+		}
+		docs = append(docs, value)
+	}
+}
+
+// errorf crea un SyntaxError en offset
+func (y *yamlParser) errorf(offset int, format string, args ...interface{}) error {
+	return newSyntaxError([]byte(y.src), offset, "", "YAML inválido: "+fmt.Sprintf(format, args...))
+}
+
+func (y *yamlParser) eof() bool {
+	return y.pos >= len(y.src)
+}
+
+func (y *yamlParser) peek() byte {
+	if y.eof() {
+		return 0
+	}
+	return y.src[y.pos]
+}
+
+// peekAt devuelve el byte en pos+offset (0 fuera de rango)
+func (y *yamlParser) peekAt(offset int) byte {
+	if y.pos+offset >= len(y.src) {
+		return 0
+	}
+	return y.src[y.pos+offset]
+}
+
+// isBlankAt indica si en offset hay espacio, tab, fin de línea o fin de entrada
+func (y *yamlParser) isBlankAt(offset int) bool {
+	switch y.peekAt(offset) {
+	case 0, ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
+
+// column columna (en caracteres, desde 0) de un offset
+func (y *yamlParser) column(offset int) int {
+	start := strings.LastIndexByte(y.src[:offset], '\n') + 1
+	return utf8.RuneCountInString(y.src[start:offset])
+}
+
+// atMarker indica si hay un marcador de documento ('---' o '...') en la posición
+func (y *yamlParser) atMarker(marker string) bool {
+	return y.column(y.pos) == 0 && strings.HasPrefix(y.src[y.pos:], marker) && y.isBlankAt(3)
+}
+
+// atDocumentBoundary indica si la posición está en un marcador de documento
+func (y *yamlParser) atDocumentBoundary() bool {
+	return y.atMarker("---") || y.atMarker("...")
+}
+
+func (y *yamlParser) atDirective() bool {
+	return y.column(y.pos) == 0 && y.peek() == '%'
+}
+
+// skipDirectives saltea líneas vacías, comentarios y directivas (%YAML, %TAG)
+func (y *yamlParser) skipDirectives() {
+	for {
+		y.skipBlank()
+		if !y.atDirective() {
+			return
+		}
+		y.skipLine()
+	}
+}
+
+// skipLine avanza hasta el comienzo de la línea siguiente
+func (y *yamlParser) skipLine() {
+	if i := strings.IndexByte(y.src[y.pos:], '\n'); i >= 0 {
+		y.pos += i + 1
+	} else {
+		y.pos = len(y.src)
+	}
+}
+
+// skipSpaces saltea espacios y tabs dentro de la línea
+func (y *yamlParser) skipSpaces() {
+	for y.peek() == ' ' || y.peek() == '\t' {
+		y.pos++
+	}
+}
+
+// skipComment saltea un comentario hasta el fin de línea (sin consumirlo)
+func (y *yamlParser) skipComment() {
+	if y.peek() == '#' {
+		for !y.eof() && y.peek() != '\n' {
+			y.pos++
+		}
+	}
+}
+
+// skipBlank saltea espacios, comentarios y saltos de línea; indica si cruzó
+// al menos un salto de línea
+func (y *yamlParser) skipBlank() bool {
+	crossed := false
+	for !y.eof() {
+		y.skipSpaces()
+		y.skipComment()
+		switch y.peek() {
+		case '\r':
+			y.pos++
+		case '\n':
+			y.pos++
+			crossed = true
+		default:
+			return crossed
+		}
+	}
+	return crossed
+}
+
+// expectLineEnd verifica que solo queden espacios o un comentario en la línea
+func (y *yamlParser) expectLineEnd() error {
+	y.skipSpaces()
+	y.skipComment()
+	if !y.eof() && y.peek() != '\n' && y.peek() != '\r' {
+		return y.errorf(y.pos, "contenido inesperado después del valor")
+	}
+	return nil
+}
+
+// checkIndentation rechaza tabs en la sangría de la línea actual (YAML 1.2
+// §6.1): solo aplica si la posición es el primer contenido de la línea
+func (y *yamlParser) checkIndentation() error {
+	start := strings.LastIndexByte(y.src[:y.pos], '\n') + 1
+	if i := strings.IndexByte(y.src[start:y.pos], '\t'); i >= 0 && strings.TrimLeft(y.src[start:y.pos], " \t") == "" {
+		return y.errorf(start+i, "no se permiten tabs en la sangría")
+	}
+	return nil
+}
+
+// enter controla la profundidad de anidación
+func (y *yamlParser) enter() error {
+	y.depth++
+	if y.depth > maxNestingDepth {
+		return y.errorf(y.pos, "profundidad máxima de anidación excedida (%d)", maxNestingDepth)
+	}
+	return nil
+}
+
+// newObject crea un objeto según las opciones de parseo
+func (y *yamlParser) newObject() interface{} {
+	if y.opts.PreserveOrder {
+		return NewOrderedObject()
+	}
+	return map[string]interface{}{}
+}
+
+// setKey agrega una clave rechazando duplicados
+func (y *yamlParser) setKey(object interface{}, key string, value interface{}, offset int) error {
+	if _, exists := objectValues(object)[key]; exists {
+		return y.errorf(offset, "clave duplicada: %s", key)
+	}
+	setMember(object, key, value)
+	return nil
+}
+
+// yamlProperties ancla y etiqueta que preceden a un nodo
+type yamlProperties struct {
+	anchor string
+	tag    string
+}
+
+// parseProperties lee las propiedades (&ancla, !etiqueta) en la posición
+func (y *yamlParser) parseProperties() yamlProperties {
+	var props yamlProperties
+	for y.peek() == '&' || y.peek() == '!' {
+		start := y.pos
+		for !y.isBlankAt(0) && !(y.peek() == ',' || y.peek() == ']' || y.peek() == '}') {
+			y.pos++
+		}
+		if y.src[start] == '&' {
+			props.anchor = y.src[start+1 : y.pos]
+		} else {
+			props.tag = y.src[start:y.pos]
+		}
+		y.skipSpaces()
+	}
+	return props
+}
+
+// finish registra el ancla del nodo y cuenta sus nodos
+func (y *yamlParser) finish(props yamlProperties, value interface{}, nodesBefore int) interface{} {
+	if props.anchor != "" {
+		y.anchors[props.anchor] = value
+		y.anchorSize[props.anchor] = y.nodes - nodesBefore
+	}
+	return value
+}
+
+// parseAlias expande un alias (*nombre) con una copia del valor anclado
+func (y *yamlParser) parseAlias() (interface{}, error) {
+	start := y.pos
+	name, err := y.parseAliasName()
+	if err != nil {
+		return nil, err
+	}
+	value, ok := y.anchors[name]
+	if !ok {
+		return nil, y.errorf(start, "alias '*%s' sin ancla definida", name)
+	}
+	y.nodes += y.anchorSize[name]
+	if y.nodes > maxYAMLAliasNodes {
+		return nil, y.errorf(start, "la expansión de alias supera el límite de %d nodos", maxYAMLAliasNodes)
+	}
+	return cloneValue(value), nil
+}
+
+// parseBlockNode parsea un nodo en contexto de bloque cuyo contenido debe
+// estar más indentado que parentIndent. En el valor de un mapeo (mapValue) se
+// acepta una secuencia con la misma sangría que la clave, pero no una
+// colección de bloque en la misma línea que la clave.
+func (y *yamlParser) parseBlockNode(parentIndent int, mapValue bool) (interface{}, error) {
+	newLine := y.skipBlank() || y.column(y.pos) == 0
+	if y.eof() || y.atDocumentBoundary() {
+		return nil, nil
+	}
+	// En la raíz un tab antes de una colección flow es separación, no sangría
+	if c := y.peek(); parentIndent >= 0 || (c != '[' && c != '{') {
+		if err := y.checkIndentation(); err != nil {
+			return nil, err
+		}
+	}
+	column := y.column(y.pos)
+	if column <= parentIndent {
+		if mapValue && column == parentIndent && y.peek() == '-' && y.isBlankAt(1) {
+			return y.parseBlockSequence(column)
+		}
+		return nil, nil
+	}
+
+	nodesBefore := y.nodes
+	propsStart := y.pos
+	props := y.parseProperties()
+	if props.anchor != "" || props.tag != "" {
+		if y.peek() == '#' || y.peek() == '\n' || y.peek() == '\r' || y.eof() {
+			// Propiedades solas en la línea: el contenido sigue debajo
+			value, err := y.parseBlockNode(parentIndent, mapValue)
+			if err != nil {
+				return nil, err
+			}
+			value, err = y.applyTag(props.tag, value, propsStart)
+			if err != nil {
+				return nil, err
+			}
+			return y.finish(props, value, nodesBefore), nil
+		}
+		if y.isImplicitKey() {
+			// Las propiedades pertenecen a la primera clave del mapeo
+			y.pos = propsStart
+			return y.parseBlockMapping(column)
+		}
+	}
+
+	switch c := y.peek(); {
+	case c == '-' && y.isBlankAt(1):
+		if mapValue && !newLine {
+			return nil, y.errorf(y.pos, "una secuencia de bloque no puede empezar en la línea de la clave")
+		}
+		value, err := y.parseBlockSequence(column)
+		if err != nil {
+			return nil, err
+		}
+		return y.finish(props, value, nodesBefore), nil
+	case c == '?' && y.isBlankAt(1):
+		return nil, y.errorf(y.pos, "las claves complejas ('?') no están soportadas")
+	case c == '|' || c == '>':
+		text, err := y.parseBlockScalar(parentIndent)
+		if err != nil {
+			return nil, err
+		}
+		value, err := y.resolve(yamlScalar{text: text}, props.tag, propsStart)
+		if err != nil {
+			return nil, err
+		}
+		return y.finish(props, value, nodesBefore), nil
+	}
+
+	if y.isImplicitKey() {
+		if mapValue && !newLine {
+			return nil, y.errorf(y.pos, "no se permite un mapeo de bloque en la línea de la clave")
+		}
+		return y.parseBlockMapping(column)
+	}
+
+	var value interface{}
+	var err error
+	switch c := y.peek(); {
+	case c == '[' || c == '{':
+		value, err = y.parseFlowNode()
+		if err == nil {
+			value, err = y.applyTag(props.tag, value, propsStart)
+		}
+		if err == nil {
+			err = y.expectLineEnd()
+		}
+	case c == '*':
+		value, err = y.parseAlias()
+		if err == nil {
+			err = y.expectLineEnd()
+		}
+	case c == '"' || c == '\'':
+		var scalar yamlScalar
+		scalar, err = y.parseQuoted()
+		if err == nil {
+			value, err = y.resolve(scalar, props.tag, propsStart)
+		}
+		if err == nil {
+			err = y.expectLineEnd()
+		}
+	default:
+		var scalar yamlScalar
+		scalar, err = y.parsePlain(parentIndent, false)
+		if err == nil {
+			value, err = y.resolve(scalar, props.tag, propsStart)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	y.nodes++
+	return y.finish(props, value, nodesBefore), nil
+}
+
+// isImplicitKey indica si en la posición empieza una clave de mapeo de
+// bloque: un escalar de una línea seguido de ':' y un espacio o fin de línea
+func (y *yamlParser) isImplicitKey() bool {
+	start := y.pos
+	defer func() { y.pos = start }()
+
+	y.parseProperties()
+	switch y.peek() {
+	case '"', '\'':
+		if _, err := y.parseQuoted(); err != nil {
+			return false
+		}
+	case '*':
+		if _, err := y.parseAliasName(); err != nil {
+			return false
+		}
+	case '[', '{', '-', '?', '|', '>', '#', '\n', '\r', 0:
+		if y.peek() != '-' || y.isBlankAt(1) {
+			return false
+		}
+		fallthrough
+	default:
+		if _, err := y.parsePlainKey(); err != nil {
+			return false
+		}
+	}
+	y.skipSpaces()
+	return y.peek() == ':' && y.isBlankAt(1) && !strings.Contains(y.src[start:y.pos], "\n")
+}
+
+// parseAliasName avanza sobre un alias sin expandirlo
+func (y *yamlParser) parseAliasName() (string, error) {
+	start := y.pos
+	y.pos++
+	for !y.isFlowBlankAt(0) {
+		y.pos++
+	}
+	if y.pos == start+1 {
+		return "", y.errorf(start, "alias sin nombre")
+	}
+	return y.src[start+1 : y.pos], nil
+}
+
+// parseBlockMapping parsea un mapeo de bloque cuyas claves están en column
+func (y *yamlParser) parseBlockMapping(column int) (interface{}, error) {
+	if err := y.enter(); err != nil {
+		return nil, err
+	}
+	object := y.newObject()
+	y.nodes++
+	for {
+		keyStart := y.pos
+		nodesBefore := y.nodes
+		props := y.parseProperties()
+
+		var key string
+		switch y.peek() {
+		case '"', '\'':
+			scalar, err := y.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			key = scalar.text
+		case '*':
+			value, err := y.parseAlias()
+			if err != nil {
+				return nil, err
+			}
+			text, ok := value.(string)
+			if !ok {
+				raw, _ := Marshal(value)
+				text = string(raw)
+			}
+			key = text
+		default:
+			text, err := y.parsePlainKey()
+			if err != nil {
+				return nil, err
+			}
+			key = text
+		}
+		y.finish(props, key, nodesBefore)
+
+		y.skipSpaces()
+		if y.peek() != ':' || !y.isBlankAt(1) {
+			return nil, y.errorf(y.pos, "se esperaba ':' después de la clave '%s'", key)
+		}
+		y.pos++
+
+		value, err := y.parseBlockNode(column, true)
+		if err != nil {
+			return nil, err
+		}
+		if err := y.setKey(object, key, value, keyStart); err != nil {
+			return nil, err
+		}
+
+		y.skipBlank()
+		if y.eof() || y.atDocumentBoundary() {
+			break
+		}
+		if err := y.checkIndentation(); err != nil {
+			return nil, err
+		}
+		next := y.column(y.pos)
+		if next < column {
+			break
+		}
+		if next > column {
+			return nil, y.errorf(y.pos, "sangría inesperada")
+		}
+		if !y.isImplicitKey() {
+			return nil, y.errorf(y.pos, "se esperaba una clave de mapeo ('clave: valor')")
+		}
+	}
+	y.depth--
+	return object, nil
+}
+
+// parseBlockSequence parsea una secuencia de bloque con los '-' en column
+func (y *yamlParser) parseBlockSequence(column int) (interface{}, error) {
+	if err := y.enter(); err != nil {
+		return nil, err
+	}
+	items := []interface{}{}
+	y.nodes++
+	for {
+		y.pos++ // '-'
+		item, err := y.parseBlockNode(column, false)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		y.skipBlank()
+		if y.eof() || y.atDocumentBoundary() {
+			break
+		}
+		if err := y.checkIndentation(); err != nil {
+			return nil, err
+		}
+		next := y.column(y.pos)
+		if next < column || (next == column && !(y.peek() == '-' && y.isBlankAt(1))) {
+			break
+		}
+		if next > column {
+			return nil, y.errorf(y.pos, "sangría inesperada")
+		}
+	}
+	y.depth--
+	return items, nil
+}
+
+// parsePlainKey lee una clave plana de una línea, hasta ': '
+func (y *yamlParser) parsePlainKey() (string, error) {
+	start := y.pos
+	for !y.eof() {
+		c := y.peek()
+		if c == '\n' || c == '\r' || (c == ':' && y.isBlankAt(1)) || (c == '#' && y.pos > start && (y.src[y.pos-1] == ' ' || y.src[y.pos-1] == '\t')) {
+			break
+		}
+		y.pos++
+	}
+	key := strings.TrimRight(y.src[start:y.pos], " \t")
+	if key == "" {
+		return "", y.errorf(start, "clave vacía")
+	}
+	return key, nil
+}
+
+// parsePlain lee un escalar plano; en bloque puede continuar en líneas más
+// indentadas que parentIndent, que se pliegan con un espacio (o con saltos de
+// línea si hay líneas vacías entre ellas). En flow termina en los indicadores
+// , [ ] { }.
+func (y *yamlParser) parsePlain(parentIndent int, flow bool) (yamlScalar, error) {
+	start := y.pos
+	if c := y.peek(); c == '@' || c == '`' {
+		return yamlScalar{}, y.errorf(y.pos, "el carácter '%c' está reservado y no puede iniciar un escalar", c)
+	}
+
+	var builder strings.Builder
+	line := y.plainLine(flow)
+	builder.WriteString(line)
+	if line == "" {
+		return yamlScalar{}, y.errorf(start, "se esperaba un valor")
+	}
+
+	for {
+		end := y.pos
+		breaks := 0
+		for !y.eof() {
+			y.skipSpaces()
+			if y.peek() == '\r' {
+				y.pos++
+			}
+			if y.peek() != '\n' {
+				break
+			}
+			y.pos++
+			breaks++
+		}
+		column := y.column(y.pos)
+		stop := breaks == 0 || y.eof() || y.peek() == '#' || y.atDocumentBoundary()
+		if !flow {
+			stop = stop || column <= parentIndent || y.isImplicitKey()
+		} else {
+			stop = stop || strings.ContainsRune(",[]{}", rune(y.peek())) || (y.peek() == ':' && y.isFlowBlankAt(1))
+		}
+		if stop {
+			y.pos = end
+			break
+		}
+		if !flow {
+			// La continuación necesita parentIndent+1 espacios; recién
+			// después de ellos se admiten tabs como separación
+			lineStart := strings.LastIndexByte(y.src[:y.pos], '\n') + 1
+			prefix := y.src[lineStart:y.pos]
+			if tab := strings.IndexByte(prefix, '\t'); tab >= 0 && tab <= parentIndent {
+				return yamlScalar{}, y.errorf(lineStart+tab, "no se permiten tabs en la sangría")
+			}
+		}
+		next := y.plainLine(flow)
+		if next == "" {
+			y.pos = end
+			break
+		}
+		if breaks == 1 {
+			builder.WriteString(" ")
+		} else {
+			builder.WriteString(strings.Repeat("\n", breaks-1))
+		}
+		builder.WriteString(next)
+	}
+	return yamlScalar{text: builder.String(), plain: true}, nil
+}
+
+// isFlowBlankAt como isBlankAt pero también acepta indicadores flow
+func (y *yamlParser) isFlowBlankAt(offset int) bool {
+	return y.isBlankAt(offset) || strings.ContainsRune(",[]{}", rune(y.peekAt(offset)))
+}
+
+// plainLine lee el tramo de un escalar plano dentro de la línea actual
+func (y *yamlParser) plainLine(flow bool) string {
+	start := y.pos
+	for !y.eof() {
+		c := y.peek()
+		if c == '\n' || c == '\r' {
+			break
+		}
+		if c == '#' && y.pos > start && (y.src[y.pos-1] == ' ' || y.src[y.pos-1] == '\t') {
+			break
+		}
+		if c == ':' && (y.isBlankAt(1) || (flow && y.isFlowBlankAt(1))) {
+			break
+		}
+		if flow && strings.ContainsRune(",[]{}", rune(c)) {
+			break
+		}
+		y.pos++
+	}
+	text := strings.TrimRight(y.src[start:y.pos], " \t")
+	y.pos = start + len(text)
+	return text
+}
+
+// parseQuoted lee un escalar entre comillas simples o dobles; los saltos de
+// línea dentro de las comillas se pliegan como en los escalares planos
+func (y *yamlParser) parseQuoted() (yamlScalar, error) {
+	start := y.pos
+	quote := y.peek()
+	y.pos++
+
+	var buf []byte
+	keep := 0 // los espacios escapados no se recortan al plegar líneas
+	for {
+		if y.eof() {
+			return yamlScalar{}, y.errorf(start, "escalar entre comillas sin cerrar")
+		}
+		c := y.peek()
+		switch {
+		case c == quote && quote == '\'' && y.peekAt(1) == '\'':
+			buf = append(buf, '\'')
+			y.pos += 2
+			keep = len(buf)
+		case c == quote:
+			y.pos++
+			return yamlScalar{text: string(buf)}, nil
+		case c == '\\' && quote == '"':
+			if y.peekAt(1) == '\n' || (y.peekAt(1) == '\r' && y.peekAt(2) == '\n') {
+				// Salto de línea escapado: une las líneas sin espacio
+				y.pos++
+				y.skipLineBreak()
+				y.skipSpaces()
+				keep = len(buf)
+				continue
+			}
+			text, err := y.parseEscape()
+			if err != nil {
+				return yamlScalar{}, err
+			}
+			buf = append(buf, text...)
+			keep = len(buf)
+		case c == '\n' || c == '\r':
+			for len(buf) > keep && (buf[len(buf)-1] == ' ' || buf[len(buf)-1] == '\t') {
+				buf = buf[:len(buf)-1]
+			}
+			breaks := 0
+			for !y.eof() && (y.peek() == '\n' || y.peek() == '\r') {
+				y.skipLineBreak()
+				breaks++
+				y.skipSpaces()
+			}
+			if y.atDocumentBoundary() {
+				return yamlScalar{}, y.errorf(start, "escalar entre comillas sin cerrar")
+			}
+			if breaks == 1 {
+				buf = append(buf, ' ')
+			} else {
+				buf = append(buf, strings.Repeat("\n", breaks-1)...)
+			}
+		default:
+			buf = append(buf, c)
+			y.pos++
+		}
+	}
+}
+
+// skipLineBreak consume un \n o \r\n
+func (y *yamlParser) skipLineBreak() {
+	if y.peek() == '\r' {
+		y.pos++
+	}
+	if y.peek() == '\n' {
+		y.pos++
+	}
+}
+
+// yamlEscapes escapes de un carácter en escalares entre comillas dobles
+var yamlEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v",
+	'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\",
+	'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+// parseEscape interpreta una secuencia de escape de comillas dobles
+func (y *yamlParser) parseEscape() (string, error) {
+	start := y.pos
+	c := y.peekAt(1)
+	if text, ok := yamlEscapes[c]; ok {
+		y.pos += 2
+		return text, nil
+	}
+	size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+	if size == 0 {
+		return "", y.errorf(start, "secuencia de escape inválida '\\%c'", c)
+	}
+	if y.pos+2+size > len(y.src) {
+		return "", y.errorf(start, "secuencia de escape incompleta")
+	}
+	code, err := strconv.ParseUint(y.src[y.pos+2:y.pos+2+size], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return "", y.errorf(start, "secuencia de escape inválida '%s'", y.src[y.pos:y.pos+2+size])
+	}
+	y.pos += 2 + size
+	return string(rune(code)), nil
+}
+
+// parseBlockScalar lee un escalar literal (|) o plegado (>) con sus
+// indicadores opcionales de sangría (1-9) y de chomping (+ o -)
+func (y *yamlParser) parseBlockScalar(parentIndent int) (string, error) {
+	folded := y.peek() == '>'
+	y.pos++
+	chomping, explicitIndent := byte(0), 0
+	for i := 0; i < 2; i++ {
+		switch c := y.peek(); {
+		case (c == '+' || c == '-') && chomping == 0:
+			chomping = c
+			y.pos++
+		case c >= '1' && c <= '9' && explicitIndent == 0:
+			explicitIndent = int(c - '0')
+			y.pos++
+		}
+	}
+	if y.peek() != ' ' && y.peek() != '\t' && y.peek() != '#' && y.peek() != '\n' && y.peek() != '\r' && !y.eof() {
+		return "", y.errorf(y.pos, "indicador de escalar de bloque inválido")
+	}
+	if err := y.expectLineEnd(); err != nil {
+		return "", err
+	}
+	y.skipLineBreak()
+
+	indent := -1
+	if explicitIndent > 0 {
+		indent = parentIndent + explicitIndent
+	}
+
+	// Líneas del contenido (sin la sangría) hasta la primera menos indentada
+	var lines []string
+	for !y.eof() {
+		lineStart := y.pos
+		lineEnd := strings.IndexByte(y.src[y.pos:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(y.src)
+		} else {
+			lineEnd += y.pos
+		}
+		line := strings.TrimSuffix(y.src[lineStart:lineEnd], "\r")
+		spaces := len(line) - len(strings.TrimLeft(line, " "))
+
+		if y.atDocumentBoundary() {
+			break
+		}
+		if strings.TrimLeft(line, " ") == "" {
+			if indent >= 0 && spaces > indent {
+				lines = append(lines, line[indent:])
+			} else {
+				lines = append(lines, "")
+			}
+		} else {
+			if indent < 0 {
+				indent = spaces
+				if indent <= parentIndent {
+					break
+				}
+			}
+			if spaces < indent {
+				break
+			}
+			lines = append(lines, line[indent:])
+		}
+		y.pos = lineEnd
+		if y.pos < len(y.src) {
+			y.pos++
+		}
+	}
+
+	// Separar las líneas vacías finales para aplicar el chomping
+	content := len(lines)
+	for content > 0 && strings.TrimSpace(lines[content-1]) == "" {
+		content--
+	}
+	trailing := len(lines) - content
+	body := lines[:content]
+
+	var text string
+	if folded {
+		text = foldBlockLines(body)
+	} else {
+		text = strings.Join(body, "\n")
+	}
+
+	switch {
+	case chomping == '-':
+	case chomping == '+':
+		if content > 0 {
+			text += "\n"
+		}
+		text += strings.Repeat("\n", trailing)
+	case content > 0:
+		text += "\n"
+	}
+	return text, nil
+}
+
+// foldBlockLines pliega las líneas de un escalar '>': un salto entre dos
+// líneas normales se convierte en espacio, las líneas vacías en saltos y las
+// líneas más indentadas conservan sus saltos
+func foldBlockLines(lines []string) string {
+	var builder strings.Builder
+	moreIndented := func(line string) bool {
+		return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+	}
+	for i, line := range lines {
+		if i > 0 {
+			previous := lines[i-1]
+			switch {
+			case previous == "":
+				builder.WriteString("\n")
+			case line == "":
+				// El salto antes de las líneas vacías se descarta entre dos
+				// líneas normales; las vacías aportan un salto cada una
+				next := line
+				for _, candidate := range lines[i:] {
+					if candidate != "" {
+						next = candidate
+						break
+					}
+				}
+				if moreIndented(previous) || moreIndented(next) {
+					builder.WriteString("\n")
+				}
+			case moreIndented(line) || moreIndented(previous):
+				builder.WriteString("\n")
+			default:
+				builder.WriteString(" ")
+			}
+		}
+		builder.WriteString(line)
+	}
+	return builder.String()
+}
+
+// skipFlowBlank saltea espacios, saltos de línea y comentarios dentro de flow
+func (y *yamlParser) skipFlowBlank() {
+	for !y.eof() {
+		switch y.peek() {
+		case ' ', '\t', '\n', '\r':
+			y.pos++
+		case '#':
+			y.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// parseFlowNode parsea un nodo dentro de una colección flow ([...] o {...})
+func (y *yamlParser) parseFlowNode() (interface{}, error) {
+	y.skipFlowBlank()
+	nodesBefore := y.nodes
+	propsStart := y.pos
+	props := y.parseProperties()
+	y.skipFlowBlank()
+
+	var value interface{}
+	var err error
+	switch y.peek() {
+	case '[':
+		value, err = y.parseFlowSequence()
+	case '{':
+		value, err = y.parseFlowMapping()
+	case '*':
+		value, err = y.parseAlias()
+	case '"', '\'':
+		var scalar yamlScalar
+		if scalar, err = y.parseQuoted(); err == nil {
+			value, err = y.resolve(scalar, props.tag, propsStart)
+		}
+		y.nodes++
+	case ',', ']', '}':
+		// Nodo vacío (solo propiedades)
+		value, err = y.resolve(yamlScalar{plain: true}, props.tag, propsStart)
+	case 0:
+		return nil, y.errorf(y.pos, "colección flow sin cerrar")
+	default:
+		var scalar yamlScalar
+		if scalar, err = y.parsePlain(-1, true); err == nil {
+			value, err = y.resolve(scalar, props.tag, propsStart)
+		}
+		y.nodes++
+	}
+	if err != nil {
+		return nil, err
+	}
+	if props.tag == "!!map" || props.tag == "!!seq" {
+		if value, err = y.applyTag(props.tag, value, propsStart); err != nil {
+			return nil, err
+		}
+	}
+	return y.finish(props, value, nodesBefore), nil
+}
+
+// parseFlowSequence parsea [a, b, ...]
+func (y *yamlParser) parseFlowSequence() (interface{}, error) {
+	if err := y.enter(); err != nil {
+		return nil, err
+	}
+	y.pos++
+	items := []interface{}{}
+	y.nodes++
+	for {
+		y.skipFlowBlank()
+		if y.peek() == ']' {
+			y.pos++
+			break
+		}
+		item, err := y.parseFlowNode()
+		if err != nil {
+			return nil, err
+		}
+		y.skipFlowBlank()
+		if y.peek() == ':' {
+			return nil, y.errorf(y.pos, "los pares 'clave: valor' dentro de una secuencia flow no están soportados")
+		}
+		items = append(items, item)
+		switch y.peek() {
+		case ',':
+			y.pos++
+		case ']':
+		case 0:
+			return nil, y.errorf(y.pos, "secuencia flow sin cerrar: se esperaba ']'")
+		default:
+			return nil, y.errorf(y.pos, "se esperaba ',' o ']' en la secuencia flow")
+		}
+	}
+	y.depth--
+	return items, nil
+}
+
+// parseFlowMapping parsea {clave: valor, ...}; una clave sin ':' vale null
+func (y *yamlParser) parseFlowMapping() (interface{}, error) {
+	if err := y.enter(); err != nil {
+		return nil, err
+	}
+	y.pos++
+	object := y.newObject()
+	y.nodes++
+	for {
+		y.skipFlowBlank()
+		if y.peek() == '}' {
+			y.pos++
+			break
+		}
+		keyStart := y.pos
+		var key string
+		switch y.peek() {
+		case '"', '\'':
+			scalar, err := y.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			key = scalar.text
+		case '[', '{':
+			return nil, y.errorf(y.pos, "las claves complejas no están soportadas")
+		case 0:
+			return nil, y.errorf(y.pos, "mapeo flow sin cerrar: se esperaba '}'")
+		default:
+			scalar, err := y.parsePlain(-1, true)
+			if err != nil {
+				return nil, err
+			}
+			key = scalar.text
+		}
+
+		y.skipFlowBlank()
+		var value interface{}
+		if y.peek() == ':' {
+			y.pos++
+			y.skipFlowBlank()
+			if y.peek() != ',' && y.peek() != '}' {
+				var err error
+				if value, err = y.parseFlowNode(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := y.setKey(object, key, value, keyStart); err != nil {
+			return nil, err
+		}
+
+		y.skipFlowBlank()
+		switch y.peek() {
+		case ',':
+			y.pos++
+		case '}':
+		case 0:
+			return nil, y.errorf(y.pos, "mapeo flow sin cerrar: se esperaba '}'")
+		default:
+			return nil, y.errorf(y.pos, "se esperaba ',' o '}' en el mapeo flow")
+		}
+	}
+	y.depth--
+	return object, nil
+}
+
+// applyTag verifica las etiquetas de colección (!!map, !!seq)
+func (y *yamlParser) applyTag(tag string, value interface{}, offset int) (interface{}, error) {
+	switch normalizeYAMLTag(tag) {
+	case "!!map":
+		if objectValues(value) == nil {
+			return nil, y.errorf(offset, "el valor no es un !!map")
+		}
+	case "!!seq":
+		if _, ok := value.([]interface{}); !ok {
+			return nil, y.errorf(offset, "el valor no es una !!seq")
+		}
+	}
+	return value, nil
+}
+
+// normalizeYAMLTag convierte la forma verbatim de las etiquetas estándar
+func normalizeYAMLTag(tag string) string {
+	if strings.HasPrefix(tag, "!<tag:yaml.org,2002:") && strings.HasSuffix(tag, ">") {
+		return "!!" + strings.TrimSuffix(strings.TrimPrefix(tag, "!<tag:yaml.org,2002:"), ">")
+	}
+	return tag
+}
+
+// resolve convierte un escalar en valor: los planos siguen el esquema core
+// (null, bool, int, float) salvo que una etiqueta indique otro tipo
+func (y *yamlParser) resolve(scalar yamlScalar, tag string, offset int) (interface{}, error) {
+	tag = normalizeYAMLTag(tag)
+	switch tag {
+	case "!!str", "!", "!!binary", "!!timestamp":
+		return scalar.text, nil
+	case "!!map", "!!seq":
+		return nil, y.errorf(offset, "un escalar no puede tener la etiqueta %s", tag)
+	case "!!null", "!!bool", "!!int", "!!float":
+		value, kind, err := y.resolveCore(scalar.text, offset)
+		if err != nil {
+			return nil, err
+		}
+		if "!!"+kind != tag && !(tag == "!!float" && kind == "int") {
+			return nil, y.errorf(offset, "el valor '%s' no es un %s válido", scalar.text, tag)
+		}
+		return value, nil
+	}
+	if !scalar.plain {
+		return scalar.text, nil
+	}
+	value, _, err := y.resolveCore(scalar.text, offset)
+	return value, err
+}
+
+// resolveCore aplica el esquema core de YAML 1.2 a un escalar plano y
+// devuelve el valor con el nombre de su tipo
+func (y *yamlParser) resolveCore(text string, offset int) (interface{}, string, error) {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil, "null", nil
+	case "true", "True", "TRUE":
+		return true, "bool", nil
+	case "false", "False", "FALSE":
+		return false, "bool", nil
+	}
+
+	var integer *big.Int
+	switch {
+	case yamlIntPattern.MatchString(text):
+		integer, _ = new(big.Int).SetString(strings.TrimPrefix(text, "+"), 10)
+	case yamlOctalPattern.MatchString(text):
+		integer, _ = new(big.Int).SetString(text[2:], 8)
+	case yamlHexPattern.MatchString(text):
+		integer, _ = new(big.Int).SetString(text[2:], 16)
+	}
+	if integer != nil {
+		value, err := y.parser.convertNumberMode(integer.String(), y.opts.NumberMode)
+		return value, "int", err
+	}
+
+	if match := yamlFloatPattern.FindStringSubmatch(text); match != nil {
+		value, err := y.parser.convertNumberMode(jsonFloatLiteral(match), y.opts.NumberMode)
+		return value, "float", err
+	}
+	if yamlSpecialFloat.MatchString(text) {
+		return nil, "", y.errorf(offset, "el valor %s no tiene representación en JSON", text)
+	}
+	return text, "str", nil
+}
+
+// jsonFloatLiteral reescribe un float YAML (+1., .5, 007.5e3) como literal JSON
+func jsonFloatLiteral(match []string) string {
+	sign, mantissa, exponent := match[1], match[2], match[4]
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+
+	literal := integer
+	if sign == "-" {
+		literal = "-" + literal
+	}
+	if fraction != "" {
+		literal += "." + fraction
+	}
+	return literal + exponent
+}
+
+// MarshalYAML serializa un valor como YAML en estilo de bloque, con sangría
+// de dos espacios y las claves en el orden del objeto. Los strings que se
+// leerían como otro tipo (o que contienen indicadores) van entre comillas
+// dobles y los de varias líneas como escalares literales (|).
+func MarshalYAML(value interface{}) (string, error) {
+	var builder strings.Builder
+	if err := writeYAMLNode(&builder, value, 0, false); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// MarshalYAMLDocuments serializa cada valor como un documento separado por '---'
+func MarshalYAMLDocuments(values []interface{}) (string, error) {
+	var builder strings.Builder
+	for _, value := range values {
+		builder.WriteString("---")
+		if isContainer(value) {
+			builder.WriteString("\n")
+		} else {
+			builder.WriteString(" ")
+		}
+		if err := writeYAMLNode(&builder, value, 0, true); err != nil {
+			return "", err
+		}
+	}
+	return builder.String(), nil
+}
+
+// isEmptyContainer indica si value es un objeto o array sin elementos
+func isEmptyContainer(value interface{}) bool {
+	if items, ok := value.([]interface{}); ok {
+		return len(items) == 0
+	}
+	if members := objectValues(value); members != nil {
+		return len(members) == 0
+	}
+	return false
+}
+
+// writeYAMLNode escribe un nodo con la sangría indicada; inline indica que
+// la primera línea ya está posicionada (después de "- " o de "---")
+func writeYAMLNode(builder *strings.Builder, value interface{}, indent int, inline bool) error {
+	pad := strings.Repeat(" ", indent)
+	switch {
+	case isContainer(value) && objectValues(value) != nil:
+		members := objectValues(value)
+		for i, key := range orderedKeys(value) {
+			if i > 0 || !inline {
+				builder.WriteString(pad)
+			}
+			builder.WriteString(yamlString(key, true) + ":")
+			if err := writeYAMLValue(builder, members[key], indent); err != nil {
+				return err
+			}
+		}
+	case isContainer(value):
+		for i, item := range value.([]interface{}) {
+			if i > 0 || !inline {
+				builder.WriteString(pad)
+			}
+			builder.WriteString("-")
+			if isContainer(item) {
+				builder.WriteString(" ")
+				if err := writeYAMLNode(builder, item, indent+2, true); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := writeYAMLValue(builder, item, indent); err != nil {
+				return err
+			}
+		}
+	default:
+		if !inline {
+			builder.WriteString(pad)
+		}
+		text, err := yamlScalarText(value, indent)
+		if err != nil {
+			return err
+		}
+		builder.WriteString(text + "\n")
+	}
+	return nil
+}
+
+// writeYAMLValue escribe el valor de una clave o de un elemento después de ':' o '-'
+func writeYAMLValue(builder *strings.Builder, value interface{}, indent int) error {
+	if isContainer(value) {
+		builder.WriteString("\n")
+		return writeYAMLNode(builder, value, indent+2, false)
+	}
+	text, err := yamlScalarText(value, indent+2)
+	if err != nil {
+		return err
+	}
+	builder.WriteString(" " + text + "\n")
+	return nil
+}
+
+// yamlScalarText escribe un escalar (o colección vacía) en una línea; los
+// strings de varias líneas usan un bloque literal indentado en indent
+func yamlScalarText(value interface{}, indent int) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		if block, ok := yamlLiteralBlock(v, indent); ok {
+			return block, nil
+		}
+		return yamlString(v, false), nil
+	}
+	if isEmptyContainer(value) {
+		if _, ok := value.([]interface{}); ok {
+			return "[]", nil
+		}
+		return "{}", nil
+	}
+	data, err := Marshal(value)
+	return string(data), err
+}
+
+// yamlString escribe un string plano si se lee igual, o entre comillas dobles
+func yamlString(text string, key bool) string {
+	if yamlPlainSafe(text, key) {
+		return text
+	}
+	data, _ := Marshal(text)
+	return string(data)
+}
+
+// yamlPlainSafe indica si text se puede escribir sin comillas y se vuelve a
+// leer como el mismo string
+func yamlPlainSafe(text string, key bool) bool {
+	if text == "" || text != strings.TrimSpace(text) || strings.HasPrefix(text, "---") || strings.HasPrefix(text, "...") {
+		return false
+	}
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(text[0])) {
+		return false
+	}
+	if strings.Contains(text, ": ") || strings.Contains(text, " #") || strings.HasSuffix(text, ":") {
+		return false
+	}
+	for _, r := range text {
+		if r < 0x20 || r == 0x7F || r == '\uFEFF' || r == utf8.RuneError {
+			return false
+		}
+	}
+	y := &yamlParser{parser: globalParser}
+	if value, kind, err := y.resolveCore(text, 0); err != nil || kind != "str" || value != text {
+		return false
+	}
+	return true
+}
+
+// yamlLiteralBlock escribe un string de varias líneas como bloque literal si
+// el resultado se lee igual
+func yamlLiteralBlock(text string, indent int) (string, bool) {
+	body := strings.TrimRight(text, "\n")
+	if !strings.Contains(text, "\n") || body == "" || strings.HasPrefix(body, " ") || strings.HasPrefix(body, "\n") {
+		return "", false
+	}
+	for _, r := range body {
+		if (r < 0x20 && r != '\n' && r != '\t') || r == 0x7F || r == '\uFEFF' || r == utf8.RuneError {
+			return "", false
+		}
+	}
+	// Las líneas de solo espacios se confundirían con el final del bloque y
+	// los marcadores de documento lo cortarían
+	for _, line := range strings.Split(body, "\n") {
+		if (line != "" && strings.TrimSpace(line) == "") || strings.HasPrefix(line, "---") || strings.HasPrefix(line, "...") {
+			return "", false
+		}
+	}
+
+	header := "|-"
+	switch len(text) - len(body) {
+	case 0:
+	case 1:
+		header = "|"
+	default:
+		header = "|+"
+	}
+
+	var builder strings.Builder
+	builder.WriteString(header)
+	pad := strings.Repeat(" ", indent)
+	lines := strings.Split(body, "\n")
+	if header == "|+" {
+		lines = strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	}
+	for _, line := range lines {
+		builder.WriteString("\n")
+		if line != "" {
+			builder.WriteString(pad + line)
+		}
+	}
+	return builder.String(), true
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// parseYAMLExact parsea YAML conservando el orden y los literales numéricos
+func parseYAMLExact(t *testing.T, input string) interface{} {
+	t.Helper()
+	value, err := NewParser().ParseYAML(input, ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	if err != nil {
+		t.Fatalf("ParseYAML(%q) error = %v", input, err)
+	}
+	return value
+}
+
+// Test para el parseo de YAML a la misma estructura que JSON
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"vacío", "", `null`},
+		{"solo comentarios", "# nada\n\n", `null`},
+		{"escalar", "hola mundo", `"hola mundo"`},
+		{"mapeo", "nombre: Ana\nedad: 30\nactivo: true\nnotas: ~\n", `{"nombre":"Ana","edad":30,"activo":true,"notas":null}`},
+		{"anidado", "a:\n  b:\n    c: 1\n  d: 2\ne: 3\n", `{"a":{"b":{"c":1},"d":2},"e":3}`},
+		{"secuencia", "- uno\n- 2\n- false\n", `["uno",2,false]`},
+		{"secuencia bajo clave sin sangría", "items:\n- a\n- b\notro: x\n", `{"items":["a","b"],"otro":"x"}`},
+		{"secuencia de mapeos", "- nombre: a\n  valor: 1\n- nombre: b\n", `[{"nombre":"a","valor":1},{"nombre":"b"}]`},
+		{"secuencias anidadas", "- - a\n  - b\n- - c\n", `[["a","b"],["c"]]`},
+		{"flow", "{a: [1, 2, {b: c}], d: 'x, y', e: }", `{"a":[1,2,{"b":"c"}],"d":"x, y","e":null}`},
+		{"flow multilínea", "lista: [\n  a,\n  b,\n]\n", `{"lista":["a","b"]}`},
+		{"colecciones vacías", "a: []\nb: {}\n", `{"a":[],"b":{}}`},
+		{"comentarios", "a: 1 # uno\n# suelto\nb: x#y\n", `{"a":1,"b":"x#y"}`},
+		{"claves no string", "1: uno\ntrue: si\nnull: nada\n", `{"1":"uno","true":"si","null":"nada"}`},
+		{"url", "url: http://ejemplo.com:8080/a", `{"url":"http://ejemplo.com:8080/a"}`},
+		{"plano multilínea", "texto: una\n  frase\n\n  larga\n", `{"texto":"una frase\nlarga"}`},
+		{"comillas simples", "a: 'it''s'\nb: '1'\n", `{"a":"it's","b":"1"}`},
+		{"comillas dobles", `a: "tab\there \u00e9 \x41 \"q\""`, `{"a":"tab\there é A \"q\""}`},
+		{"comillas multilínea", "a: \"uno\n  dos\\\n  tres\"\n", `{"a":"uno dostres"}`},
+		{"literal", "a: |\n  línea 1\n    sangría\n  línea 3\nb: 1\n", `{"a":"línea 1\n  sangría\nlínea 3\n","b":1}`},
+		{"literal strip", "a: |-\n  x\n\n", `{"a":"x"}`},
+		{"literal keep", "a: |+\n  x\n\n", `{"a":"x\n\n"}`},
+		{"literal sangría explícita", "a: |2\n   x\n  y\n", `{"a":" x\ny\n"}`},
+		{"plegado", "a: >\n  uno\n  dos\n\n  tres\n    más\n  fin\n", `{"a":"uno dos\ntres\n  más\nfin\n"}`},
+		{"enteros", "- 0o17\n- 0x1F\n- +12\n- -0\n- 123456789012345678901234567890\n", `[15,31,12,0,123456789012345678901234567890]`},
+		{"floats", "- 1.5\n- .5\n- -2.\n- 1e3\n- 007.25\n", `[1.5,0.5,-2,1e3,7.25]`},
+		{"no números", "- 1_000\n- 0b11\n- 12:30\n- .\n", `["1_000","0b11","12:30","."]`},
+		{"etiquetas", "- !!str 123\n- !!float 1\n- !!int \"7\"\n- !custom x\n", `["123",1,7,"x"]`},
+		{"anclas y alias", "base: &b {x: 1}\ncopia: *b\nlista: &l\n  - 1\notra: *l\n", `{"base":{"x":1},"copia":{"x":1},"lista":[1],"otra":[1]}`},
+		{"alias de una clave", "&k a: 1\nb: *k\n", `{"a":1,"b":"a"}`},
+		{"documento explícito", "%YAML 1.2\n---\na: 1\n...\n", `{"a":1}`},
+		{"tabs como separación", "a:\t1\n\t\nb: [1,\n\t2]\nc: x\n   \ty\n", `{"a":1,"b":[1,2],"c":"x y"}`},
+		{"bom y crlf", "\uFEFFa: 1\r\nb:\r\n  - x\r\n", `{"a":1,"b":["x"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(mustMarshal(t, parseYAMLExact(t, tt.input)))
+			if got != tt.want {
+				t.Errorf("ParseYAML() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test: con las opciones por defecto YAML y JSON producen el mismo árbol
+func TestParseYAMLMatchesJSON(t *testing.T) {
+	yaml := "usuarios:\n  - nombre: Ana\n    edad: 30\n    tags: [a, b]\n  - nombre: Luis\n    edad: 41.5\n    tags: []\nactivo: true\nvacío: null\n"
+	json := `{"usuarios":[{"nombre":"Ana","edad":30,"tags":["a","b"]},{"nombre":"Luis","edad":41.5,"tags":[]}],"activo":true,"vacío":null}`
+
+	fromYAML, err := NewParser().ParseYAML(yaml, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseYAML() error = %v", err)
+	}
+	fromJSON, err := NewParser().ParseJSONWithOptions(json, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseJSONWithOptions() error = %v", err)
+	}
+	if !EqualValues(fromYAML, fromJSON) {
+		t.Errorf("ParseYAML() = %#v, want %#v", fromYAML, fromJSON)
+	}
+	if _, ok := fromYAML.(map[string]interface{})["usuarios"].([]interface{})[0].(map[string]interface{})["edad"].(float64); !ok {
+		t.Error("se esperaba float64 con NumberAsFloat64")
+	}
+}
+
+// Test para flujos con varios documentos
+func TestParseYAMLDocuments(t *testing.T) {
+	docs, err := NewParser().ParseYAMLDocuments("a: 1\n---\n- x\n--- texto\n---\n...\n---\n{}\n", ParseOptions{PreserveOrder: true, NumberMode: NumberAsNumber})
+	if err != nil {
+		t.Fatalf("ParseYAMLDocuments() error = %v", err)
+	}
+	if got := string(mustMarshal(t, docs)); got != `[{"a":1},["x"],"texto",null,{}]` {
+		t.Errorf("ParseYAMLDocuments() = %s", got)
+	}
+
+	if _, err := NewParser().ParseYAML("a: 1\n---\nb: 2\n", ParseOptions{}); err == nil || !strings.Contains(err.Error(), "2 documentos") {
+		t.Errorf("ParseYAML() con dos documentos error = %v", err)
+	}
+}
+
+// Test para los errores de sintaxis con posición
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name, input, wantErr string
+		line, column         int
+	}{
+		{"clave duplicada", "a: 1\nb: 2\na: 3\n", "clave duplicada: a", 3, 1},
+		{"sangría", "a: 1\n   b: 2\n", "sangría inesperada", 2, 4},
+		{"tab", "a:\n\tb: 1\n", "no se permiten tabs", 2, 1},
+		{"tab en la raíz", "\ta: 1\n", "no se permiten tabs", 1, 1},
+		{"tab tras espacios", "a:\n  b: 1\n \tc: 2\n", "no se permiten tabs", 3, 2},
+		{"tab en secuencia", "- a\n\t- b\n", "no se permiten tabs", 2, 1},
+		{"tab en escalar de bloque", "a: |\n\tx\n", "no se permiten tabs", 2, 1},
+		{"alias sin ancla", "a: *nada\n", "alias '*nada' sin ancla", 1, 4},
+		{"comillas sin cerrar", "a: \"abierto\n", "sin cerrar", 1, 4},
+		{"flow sin cerrar", "a: [1, 2\n", "secuencia flow sin cerrar", 2, 1},
+		{"mapeo en línea de clave", "a: b: c\n", "no se permite un mapeo de bloque", 1, 4},
+		{"secuencia en línea de clave", "a: - b\n", "secuencia de bloque no puede", 1, 4},
+		{"escape inválido", `a: "\q"`, "secuencia de escape inválida", 1, 5},
+		{"infinito", "a: .inf\n", "no tiene representación en JSON", 1, 4},
+		{"etiqueta incompatible", "a: !!int hola\n", "no es un !!int válido", 1, 4},
+		{"contenido extra", "- a\nb: 1\n", "contenido inesperado", 2, 1},
+		{"reservado", "a: @x\n", "está reservado", 1, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser().ParseYAML(tt.input, ParseOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseYAML() error = %v, want %q", err, tt.wantErr)
+			}
+			syntaxErr := AsSyntaxError(err)
+			if syntaxErr == nil || syntaxErr.Line != tt.line || syntaxErr.Column != tt.column {
+				t.Errorf("posición = %+v, want %d:%d", syntaxErr, tt.line, tt.column)
+			}
+		})
+	}
+}
+
+// Test: la expansión de alias está limitada ("billion laughs")
+func TestParseYAMLAliasLimit(t *testing.T) {
+	var builder strings.Builder
+	builder.WriteString("a0: &a0 [x, x, x, x, x, x, x, x, x, x]\n")
+	for i := 1; i < 9; i++ {
+		alias := fmt.Sprintf("*a%d", i-1)
+		fmt.Fprintf(&builder, "a%d: &a%d [%s]\n", i, i, strings.TrimSuffix(strings.Repeat(alias+", ", 10), ", "))
+	}
+	_, err := NewParser().ParseYAML(builder.String(), ParseOptions{})
+	if err == nil || !strings.Contains(err.Error(), "supera el límite") {
+		t.Errorf("ParseYAML() error = %v, want límite de expansión", err)
+	}
+}
+
+// Test para la serialización a YAML y su lectura de vuelta
+func TestMarshalYAML(t *testing.T) {
+	tests := []struct {
+		name, json, want string
+	}{
+		{"escalar", `"hola"`, "hola\n"},
+		{"vacíos", `{"a":{},"b":[]}`, "a: {}\nb: []\n"},
+		{"anidado", `{"a":{"b":[1,{"c":null,"d":[true,[]]}],"e":"x"}}`, "a:\n  b:\n    - 1\n    - c: null\n      d:\n        - true\n        - []\n  e: x\n"},
+		{"secuencias anidadas", `[[1,2],["a"]]`, "- - 1\n  - 2\n- - a\n"},
+		{"strings ambiguos", `["true","1.5","null","","- x","a: b","a #b"," sp","0x1F"]`, "- \"true\"\n- \"1.5\"\n- \"null\"\n- \"\"\n- \"- x\"\n- \"a: b\"\n- \"a #b\"\n- \" sp\"\n- \"0x1F\"\n"},
+		{"claves", `{"1":"x","con espacio":"y","a:b":"z"}`, "\"1\": x\ncon espacio: y\na:b: z\n"},
+		{"multilínea", `{"a":"uno\ndos\n","b":"x\ny","c":"p\n\n"}`, "a: |\n  uno\n  dos\nb: |-\n  x\n  y\nc: |+\n  p\n\n"},
+		{"controles", `["tab\there","\u001b","cr\r\nlf"]`, "- \"tab\\there\"\n- \"\\u001b\"\n- \"cr\\r\\nlf\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := parseExact(t, tt.json)
+			got, err := MarshalYAML(value)
+			if err != nil {
+				t.Fatalf("MarshalYAML() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MarshalYAML() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if back := parseYAMLExact(t, got); !EqualValues(back, value) {
+				t.Errorf("ParseYAML(MarshalYAML()) = %s, want %s", mustMarshal(t, back), tt.json)
+			}
+		})
+	}
+
+	docs, err := MarshalYAMLDocuments([]interface{}{parseExact(t, `{"a":1}`), "x", parseExact(t, `[]`)})
+	if err != nil || docs != "---\na: 1\n--- x\n--- []\n" {
+		t.Errorf("MarshalYAMLDocuments() = %q, %v", docs, err)
+	}
+}